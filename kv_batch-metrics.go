@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/batch-metrics.go --fake-name BatchMetrics . BatchMetrics
+
+// BatchMetrics provides monitoring of batched write transactions using Prometheus.
+type BatchMetrics interface {
+	DbBatchSizeObserve(size int)
+	DbBatchFallbackInc()
+}
+
+// NewBatchMetrics creates a new BatchMetrics instance with default Prometheus collectors.
+func NewBatchMetrics() BatchMetrics {
+	return &batchMetrics{}
+}
+
+type batchMetrics struct {
+}
+
+func (m *batchMetrics) DbBatchSizeObserve(size int) {
+	dbBatchSizeHistogram.Observe(float64(size))
+}
+
+func (m *batchMetrics) DbBatchFallbackInc() {
+	dbBatchFallbackCounter.Inc()
+}
+
+var (
+	dbBatchSizeHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "batch_size",
+		Help:      "Number of closures combined into one batch transaction",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	dbBatchFallbackCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "batch_fallback_total",
+		Help:      "Counts closures re-run in their own transaction after the batch failed",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbBatchSizeHistogram,
+		dbBatchFallbackCounter,
+	)
+}
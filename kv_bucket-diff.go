@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// diffBuckets iterates both buckets in key order and calls fn for each key
+// with different values. A nil bucket is empty, a nil value means the key is missing.
+// The values are only valid within fn.
+func diffBuckets(
+	ctx context.Context,
+	a Bucket,
+	b Bucket,
+	fn func(key []byte, aValue []byte, bValue []byte) error,
+) error {
+	return diffBucketsAfter(ctx, a, b, nil, fn)
+}
+
+// diffBucketsAfter is diffBuckets starting with the first key after the given key,
+// a nil key starts at the beginning.
+func diffBucketsAfter(
+	ctx context.Context,
+	a Bucket,
+	b Bucket,
+	after []byte,
+	fn func(key []byte, aValue []byte, bValue []byte) error,
+) error {
+	aIterator := diffIterator(a)
+	defer aIterator.Close()
+	bIterator := diffIterator(b)
+	defer bIterator.Close()
+	diffSeek(aIterator, after)
+	diffSeek(bIterator, after)
+	for aIterator.Valid() || bIterator.Valid() {
+		compare := diffCompare(aIterator, bIterator)
+		var key, aValue, bValue []byte
+		var err error
+		if compare <= 0 {
+			key = bytes.Clone(aIterator.Item().Key())
+			if aValue, err = itemValue(aIterator.Item()); err != nil {
+				return errors.Wrapf(ctx, err, "get value of %s failed", key)
+			}
+		}
+		if compare >= 0 {
+			key = bytes.Clone(bIterator.Item().Key())
+			if bValue, err = itemValue(bIterator.Item()); err != nil {
+				return errors.Wrapf(ctx, err, "get value of %s failed", key)
+			}
+		}
+		if compare <= 0 {
+			aIterator.Next()
+		}
+		if compare >= 0 {
+			bIterator.Next()
+		}
+		if bytes.Equal(aValue, bValue) {
+			continue
+		}
+		if err := fn(key, aValue, bValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffSeek moves the iterator to the first key after the given key.
+func diffSeek(iterator Iterator, after []byte) {
+	if after == nil {
+		iterator.Rewind()
+		return
+	}
+	iterator.Seek(after)
+	if iterator.Valid() && bytes.Equal(iterator.Item().Key(), after) {
+		iterator.Next()
+	}
+}
+
+// diffCompare compares the current keys, an invalid iterator is behind all keys.
+func diffCompare(a Iterator, b Iterator) int {
+	if !a.Valid() {
+		return 1
+	}
+	if !b.Valid() {
+		return -1
+	}
+	return bytes.Compare(a.Item().Key(), b.Item().Key())
+}
+
+func diffIterator(bucket Bucket) Iterator {
+	if bucket == nil {
+		return &emptyIterator{}
+	}
+	return bucket.Iterator()
+}
+
+// emptyIterator is the iterator of a missing bucket.
+type emptyIterator struct{}
+
+func (e *emptyIterator) Close() {
+}
+
+func (e *emptyIterator) Item() Item {
+	return nil
+}
+
+func (e *emptyIterator) Next() {
+}
+
+func (e *emptyIterator) Valid() bool {
+	return false
+}
+
+func (e *emptyIterator) Rewind() {
+}
+
+func (e *emptyIterator) Seek(key []byte) {
+}
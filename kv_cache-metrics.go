@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/cache-metrics.go --fake-name CacheMetrics . CacheMetrics
+
+// CacheMetrics provides monitoring of the read cache using Prometheus.
+type CacheMetrics interface {
+	DbCacheHitInc()
+	DbCacheMissInc()
+}
+
+// NewCacheMetrics creates a new CacheMetrics instance with default Prometheus counters.
+func NewCacheMetrics() CacheMetrics {
+	return &cacheMetrics{}
+}
+
+type cacheMetrics struct {
+}
+
+func (m *cacheMetrics) DbCacheHitInc() {
+	dbCacheHitCounter.Inc()
+}
+
+func (m *cacheMetrics) DbCacheMissInc() {
+	dbCacheMissCounter.Inc()
+}
+
+var (
+	dbCacheHitCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "cache_hit_total",
+		Help:      "Counts bucket gets served from the cache",
+	})
+	dbCacheMissCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "cache_miss_total",
+		Help:      "Counts bucket gets not found in the cache",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbCacheHitCounter,
+		dbCacheMissCounter,
+	)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// CachedStoreOptions configures NewCachedStore.
+// Unset MaxEntries and TTL are replaced by the defaults of DefaultCachedStoreOptions.
+type CachedStoreOptions[OBJECT any] struct {
+	// MaxEntries is the maximum number of cached objects.
+	MaxEntries int
+	// MaxBytes is the maximum sum of Size of all cached objects, zero disables it.
+	// It is only applied if Size is set.
+	MaxBytes int
+	// Size estimates the memory used by an object for MaxBytes.
+	Size func(object OBJECT) int
+	// TTL is the maximum time an object is served from the cache.
+	TTL time.Duration
+	// Clone copies an object before it is cached and before it is returned.
+	// By default only the object itself is copied, maps, slices and pointers in it
+	// are shared by all callers and must not be modified. Set it for deep copies.
+	Clone func(object OBJECT) OBJECT
+}
+
+// DefaultCachedStoreOptions returns the default CachedStoreOptions.
+func DefaultCachedStoreOptions[OBJECT any]() CachedStoreOptions[OBJECT] {
+	return CachedStoreOptions[OBJECT]{
+		MaxEntries: 1000,
+		TTL:        time.Minute,
+	}
+}
+
+// CachedStore is a Store that keeps decoded objects in memory.
+type CachedStore[KEY ~[]byte | ~string, OBJECT any] interface {
+	Store[KEY, OBJECT]
+	// Preload reads all objects of the bucket into the cache, up to MaxEntries.
+	Preload(ctx context.Context) error
+	// Purge removes all objects from the cache.
+	Purge()
+}
+
+// NewCachedStore returns a Store caching the objects returned by Get.
+// Add and Remove invalidate the key after the transaction finished.
+// Writes to the bucket that bypass the CachedStore are visible after TTL.
+func NewCachedStore[KEY ~[]byte | ~string, OBJECT any](
+	db DB,
+	bucketName BucketName,
+	options CachedStoreOptions[OBJECT],
+) CachedStore[KEY, OBJECT] {
+	defaults := DefaultCachedStoreOptions[OBJECT]()
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = defaults.MaxEntries
+	}
+	if options.TTL <= 0 {
+		options.TTL = defaults.TTL
+	}
+	if options.Clone == nil {
+		options.Clone = func(object OBJECT) OBJECT {
+			return object
+		}
+	}
+	maxBytes := 0
+	if options.Size != nil {
+		maxBytes = options.MaxBytes
+	}
+	return &cachedStore[KEY, OBJECT]{
+		Store:   NewStore[KEY, OBJECT](db, bucketName),
+		options: options,
+		cache:   newLRUCache[cachedStoreEntry[OBJECT]](options.MaxEntries, maxBytes),
+	}
+}
+
+type cachedStore[KEY ~[]byte | ~string, OBJECT any] struct {
+	Store[KEY, OBJECT]
+	options CachedStoreOptions[OBJECT]
+	cache   *lruCache[cachedStoreEntry[OBJECT]]
+
+	// mux guards generation, which is incremented by each invalidation
+	mux        sync.Mutex
+	generation uint64
+}
+
+type cachedStoreEntry[OBJECT any] struct {
+	object  OBJECT
+	expires time.Time
+}
+
+func (c *cachedStore[KEY, OBJECT]) Add(ctx context.Context, key KEY, object OBJECT) error {
+	defer c.invalidate(key)
+	return c.Store.Add(ctx, key, object)
+}
+
+func (c *cachedStore[KEY, OBJECT]) Remove(ctx context.Context, key KEY) error {
+	defer c.invalidate(key)
+	return c.Store.Remove(ctx, key)
+}
+
+func (c *cachedStore[KEY, OBJECT]) Get(ctx context.Context, key KEY) (*OBJECT, error) {
+	if object, ok := c.get(key); ok {
+		return object, nil
+	}
+	generation := c.currentGeneration()
+	object, err := c.Store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.add(generation, key, *object)
+	return object, nil
+}
+
+func (c *cachedStore[KEY, OBJECT]) Exists(ctx context.Context, key KEY) (bool, error) {
+	if _, ok := c.lookup(key); ok {
+		return true, nil
+	}
+	return c.Store.Exists(ctx, key)
+}
+
+func (c *cachedStore[KEY, OBJECT]) Preload(ctx context.Context) error {
+	generation := c.currentGeneration()
+	var count int
+	err := c.Store.Map(ctx, func(ctx context.Context, key KEY, object OBJECT) error {
+		if count >= c.options.MaxEntries {
+			return nil
+		}
+		c.add(generation, key, object)
+		count++
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "preload failed")
+	}
+	return nil
+}
+
+func (c *cachedStore[KEY, OBJECT]) Purge() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.generation++
+	c.cache.Purge()
+}
+
+// get returns a copy of the cached object.
+func (c *cachedStore[KEY, OBJECT]) get(key KEY) (*OBJECT, bool) {
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	object := c.options.Clone(entry.object)
+	return &object, true
+}
+
+// lookup returns the cached entry if it is not expired.
+func (c *cachedStore[KEY, OBJECT]) lookup(key KEY) (cachedStoreEntry[OBJECT], bool) {
+	entry, ok := c.cache.Get(string(key))
+	if !ok {
+		return entry, false
+	}
+	if time.Now().After(entry.expires) {
+		c.cache.Remove(string(key))
+		return entry, false
+	}
+	return entry, true
+}
+
+func (c *cachedStore[KEY, OBJECT]) currentGeneration() uint64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.generation
+}
+
+// add caches a copy of object read after generation. The object is dropped if
+// the cache was invalidated since, it might have been read before the last commit.
+func (c *cachedStore[KEY, OBJECT]) add(generation uint64, key KEY, object OBJECT) {
+	size := 0
+	if c.options.Size != nil {
+		size = c.options.Size(object)
+	}
+	clone := c.options.Clone(object)
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.generation != generation {
+		return
+	}
+	c.cache.Add(string(key), cachedStoreEntry[OBJECT]{
+		object:  clone,
+		expires: time.Now().Add(c.options.TTL),
+	}, size)
+}
+
+func (c *cachedStore[KEY, OBJECT]) invalidate(key KEY) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.generation++
+	c.cache.Remove(string(key))
+}
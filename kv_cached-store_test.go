@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("CachedStore", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var db *mocks.DB
+	var options kv.CachedStoreOptions[TestObject]
+	var bucketName kv.BucketName
+	var store kv.CachedStore[string, TestObject]
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		db = &mocks.DB{}
+		db.ViewStub = backend.View
+		db.UpdateStub = backend.Update
+		bucketName = kv.NewBucketName("objects")
+		options = kv.CachedStoreOptions[TestObject]{
+			MaxEntries: 10,
+			TTL:        time.Hour,
+		}
+		raw := kv.NewStore[string, TestObject](backend, bucketName)
+		Expect(raw.Add(ctx, "john", TestObject{Name: "John", Age: 30})).To(Succeed())
+		Expect(raw.Add(ctx, "jane", TestObject{Name: "Jane", Age: 40})).To(Succeed())
+	})
+	JustBeforeEach(func() {
+		store = kv.NewCachedStore[string, TestObject](db, bucketName, options)
+	})
+	It("decodes each object once", func() {
+		object, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+		object, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+		exists, err := store.Exists(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeTrue())
+		Expect(db.ViewCallCount()).To(Equal(1))
+	})
+	It("returns copies of cached objects", func() {
+		object, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		object.Name = "changed"
+		object, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+		object.Name = "changed"
+		object, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+	})
+	It("returns deep copies with a deep clone", func() {
+		raw := kv.NewStore[string, map[string][]string](backend, bucketName)
+		Expect(raw.Add(ctx, "tags", map[string][]string{"a": {"b"}})).To(Succeed())
+		store := kv.NewCachedStore[string, map[string][]string](
+			db,
+			bucketName,
+			kv.CachedStoreOptions[map[string][]string]{
+				Clone: func(object map[string][]string) map[string][]string {
+					result := make(map[string][]string, len(object))
+					for key, values := range object {
+						result[key] = append([]string{}, values...)
+					}
+					return result
+				},
+			},
+		)
+		object, err := store.Get(ctx, "tags")
+		Expect(err).To(BeNil())
+		(*object)["a"][0] = "changed"
+		(*object)["c"] = nil
+		object, err = store.Get(ctx, "tags")
+		Expect(err).To(BeNil())
+		Expect(*object).To(Equal(map[string][]string{"a": {"b"}}))
+	})
+	It("uses the given clone", func() {
+		var cloned int
+		options.Clone = func(object TestObject) TestObject {
+			cloned++
+			return object
+		}
+		store = kv.NewCachedStore[string, TestObject](db, bucketName, options)
+		_, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		_, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(cloned).To(Equal(2))
+		exists, err := store.Exists(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeTrue())
+		Expect(cloned).To(Equal(2))
+	})
+	It("evicts objects above max bytes", func() {
+		options.MaxBytes = 5
+		options.Size = func(object TestObject) int {
+			return len(object.Name)
+		}
+		store = kv.NewCachedStore[string, TestObject](db, bucketName, options)
+		_, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		_, err = store.Get(ctx, "jane")
+		Expect(err).To(BeNil())
+		Expect(db.ViewCallCount()).To(Equal(2))
+		_, err = store.Get(ctx, "jane")
+		Expect(err).To(BeNil())
+		Expect(db.ViewCallCount()).To(Equal(2))
+		_, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(db.ViewCallCount()).To(Equal(3))
+	})
+	It("invalidates on add and remove", func() {
+		_, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(store.Add(ctx, "john", TestObject{Name: "Johnny"})).To(Succeed())
+		object, err := store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("Johnny"))
+		Expect(store.Remove(ctx, "john")).To(Succeed())
+		_, err = store.Get(ctx, "john")
+		Expect(err).NotTo(BeNil())
+		exists, err := store.Exists(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeFalse())
+	})
+	Context("with short ttl", func() {
+		BeforeEach(func() {
+			options.TTL = 10 * time.Millisecond
+		})
+		It("reloads expired objects", func() {
+			_, err := store.Get(ctx, "john")
+			Expect(err).To(BeNil())
+			raw := kv.NewStore[string, TestObject](backend, bucketName)
+			Expect(raw.Add(ctx, "john", TestObject{Name: "Johnny"})).To(Succeed())
+			Eventually(func() string {
+				object, err := store.Get(ctx, "john")
+				Expect(err).To(BeNil())
+				return object.Name
+			}).Should(Equal("Johnny"))
+		})
+	})
+	It("preloads the bucket", func() {
+		Expect(store.Preload(ctx)).To(Succeed())
+		Expect(db.ViewCallCount()).To(Equal(1))
+		object, err := store.Get(ctx, "jane")
+		Expect(err).To(BeNil())
+		Expect(object.Age).To(Equal(40))
+		_, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(db.ViewCallCount()).To(Equal(1))
+
+		store.Purge()
+		_, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(db.ViewCallCount()).To(Equal(2))
+	})
+})
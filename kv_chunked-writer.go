@@ -0,0 +1,261 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ChunkedWriteOperation is a single Put or Delete written by ChunkedWriter.
+type ChunkedWriteOperation struct {
+	BucketName BucketName
+	Key        []byte
+	Value      []byte
+	// Delete deletes Key instead of writing Value.
+	Delete bool
+	// Checkpoint is an optional position of the operation in its source and must be
+	// ascending. With ChunkedWriterOptions.CheckpointKey set, the checkpoint of the last
+	// operation of each chunk is stored and already written operations are skipped on resume.
+	Checkpoint []byte
+}
+
+func (c ChunkedWriteOperation) size() int {
+	return len(c.BucketName) + len(c.Key) + len(c.Value)
+}
+
+// ChunkedWriterOptions configures NewChunkedWriter.
+// Unset MaxOperations and MaxBytes are replaced by the defaults of DefaultChunkedWriterOptions.
+type ChunkedWriterOptions struct {
+	// MaxOperations is the maximum number of operations per transaction.
+	MaxOperations int
+	// MaxBytes is the maximum size of keys and values per transaction.
+	// A single larger operation is written in its own transaction.
+	MaxBytes int
+	// CheckpointBucketName is the bucket of the checkpoint.
+	CheckpointBucketName BucketName
+	// CheckpointKey enables checkpoints if set.
+	CheckpointKey []byte
+}
+
+// DefaultChunkedWriterOptions returns the default ChunkedWriterOptions without checkpoint.
+func DefaultChunkedWriterOptions() ChunkedWriterOptions {
+	return ChunkedWriterOptions{
+		MaxOperations: 1000,
+		MaxBytes:      4 * 1024 * 1024,
+	}
+}
+
+// ChunkedWriteSummary describes the chunks committed by ChunkedWriter.Write.
+type ChunkedWriteSummary struct {
+	// Chunks is the number of committed transactions.
+	Chunks int
+	// Operations is the number of committed operations.
+	Operations int
+	// Bytes is the size of keys and values of all committed operations.
+	Bytes int
+	// Skipped is the number of operations skipped because of the checkpoint.
+	Skipped int
+	// Checkpoint is the checkpoint of the last committed operation.
+	Checkpoint []byte
+}
+
+//counterfeiter:generate -o mocks/chunked-writer.go --fake-name ChunkedWriter . ChunkedWriter
+
+// ChunkedWriter writes a stream of operations in multiple transactions to stay
+// below the transaction size limits of the backend.
+type ChunkedWriter interface {
+	// Write reads operations until ch is closed and commits them in chunks.
+	// On error the summary contains all chunks committed before.
+	Write(ctx context.Context, ch <-chan ChunkedWriteOperation) (*ChunkedWriteSummary, error)
+	// Checkpoint returns the stored checkpoint or nil if none exists.
+	Checkpoint(ctx context.Context) ([]byte, error)
+}
+
+// NewChunkedWriter returns a ChunkedWriter writing to the given DB.
+// It fails if CheckpointKey is set without CheckpointBucketName.
+func NewChunkedWriter(db DB, options ChunkedWriterOptions) (ChunkedWriter, error) {
+	if len(options.CheckpointKey) > 0 && len(options.CheckpointBucketName) == 0 {
+		return nil, errors.Errorf(
+			context.Background(),
+			"checkpoint key %q without checkpoint bucket name",
+			options.CheckpointKey,
+		)
+	}
+	defaults := DefaultChunkedWriterOptions()
+	if options.MaxOperations <= 0 {
+		options.MaxOperations = defaults.MaxOperations
+	}
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = defaults.MaxBytes
+	}
+	return &chunkedWriter{
+		db:      db,
+		options: options,
+	}, nil
+}
+
+type chunkedWriter struct {
+	db      DB
+	options ChunkedWriterOptions
+}
+
+func (c *chunkedWriter) Write(
+	ctx context.Context,
+	ch <-chan ChunkedWriteOperation,
+) (*ChunkedWriteSummary, error) {
+	checkpoint, err := c.Checkpoint(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read checkpoint failed")
+	}
+	summary := &ChunkedWriteSummary{
+		Checkpoint: checkpoint,
+	}
+	var chunk []ChunkedWriteOperation
+	var chunkBytes int
+	var last []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return summary, ctx.Err()
+		case operation, ok := <-ch:
+			if !ok {
+				if err := c.writeChunk(ctx, chunk, summary); err != nil {
+					return summary, err
+				}
+				return summary, nil
+			}
+			if len(operation.Checkpoint) > 0 {
+				if last != nil && bytes.Compare(operation.Checkpoint, last) < 0 {
+					return summary, errors.Errorf(
+						ctx,
+						"checkpoint %q is before %q",
+						operation.Checkpoint,
+						last,
+					)
+				}
+				last = operation.Checkpoint
+				if c.enabled() && checkpoint != nil &&
+					bytes.Compare(operation.Checkpoint, checkpoint) <= 0 {
+					summary.Skipped++
+					continue
+				}
+			}
+			size := operation.size()
+			if len(chunk) > 0 &&
+				(len(chunk) >= c.options.MaxOperations || chunkBytes+size > c.options.MaxBytes) {
+				if err := c.writeChunk(ctx, chunk, summary); err != nil {
+					return summary, err
+				}
+				chunk = nil
+				chunkBytes = 0
+			}
+			chunk = append(chunk, operation)
+			chunkBytes += size
+		}
+	}
+}
+
+func (c *chunkedWriter) enabled() bool {
+	return len(c.options.CheckpointKey) > 0
+}
+
+func (c *chunkedWriter) writeChunk(
+	ctx context.Context,
+	chunk []ChunkedWriteOperation,
+	summary *ChunkedWriteSummary,
+) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	var checkpoint []byte
+	var size int
+	err := c.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		// reset, fn is run again if the update is retried
+		checkpoint = nil
+		size = 0
+		for _, operation := range chunk {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, operation.BucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", operation.BucketName)
+			}
+			if operation.Delete {
+				if err := bucket.Delete(ctx, operation.Key); err != nil {
+					return errors.Wrapf(ctx, err, "delete failed")
+				}
+			} else {
+				if err := bucket.Put(ctx, operation.Key, operation.Value); err != nil {
+					return errors.Wrapf(ctx, err, "put failed")
+				}
+			}
+			if len(operation.Checkpoint) > 0 {
+				checkpoint = operation.Checkpoint
+			}
+			size += operation.size()
+		}
+		if !c.enabled() || checkpoint == nil {
+			return nil
+		}
+		bucket, err := tx.CreateBucketIfNotExists(ctx, c.options.CheckpointBucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get checkpoint bucket failed")
+		}
+		if err := bucket.Put(ctx, c.options.CheckpointKey, checkpoint); err != nil {
+			return errors.Wrapf(ctx, err, "put checkpoint failed")
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(
+			ctx,
+			err,
+			"write chunk %d with %d operations failed",
+			summary.Chunks+1,
+			len(chunk),
+		)
+	}
+	summary.Chunks++
+	summary.Operations += len(chunk)
+	summary.Bytes += size
+	if checkpoint != nil {
+		summary.Checkpoint = checkpoint
+	}
+	glog.V(3).Infof("wrote chunk %d with %d operations", summary.Chunks, len(chunk))
+	return nil
+}
+
+func (c *chunkedWriter) Checkpoint(ctx context.Context) ([]byte, error) {
+	if !c.enabled() {
+		return nil, nil
+	}
+	var checkpoint []byte
+	err := c.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, c.options.CheckpointBucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get checkpoint bucket failed")
+		}
+		item, err := bucket.Get(ctx, c.options.CheckpointKey)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get checkpoint failed")
+		}
+		if !item.Exists() {
+			return nil
+		}
+		return item.Value(func(val []byte) error {
+			checkpoint = bytes.Clone(val)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
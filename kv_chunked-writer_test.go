@@ -0,0 +1,159 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("ChunkedWriter", func() {
+	var ctx context.Context
+	var err error
+	var backend *memoryDB
+	var summary *kv.ChunkedWriteSummary
+	var bucketName kv.BucketName
+	var options kv.ChunkedWriterOptions
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		bucketName = kv.NewBucketName("bucket")
+		options = kv.ChunkedWriterOptions{
+			MaxOperations:        3,
+			MaxBytes:             1000,
+			CheckpointBucketName: kv.NewBucketName("checkpoints"),
+			CheckpointKey:        []byte("import"),
+		}
+	})
+	newChunkedWriter := func(db kv.DB, options kv.ChunkedWriterOptions) kv.ChunkedWriter {
+		writer, err := kv.NewChunkedWriter(db, options)
+		Expect(err).To(BeNil())
+		return writer
+	}
+	operations := func(from, to int) <-chan kv.ChunkedWriteOperation {
+		ch := make(chan kv.ChunkedWriteOperation, to-from)
+		for i := from; i < to; i++ {
+			key := []byte(fmt.Sprintf("%03d", i))
+			ch <- kv.ChunkedWriteOperation{
+				BucketName: bucketName,
+				Key:        key,
+				Value:      key,
+				Checkpoint: key,
+			}
+		}
+		close(ch)
+		return ch
+	}
+	storedKeys := func() []string {
+		var keys []string
+		Expect(backend.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			keys = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return keys
+	}
+	It("splits by operation count", func() {
+		summary, err = newChunkedWriter(backend, options).Write(ctx, operations(0, 7))
+		Expect(err).To(BeNil())
+		Expect(summary.Chunks).To(Equal(3))
+		Expect(summary.Operations).To(Equal(7))
+		Expect(summary.Bytes).To(Equal(7 * (len(bucketName) + 6)))
+		Expect(summary.Checkpoint).To(Equal([]byte("006")))
+		Expect(storedKeys()).To(HaveLen(7))
+	})
+	It("splits by byte budget", func() {
+		options.MaxOperations = 100
+		options.MaxBytes = 2 * (len(bucketName) + 6)
+		summary, err = newChunkedWriter(backend, options).Write(ctx, operations(0, 5))
+		Expect(err).To(BeNil())
+		Expect(summary.Chunks).To(Equal(3))
+	})
+	It("writes deletes", func() {
+		_, err = newChunkedWriter(backend, options).Write(ctx, operations(0, 2))
+		Expect(err).To(BeNil())
+		ch := make(chan kv.ChunkedWriteOperation, 1)
+		ch <- kv.ChunkedWriteOperation{BucketName: bucketName, Key: []byte("000"), Delete: true}
+		close(ch)
+		_, err = newChunkedWriter(backend, options).Write(ctx, ch)
+		Expect(err).To(BeNil())
+		Expect(storedKeys()).To(Equal([]string{"001"}))
+	})
+	It("resumes after the checkpoint", func() {
+		failing := &mocks.DB{}
+		failing.ViewStub = backend.View
+		failing.UpdateStub = func(
+			ctx context.Context,
+			fn func(ctx context.Context, tx kv.Tx) error,
+		) error {
+			if failing.UpdateCallCount() > 2 {
+				return errors.New("txn too big")
+			}
+			return backend.Update(ctx, fn)
+		}
+		summary, err = newChunkedWriter(failing, options).Write(ctx, operations(0, 10))
+		Expect(err).NotTo(BeNil())
+		Expect(summary.Chunks).To(Equal(2))
+		Expect(summary.Checkpoint).To(Equal([]byte("005")))
+
+		writer := newChunkedWriter(backend, options)
+		checkpoint, err := writer.Checkpoint(ctx)
+		Expect(err).To(BeNil())
+		Expect(checkpoint).To(Equal([]byte("005")))
+
+		summary, err = writer.Write(ctx, operations(0, 10))
+		Expect(err).To(BeNil())
+		Expect(summary.Skipped).To(Equal(6))
+		Expect(summary.Operations).To(Equal(4))
+		Expect(storedKeys()).To(HaveLen(10))
+	})
+	It("returns error for descending checkpoints", func() {
+		ch := make(chan kv.ChunkedWriteOperation, 2)
+		for _, key := range []string{"b", "a"} {
+			ch <- kv.ChunkedWriteOperation{
+				BucketName: bucketName,
+				Key:        []byte(key),
+				Checkpoint: []byte(key),
+			}
+		}
+		close(ch)
+		_, err = newChunkedWriter(backend, options).Write(ctx, ch)
+		Expect(err).NotTo(BeNil())
+	})
+	It("counts bytes of a retried chunk once", func() {
+		retrying := &mocks.DB{}
+		retrying.ViewStub = backend.View
+		retrying.UpdateStub = func(
+			ctx context.Context,
+			fn func(ctx context.Context, tx kv.Tx) error,
+		) error {
+			_ = backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				if err := fn(ctx, tx); err != nil {
+					return err
+				}
+				return errors.New("conflict")
+			})
+			return backend.Update(ctx, fn)
+		}
+		summary, err = newChunkedWriter(retrying, options).Write(ctx, operations(0, 2))
+		Expect(err).To(BeNil())
+		Expect(summary.Bytes).To(Equal(2 * (len(bucketName) + 6)))
+	})
+	It("returns error for a checkpoint key without bucket name", func() {
+		options.CheckpointBucketName = nil
+		_, err = kv.NewChunkedWriter(backend, options)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/compression-metrics.go --fake-name CompressionMetrics . CompressionMetrics
+
+// CompressionMetrics provides monitoring of value compression using Prometheus.
+type CompressionMetrics interface {
+	DbCompressionRatioObserve(ratio float64)
+	DbCompressionBytesAdd(uncompressed int, stored int)
+}
+
+// NewCompressionMetrics creates a new CompressionMetrics instance with default collectors.
+func NewCompressionMetrics() CompressionMetrics {
+	return &compressionMetrics{}
+}
+
+type compressionMetrics struct {
+}
+
+func (m *compressionMetrics) DbCompressionRatioObserve(ratio float64) {
+	dbCompressionRatioHistogram.Observe(ratio)
+}
+
+func (m *compressionMetrics) DbCompressionBytesAdd(uncompressed int, stored int) {
+	dbCompressionUncompressedBytesCounter.Add(float64(uncompressed))
+	dbCompressionStoredBytesCounter.Add(float64(stored))
+}
+
+var (
+	dbCompressionRatioHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "compression_ratio",
+		Help:      "Stored size divided by uncompressed size of written values",
+		Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+	dbCompressionUncompressedBytesCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "compression_uncompressed_bytes_total",
+		Help:      "Counts bytes of written values before compression",
+	})
+	dbCompressionStoredBytesCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "compression_stored_bytes_total",
+		Help:      "Counts bytes of written values after compression",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbCompressionRatioHistogram,
+		dbCompressionUncompressedBytesCounter,
+		dbCompressionStoredBytesCounter,
+	)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures NewDBWithBatch.
+// Unset values are replaced by the defaults of DefaultBatchOptions.
+type BatchOptions struct {
+	// MaxBatchSize is the maximum number of closures in one transaction.
+	MaxBatchSize int
+	// MaxDelay is the maximum time a closure waits for other closures before the batch starts.
+	MaxDelay time.Duration
+}
+
+// DefaultBatchOptions returns the default BatchOptions.
+func DefaultBatchOptions() BatchOptions {
+	return BatchOptions{
+		MaxBatchSize: 1000,
+		MaxDelay:     10 * time.Millisecond,
+	}
+}
+
+//counterfeiter:generate -o mocks/batch-db.go --fake-name BatchDB . BatchDB
+
+// BatchDB is a DB that can combine concurrent write closures into a single transaction.
+type BatchDB interface {
+	DB
+	// Batch runs fn as part of a shared write transaction together with closures
+	// of concurrent Batch calls. It returns once the shared transaction committed.
+	// fn can be called more than once: if a closure fails, it is removed from
+	// the batch and run alone, and the remaining closures are run again.
+	// If the commit fails, every closure is run alone. fn therefore must not
+	// have side effects outside the transaction. The ctx passed to fn belongs to
+	// the shared transaction, not to the caller.
+	// If ctx is done while waiting, Batch returns the error of ctx. fn is skipped
+	// if the batch did not start yet, otherwise it might still be committed.
+	// Batch must not be called inside a transaction.
+	Batch(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error
+}
+
+// NewDBWithBatch wraps a DB with Batch support.
+func NewDBWithBatch(db DB, options BatchOptions, metrics BatchMetrics) BatchDB {
+	defaults := DefaultBatchOptions()
+	if options.MaxBatchSize <= 0 {
+		options.MaxBatchSize = defaults.MaxBatchSize
+	}
+	if options.MaxDelay <= 0 {
+		options.MaxDelay = defaults.MaxDelay
+	}
+	return &dbWithBatch{
+		db:      db,
+		options: options,
+		metrics: metrics,
+	}
+}
+
+type dbWithBatch struct {
+	db      DB
+	options BatchOptions
+	metrics BatchMetrics
+
+	mux     sync.Mutex
+	current *batch
+}
+
+type batch struct {
+	once  sync.Once
+	timer *time.Timer
+	calls []batchCall
+}
+
+type batchCall struct {
+	ctx  context.Context
+	fn   func(ctx context.Context, tx Tx) error
+	done chan error
+}
+
+func (d *dbWithBatch) Batch(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	call := batchCall{
+		ctx:  ctx,
+		fn:   fn,
+		done: make(chan error, 1),
+	}
+	d.mux.Lock()
+	if d.current == nil {
+		current := &batch{}
+		current.timer = time.AfterFunc(d.options.MaxDelay, func() {
+			d.trigger(current)
+		})
+		d.current = current
+	}
+	current := d.current
+	current.calls = append(current.calls, call)
+	full := len(current.calls) >= d.options.MaxBatchSize
+	if full {
+		d.current = nil
+	}
+	d.mux.Unlock()
+	if full {
+		go d.trigger(current)
+	}
+	select {
+	case err := <-call.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trigger runs the batch exactly once.
+func (d *dbWithBatch) trigger(b *batch) {
+	b.once.Do(func() {
+		d.run(b)
+	})
+}
+
+func (d *dbWithBatch) run(b *batch) {
+	d.mux.Lock()
+	if d.current == b {
+		d.current = nil
+	}
+	d.mux.Unlock()
+	b.timer.Stop()
+
+	calls := make([]batchCall, 0, len(b.calls))
+	for _, call := range b.calls {
+		if err := call.ctx.Err(); err != nil {
+			call.done <- err
+			continue
+		}
+		calls = append(calls, call)
+	}
+	if len(calls) == 0 {
+		return
+	}
+	d.metrics.DbBatchSizeObserve(len(calls))
+	for len(calls) > 0 {
+		failed := -1
+		err := d.db.Update(context.Background(), func(ctx context.Context, tx Tx) error {
+			// fn is run again if the db retries the transaction
+			failed = -1
+			for i, call := range calls {
+				if err := call.fn(ctx, tx); err != nil {
+					failed = i
+					return err
+				}
+			}
+			return nil
+		})
+		if failed >= 0 {
+			// run the failed closure alone to report its own result and retry the rest
+			call := calls[failed]
+			calls = append(calls[:failed:failed], calls[failed+1:]...)
+			d.runAlone(call)
+			continue
+		}
+		if err != nil {
+			for _, call := range calls {
+				d.runAlone(call)
+			}
+			return
+		}
+		for _, call := range calls {
+			call.done <- nil
+		}
+		return
+	}
+}
+
+func (d *dbWithBatch) runAlone(call batchCall) {
+	d.metrics.DbBatchFallbackInc()
+	call.done <- d.db.Update(call.ctx, call.fn)
+}
+
+func (d *dbWithBatch) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.Update(ctx, fn)
+}
+
+func (d *dbWithBatch) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.View(ctx, fn)
+}
+
+func (d *dbWithBatch) Sync() error {
+	return d.db.Sync()
+}
+
+// Close runs the pending batch before closing the underlying DB.
+func (d *dbWithBatch) Close() error {
+	d.mux.Lock()
+	current := d.current
+	d.mux.Unlock()
+	if current != nil {
+		d.trigger(current)
+	}
+	return d.db.Close()
+}
+
+func (d *dbWithBatch) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithBatch) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithBatch) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithBatch) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
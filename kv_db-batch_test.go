@@ -0,0 +1,237 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("DBWithBatch", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var metrics *mocks.BatchMetrics
+	var db kv.BatchDB
+	var bucketName kv.BucketName
+	var options kv.BatchOptions
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		metrics = &mocks.BatchMetrics{}
+		bucketName = kv.NewBucketName("bucket")
+		options = kv.BatchOptions{
+			MaxBatchSize: 10,
+			MaxDelay:     50 * time.Millisecond,
+		}
+	})
+	JustBeforeEach(func() {
+		db = kv.NewDBWithBatch(backend, options, metrics)
+	})
+	put := func(key string) func(ctx context.Context, tx kv.Tx) error {
+		return func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(key))
+		}
+	}
+	runConcurrent := func(count int, fn func(i int) error) []error {
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				errs[i] = fn(i)
+			}(i)
+		}
+		wg.Wait()
+		return errs
+	}
+	storedKeys := func() int64 {
+		var count int64
+		Expect(backend.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if errors.Is(err, kv.ErrBucketNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			count, err = kv.Count(ctx, bucket)
+			return err
+		})).To(Succeed())
+		return count
+	}
+	It("combines concurrent closures", func() {
+		errs := runConcurrent(20, func(i int) error {
+			return db.Batch(ctx, put(fmt.Sprintf("key%02d", i)))
+		})
+		for _, err := range errs {
+			Expect(err).To(BeNil())
+		}
+		Expect(storedKeys()).To(Equal(int64(20)))
+		var total int
+		for i := 0; i < metrics.DbBatchSizeObserveCallCount(); i++ {
+			size := metrics.DbBatchSizeObserveArgsForCall(i)
+			Expect(size).To(BeNumerically("<=", 10))
+			total += size
+		}
+		Expect(total).To(Equal(20))
+		Expect(metrics.DbBatchSizeObserveCallCount()).To(BeNumerically("<", 20))
+	})
+	It("runs a failing closure alone", func() {
+		errs := runConcurrent(5, func(i int) error {
+			if i == 2 {
+				return db.Batch(ctx, func(ctx context.Context, tx kv.Tx) error {
+					return errors.New("banana")
+				})
+			}
+			return db.Batch(ctx, put(fmt.Sprintf("key%02d", i)))
+		})
+		for i, err := range errs {
+			if i == 2 {
+				Expect(err).NotTo(BeNil())
+				continue
+			}
+			Expect(err).To(BeNil())
+		}
+		Expect(storedKeys()).To(Equal(int64(4)))
+		Expect(metrics.DbBatchFallbackIncCallCount()).To(Equal(1))
+	})
+	Context("commit fails", func() {
+		var mockDB *mocks.DB
+		BeforeEach(func() {
+			mockDB = &mocks.DB{}
+			mockDB.UpdateStub = func(
+				ctx context.Context,
+				fn func(context.Context, kv.Tx) error,
+			) error {
+				if err := fn(ctx, &mocks.Tx{}); err != nil {
+					return err
+				}
+				if mockDB.UpdateCallCount() == 1 {
+					return errors.New("commit failed")
+				}
+				return nil
+			}
+		})
+		It("runs every closure alone", func() {
+			db = kv.NewDBWithBatch(mockDB, options, metrics)
+			var calls sync.Map
+			errs := runConcurrent(3, func(i int) error {
+				return db.Batch(ctx, func(ctx context.Context, tx kv.Tx) error {
+					value, _ := calls.LoadOrStore(i, 0)
+					calls.Store(i, value.(int)+1)
+					return nil
+				})
+			})
+			for _, err := range errs {
+				Expect(err).To(BeNil())
+			}
+			Expect(mockDB.UpdateCallCount()).To(Equal(4))
+			Expect(metrics.DbBatchFallbackIncCallCount()).To(Equal(3))
+			for i := 0; i < 3; i++ {
+				value, _ := calls.Load(i)
+				Expect(value).To(Equal(2))
+			}
+		})
+	})
+	It("runs every closure alone if the transaction fails to begin", func() {
+		closedDB := &mocks.DB{}
+		closedDB.UpdateReturns(errors.New("db closed"))
+		db = kv.NewDBWithBatch(closedDB, options, metrics)
+		errs := runConcurrent(3, func(i int) error {
+			return db.Batch(ctx, put(fmt.Sprintf("key%02d", i)))
+		})
+		for _, err := range errs {
+			Expect(err).NotTo(BeNil())
+		}
+		Expect(closedDB.UpdateCallCount()).To(Equal(4))
+		Expect(metrics.DbBatchFallbackIncCallCount()).To(Equal(3))
+	})
+	It("keeps closures of a retried transaction in the batch", func() {
+		retryDB := &mocks.DB{}
+		retryDB.UpdateStub = func(
+			ctx context.Context,
+			fn func(context.Context, kv.Tx) error,
+		) error {
+			return backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				if err := fn(ctx, tx); err != nil {
+					return fn(ctx, tx)
+				}
+				return nil
+			})
+		}
+		db = kv.NewDBWithBatch(retryDB, options, metrics)
+		var once sync.Once
+		errs := runConcurrent(3, func(i int) error {
+			return db.Batch(ctx, func(ctx context.Context, tx kv.Tx) error {
+				var err error
+				if i == 1 {
+					once.Do(func() {
+						err = errors.New("conflict")
+					})
+				}
+				if err != nil {
+					return err
+				}
+				return put(fmt.Sprintf("key%02d", i))(ctx, tx)
+			})
+		})
+		Expect(errs).To(Equal([]error{nil, nil, nil}))
+		Expect(storedKeys()).To(Equal(int64(3)))
+		Expect(metrics.DbBatchFallbackIncCallCount()).To(Equal(0))
+	})
+	It("returns the error of the context while waiting", func() {
+		options.MaxDelay = time.Hour
+		db = kv.NewDBWithBatch(backend, options, metrics)
+		cancelCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			done <- db.Batch(cancelCtx, put("key"))
+		}()
+		Consistently(done).ShouldNot(Receive())
+		cancel()
+		Eventually(done).Should(Receive(MatchError(context.Canceled)))
+		Expect(db.Close()).To(Succeed())
+		Expect(storedKeys()).To(BeZero())
+		Expect(metrics.DbBatchSizeObserveCallCount()).To(Equal(0))
+	})
+	It("runs a full batch without waiting for max delay", func() {
+		options.MaxBatchSize = 2
+		options.MaxDelay = time.Hour
+		db = kv.NewDBWithBatch(backend, options, metrics)
+		errs := runConcurrent(2, func(i int) error {
+			return db.Batch(ctx, put(fmt.Sprintf("key%02d", i)))
+		})
+		Expect(errs).To(Equal([]error{nil, nil}))
+		Expect(storedKeys()).To(Equal(int64(2)))
+	})
+	It("runs the pending batch on close", func() {
+		options.MaxDelay = time.Hour
+		db = kv.NewDBWithBatch(backend, options, metrics)
+		done := make(chan error)
+		go func() {
+			done <- db.Batch(ctx, put("key"))
+		}()
+		Eventually(func() int64 {
+			Expect(db.Close()).To(Succeed())
+			return storedKeys()
+		}).Should(Equal(int64(1)))
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,299 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/bborbe/errors"
+)
+
+// CacheOptions configures NewDBWithCache.
+// Unset values are replaced by the defaults of DefaultCacheOptions.
+type CacheOptions struct {
+	// MaxEntries is the maximum number of cached keys.
+	MaxEntries int
+	// MaxBytes is the maximum size of all cached keys and values.
+	MaxBytes int
+}
+
+// DefaultCacheOptions returns the default CacheOptions.
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{
+		MaxEntries: 10000,
+		MaxBytes:   64 * 1024 * 1024,
+	}
+}
+
+// NewDBWithCache wraps a DB with a least recently used cache for Bucket.Get in View.
+// Values read in View are cached, including missing keys. Keys written in Update and
+// all keys of deleted buckets are invalidated after the transaction finished,
+// Update itself always reads from the underlying DB.
+func NewDBWithCache(db DB, options CacheOptions, metrics CacheMetrics) DB {
+	defaults := DefaultCacheOptions()
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = defaults.MaxEntries
+	}
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = defaults.MaxBytes
+	}
+	return &dbWithCache{
+		db:      db,
+		metrics: metrics,
+		cache:   newLRUCache[[]byte](options.MaxEntries, options.MaxBytes),
+	}
+}
+
+type dbWithCache struct {
+	db      DB
+	metrics CacheMetrics
+	cache   *lruCache[[]byte]
+
+	// mux guards generation, which is incremented by each invalidation
+	mux        sync.Mutex
+	generation uint64
+}
+
+func (d *dbWithCache) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	changes := newDBCacheChanges()
+	err := d.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &dbCacheUpdateTx{
+			Tx:      tx,
+			changes: changes,
+		})
+	})
+	// invalidate even on error, the outcome of a failed commit is unknown
+	d.invalidate(changes)
+	return err
+}
+
+func (d *dbWithCache) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	generation := d.currentGeneration()
+	return d.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &dbCacheViewTx{
+			Tx:         tx,
+			db:         d,
+			generation: generation,
+		})
+	})
+}
+
+func (d *dbWithCache) currentGeneration() uint64 {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.generation
+}
+
+func (d *dbWithCache) invalidate(changes *dbCacheChanges) {
+	changes.mux.Lock()
+	defer changes.mux.Unlock()
+	if len(changes.keys) == 0 && len(changes.deletedBuckets) == 0 {
+		return
+	}
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.generation++
+	for key := range changes.keys {
+		d.cache.Remove(key)
+	}
+	for bucketName := range changes.deletedBuckets {
+		d.cache.RemovePrefix(dbCacheBucketPrefix(BucketName(bucketName)))
+	}
+}
+
+// add caches the value read in a View started at generation. The value is dropped if
+// the cache was invalidated since, it might have been read from an older snapshot.
+func (d *dbWithCache) add(generation uint64, key string, value []byte) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.generation != generation {
+		return
+	}
+	d.cache.Add(key, value, len(key)+len(value))
+}
+
+func (d *dbWithCache) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithCache) Close() error {
+	d.cache.Purge()
+	return d.db.Close()
+}
+
+func (d *dbWithCache) Remove() error {
+	d.cache.Purge()
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithCache) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithCache) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithCache) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
+
+// dbCacheBucketPrefix returns the length prefixed bucket name,
+// so no bucket prefix matches the keys of another bucket.
+func dbCacheBucketPrefix(bucketName BucketName) string {
+	prefix := binary.AppendUvarint(nil, uint64(len(bucketName)))
+	return string(append(prefix, bucketName...))
+}
+
+func dbCacheKey(bucketName BucketName, key []byte) string {
+	return dbCacheBucketPrefix(bucketName) + string(key)
+}
+
+// dbCacheChanges collects the keys and buckets written by an Update.
+type dbCacheChanges struct {
+	mux            sync.Mutex
+	keys           map[string]struct{}
+	deletedBuckets map[string]struct{}
+}
+
+func newDBCacheChanges() *dbCacheChanges {
+	return &dbCacheChanges{
+		keys:           map[string]struct{}{},
+		deletedBuckets: map[string]struct{}{},
+	}
+}
+
+func (d *dbCacheChanges) addKey(bucketName BucketName, key []byte) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.keys[dbCacheKey(bucketName, key)] = struct{}{}
+}
+
+func (d *dbCacheChanges) addDeletedBucket(bucketName BucketName) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.deletedBuckets[bucketName.String()] = struct{}{}
+}
+
+type dbCacheUpdateTx struct {
+	Tx
+	changes *dbCacheChanges
+}
+
+func (d *dbCacheUpdateTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := d.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return d.wrap(name, bucket), nil
+}
+
+func (d *dbCacheUpdateTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := d.Tx.CreateBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return d.wrap(name, bucket), nil
+}
+
+func (d *dbCacheUpdateTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	bucket, err := d.Tx.CreateBucketIfNotExists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return d.wrap(name, bucket), nil
+}
+
+func (d *dbCacheUpdateTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	d.changes.addDeletedBucket(name)
+	return d.Tx.DeleteBucket(ctx, name)
+}
+
+func (d *dbCacheUpdateTx) wrap(name BucketName, bucket Bucket) Bucket {
+	return &dbCacheUpdateBucket{
+		Bucket:  bucket,
+		name:    name,
+		changes: d.changes,
+	}
+}
+
+// dbCacheUpdateBucket records all written keys for invalidation.
+type dbCacheUpdateBucket struct {
+	Bucket
+	name    BucketName
+	changes *dbCacheChanges
+}
+
+func (d *dbCacheUpdateBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	d.changes.addKey(d.name, key)
+	return d.Bucket.Put(ctx, key, value)
+}
+
+func (d *dbCacheUpdateBucket) Delete(ctx context.Context, key []byte) error {
+	d.changes.addKey(d.name, key)
+	return d.Bucket.Delete(ctx, key)
+}
+
+type dbCacheViewTx struct {
+	Tx
+	db         *dbWithCache
+	generation uint64
+}
+
+func (d *dbCacheViewTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := d.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &dbCacheViewBucket{
+		Bucket: bucket,
+		tx:     d,
+		name:   name,
+	}, nil
+}
+
+// dbCacheViewBucket serves Get from the cache and populates it on misses.
+type dbCacheViewBucket struct {
+	Bucket
+	tx   *dbCacheViewTx
+	name BucketName
+}
+
+func (d *dbCacheViewBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	cacheKey := dbCacheKey(d.name, key)
+	if cached, ok := d.tx.db.cache.Get(cacheKey); ok {
+		d.tx.db.metrics.DbCacheHitInc()
+		return NewByteItem(key, cached), nil
+	}
+	d.tx.db.metrics.DbCacheMissInc()
+	item, err := d.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	if item.Exists() {
+		if err := item.Value(func(val []byte) error {
+			value = bytes.Clone(val)
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(ctx, err, "read value failed")
+		}
+	}
+	d.tx.db.add(d.tx.generation, cacheKey, value)
+	return NewByteItem(key, value), nil
+}
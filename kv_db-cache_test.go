@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("DBWithCache", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var metrics *mocks.CacheMetrics
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		metrics = &mocks.CacheMetrics{}
+		db = kv.NewDBWithCache(backend, kv.CacheOptions{MaxEntries: 2}, metrics)
+		bucketName = kv.NewBucketName("bucket")
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "b", "c"} {
+				if err := bucket.Put(ctx, []byte(key), []byte(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	})
+	getIn := func(ctx context.Context, tx kv.Tx, key string) string {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		Expect(err).To(BeNil())
+		item, err := bucket.Get(ctx, []byte(key))
+		Expect(err).To(BeNil())
+		var result string
+		Expect(item.Value(func(val []byte) error {
+			result = string(val)
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	get := func(key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			result = getIn(ctx, tx, key)
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	put := func(key string, value string) error {
+		return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})
+	}
+	It("serves repeated reads from the cache", func() {
+		Expect(get("a")).To(Equal("a"))
+		Expect(get("a")).To(Equal("a"))
+		Expect(get("x")).To(Equal(""))
+		Expect(get("x")).To(Equal(""))
+		Expect(metrics.DbCacheMissIncCallCount()).To(Equal(2))
+		Expect(metrics.DbCacheHitIncCallCount()).To(Equal(2))
+	})
+	It("invalidates committed writes", func() {
+		Expect(get("a")).To(Equal("a"))
+		Expect(put("a", "A")).To(Succeed())
+		Expect(get("a")).To(Equal("A"))
+		Expect(metrics.DbCacheHitIncCallCount()).To(Equal(0))
+	})
+	It("does not cache uncommitted writes", func() {
+		err := db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("a"), []byte("A"))).To(Succeed())
+			Expect(getIn(ctx, tx, "a")).To(Equal("A"))
+			return errors.New("banana")
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(get("a")).To(Equal("a"))
+	})
+	It("invalidates deleted buckets", func() {
+		Expect(get("a")).To(Equal("a"))
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			if err := tx.DeleteBucket(ctx, bucketName); err != nil {
+				return err
+			}
+			_, err := tx.CreateBucket(ctx, bucketName)
+			return err
+		})).To(Succeed())
+		Expect(get("a")).To(Equal(""))
+	})
+	It("does not cache values of snapshots older than a commit", func() {
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			Expect(put("a", "A")).To(Succeed())
+			Expect(getIn(ctx, tx, "a")).To(Equal("a"))
+			return nil
+		})).To(Succeed())
+		Expect(get("a")).To(Equal("A"))
+	})
+	It("evicts the least recently used key", func() {
+		Expect(get("a")).To(Equal("a"))
+		Expect(get("b")).To(Equal("b"))
+		Expect(get("a")).To(Equal("a"))
+		Expect(get("c")).To(Equal("c"))
+		Expect(metrics.DbCacheHitIncCallCount()).To(Equal(1))
+		Expect(get("a")).To(Equal("a"))
+		Expect(metrics.DbCacheHitIncCallCount()).To(Equal(2))
+		Expect(get("b")).To(Equal("b"))
+		Expect(metrics.DbCacheHitIncCallCount()).To(Equal(2))
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewDBWithCache(
+				newMemoryDB(),
+				kv.DefaultCacheOptions(),
+				&mocks.CacheMetrics{},
+			), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoCallbackTransaction is returned by OnCommit and OnRollback if the context
+// does not belong to a transaction opened through NewDBWithCallbacks.
+var ErrNoCallbackTransaction = errors.New("no transaction with callback support in context")
+
+type callbacksContextKey struct{}
+
+// OnCommit registers fn to be called after the transaction of ctx committed.
+// It must be called within the fn(ctx, tx) of a DB created with NewDBWithCallbacks.
+// Callbacks run in registration order with the context passed to Update or View.
+func OnCommit(ctx context.Context, fn func(ctx context.Context)) error {
+	callbacks, ok := ctx.Value(callbacksContextKey{}).(*transactionCallbacks)
+	if !ok {
+		return ErrNoCallbackTransaction
+	}
+	callbacks.addCommit(fn)
+	return nil
+}
+
+// OnRollback registers fn to be called after the transaction of ctx was rolled back.
+// It must be called within the fn(ctx, tx) of a DB created with NewDBWithCallbacks.
+// Callbacks run in registration order with the context passed to Update or View.
+func OnRollback(ctx context.Context, fn func(ctx context.Context)) error {
+	callbacks, ok := ctx.Value(callbacksContextKey{}).(*transactionCallbacks)
+	if !ok {
+		return ErrNoCallbackTransaction
+	}
+	callbacks.addRollback(fn)
+	return nil
+}
+
+// NewDBWithCallbacks wraps a DB so OnCommit and OnRollback can be used inside its transactions.
+// A View that returns no error counts as committed.
+func NewDBWithCallbacks(db DB) DB {
+	return &dbWithCallbacks{
+		db: db,
+	}
+}
+
+type dbWithCallbacks struct {
+	db DB
+}
+
+func (d *dbWithCallbacks) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.run(ctx, d.db.Update, fn)
+}
+
+func (d *dbWithCallbacks) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.run(ctx, d.db.View, fn)
+}
+
+func (d *dbWithCallbacks) run(
+	ctx context.Context,
+	action func(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	var callbacks *transactionCallbacks
+	err := action(ctx, func(txCtx context.Context, tx Tx) error {
+		// create a fresh registry per call, in case the backend re-runs fn
+		callbacks = &transactionCallbacks{}
+		return fn(context.WithValue(txCtx, callbacksContextKey{}, callbacks), tx)
+	})
+	if callbacks == nil {
+		return err
+	}
+	if err != nil {
+		callbacks.runRollback(ctx)
+		return err
+	}
+	callbacks.runCommit(ctx)
+	return nil
+}
+
+func (d *dbWithCallbacks) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithCallbacks) Close() error {
+	return d.db.Close()
+}
+
+func (d *dbWithCallbacks) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithCallbacks) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithCallbacks) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithCallbacks) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
+
+type transactionCallbacks struct {
+	mux      sync.Mutex
+	commit   []func(ctx context.Context)
+	rollback []func(ctx context.Context)
+}
+
+func (t *transactionCallbacks) addCommit(fn func(ctx context.Context)) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.commit = append(t.commit, fn)
+}
+
+func (t *transactionCallbacks) addRollback(fn func(ctx context.Context)) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.rollback = append(t.rollback, fn)
+}
+
+func (t *transactionCallbacks) runCommit(ctx context.Context) {
+	t.mux.Lock()
+	fns := t.commit
+	t.mux.Unlock()
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
+
+func (t *transactionCallbacks) runRollback(ctx context.Context) {
+	t.mux.Lock()
+	fns := t.rollback
+	t.mux.Unlock()
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
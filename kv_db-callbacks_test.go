@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("DBWithCallbacks", func() {
+	var ctx context.Context
+	var err error
+	var db kv.DB
+	var calls []string
+	var fnErr error
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = kv.NewDBWithCallbacks(newMemoryDB())
+		calls = nil
+		fnErr = nil
+	})
+	register := func(ctx context.Context) {
+		Expect(kv.OnCommit(ctx, func(ctx context.Context) {
+			calls = append(calls, "commit1")
+		})).To(Succeed())
+		Expect(kv.OnRollback(ctx, func(ctx context.Context) {
+			calls = append(calls, "rollback1")
+		})).To(Succeed())
+		Expect(kv.OnCommit(ctx, func(ctx context.Context) {
+			calls = append(calls, "commit2")
+		})).To(Succeed())
+		Expect(kv.OnRollback(ctx, func(ctx context.Context) {
+			calls = append(calls, "rollback2")
+		})).To(Succeed())
+	}
+	Context("Update", func() {
+		JustBeforeEach(func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				register(ctx)
+				calls = append(calls, "fn")
+				return fnErr
+			})
+		})
+		Context("commit", func() {
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("calls commit callbacks in order after fn", func() {
+				Expect(calls).To(Equal([]string{"fn", "commit1", "commit2"}))
+			})
+		})
+		Context("rollback", func() {
+			BeforeEach(func() {
+				fnErr = errors.New("banana")
+			})
+			It("returns error", func() {
+				Expect(err).To(Equal(fnErr))
+			})
+			It("calls rollback callbacks in order after fn", func() {
+				Expect(calls).To(Equal([]string{"fn", "rollback1", "rollback2"}))
+			})
+		})
+	})
+	Context("View", func() {
+		JustBeforeEach(func() {
+			err = db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+				register(ctx)
+				return fnErr
+			})
+		})
+		It("calls commit callbacks", func() {
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal([]string{"commit1", "commit2"}))
+		})
+	})
+	Context("commit fails in backend", func() {
+		BeforeEach(func() {
+			backend := &mocks.DB{}
+			backend.UpdateStub = func(
+				ctx context.Context,
+				fn func(context.Context, kv.Tx) error,
+			) error {
+				if err := fn(ctx, &mocks.Tx{}); err != nil {
+					return err
+				}
+				return errors.New("commit failed")
+			}
+			db = kv.NewDBWithCallbacks(backend)
+		})
+		It("calls rollback callbacks", func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				register(ctx)
+				return nil
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(calls).To(Equal([]string{"rollback1", "rollback2"}))
+		})
+	})
+	Context("callback uses db", func() {
+		It("can open a new transaction", func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return kv.OnCommit(ctx, func(ctx context.Context) {
+					Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+						calls = append(calls, "view")
+						return nil
+					})).To(Succeed())
+				})
+			})
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal([]string{"view"}))
+		})
+	})
+	Context("without transaction", func() {
+		It("OnCommit returns error", func() {
+			err = kv.OnCommit(ctx, func(ctx context.Context) {})
+			Expect(errors.Is(err, kv.ErrNoCallbackTransaction)).To(BeTrue())
+		})
+		It("OnRollback returns error", func() {
+			err = kv.OnRollback(ctx, func(ctx context.Context) {})
+			Expect(errors.Is(err, kv.ErrNoCallbackTransaction)).To(BeTrue())
+		})
+	})
+	kv.BasicTestSuite(kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+		return kv.NewDBWithCallbacks(newMemoryDB()), nil
+	}))
+})
@@ -0,0 +1,190 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	stderrors "errors"
+	"io"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrDecodedSizeExceeded is returned if a compressed value decompresses to more
+// than CompressionOptions.MaxDecodedSize bytes.
+var ErrDecodedSizeExceeded = stderrors.New("decoded size exceeded")
+
+// compressionMagic starts all values written by the compression wrapper.
+// JSON and text values never start with a zero byte, so values written
+// before compression was enabled are read as they are.
+var compressionMagic = []byte{0, 'k', 'v', 'z'}
+
+const (
+	compressionAlgorithmNone  byte = 0
+	compressionAlgorithmFlate byte = 1
+)
+
+// CompressionOptions configures NewDBWithCompression.
+// Unset values are replaced by the defaults of DefaultCompressionOptions.
+type CompressionOptions struct {
+	// Threshold is the minimum size of values to compress.
+	Threshold int
+	// Level is the flate compression level.
+	Level int
+	// MaxDecodedSize is the maximum size of a decompressed value. Reading a larger
+	// value fails with ErrDecodedSizeExceeded.
+	MaxDecodedSize int
+}
+
+// DefaultCompressionOptions returns the default CompressionOptions.
+func DefaultCompressionOptions() CompressionOptions {
+	return CompressionOptions{
+		Threshold:      1024,
+		Level:          flate.DefaultCompression,
+		MaxDecodedSize: 64 << 20,
+	}
+}
+
+// NewDBWithCompression wraps a DB to compress the values of the given buckets,
+// or of all buckets if none is given, with flate if they are larger than the threshold.
+// Values that do not get smaller are stored as they are, compressed values have a
+// header of 5 bytes. Existing uncompressed values stay readable.
+func NewDBWithCompression(
+	db DB,
+	options CompressionOptions,
+	metrics CompressionMetrics,
+	bucketNames ...BucketName,
+) DB {
+	return newDBWithValueTransform(
+		db,
+		newCompressionTransform(options, metrics),
+		bucketNames,
+	)
+}
+
+// NewCompressedBucket wraps a Bucket to compress its values, see NewDBWithCompression.
+func NewCompressedBucket(
+	bucket Bucket,
+	bucketName BucketName,
+	options CompressionOptions,
+	metrics CompressionMetrics,
+) Bucket {
+	return newValueTransformBucket(
+		bucket,
+		bucketName,
+		newCompressionTransform(options, metrics),
+	)
+}
+
+func newCompressionTransform(
+	options CompressionOptions,
+	metrics CompressionMetrics,
+) *compressionTransform {
+	defaults := DefaultCompressionOptions()
+	if options.Threshold <= 0 {
+		options.Threshold = defaults.Threshold
+	}
+	if options.Level == 0 {
+		options.Level = defaults.Level
+	}
+	if options.MaxDecodedSize <= 0 {
+		options.MaxDecodedSize = defaults.MaxDecodedSize
+	}
+	return &compressionTransform{
+		options: options,
+		metrics: metrics,
+	}
+}
+
+type compressionTransform struct {
+	options CompressionOptions
+	metrics CompressionMetrics
+}
+
+func (c *compressionTransform) encode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	if len(value) < c.options.Threshold {
+		return rawCompressionValue(value), nil
+	}
+	buf := bytes.NewBuffer(compressionValue(compressionAlgorithmFlate, nil))
+	writer, err := flate.NewWriter(buf, c.options.Level)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create flate writer failed")
+	}
+	if _, err := writer.Write(value); err != nil {
+		return nil, errors.Wrapf(ctx, err, "compress failed")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "close flate writer failed")
+	}
+	stored := buf.Bytes()
+	if len(stored) >= len(value) {
+		stored = rawCompressionValue(value)
+	}
+	c.metrics.DbCompressionRatioObserve(float64(len(stored)) / float64(len(value)))
+	c.metrics.DbCompressionBytesAdd(len(value), len(stored))
+	return stored, nil
+}
+
+func (c *compressionTransform) decode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	headerLength := len(compressionMagic) + 1
+	if !bytes.HasPrefix(value, compressionMagic) || len(value) < headerLength {
+		return bytes.Clone(value), nil
+	}
+	payload := value[headerLength:]
+	switch algorithm := value[len(compressionMagic)]; algorithm {
+	case compressionAlgorithmNone:
+		return bytes.Clone(payload), nil
+	case compressionAlgorithmFlate:
+		reader := flate.NewReader(bytes.NewReader(payload))
+		defer reader.Close()
+		limit := int64(c.options.MaxDecodedSize)
+		result, err := io.ReadAll(io.LimitReader(reader, limit+1))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decompress failed")
+		}
+		if int64(len(result)) > limit {
+			return nil, errors.Wrapf(
+				ctx,
+				ErrDecodedSizeExceeded,
+				"decompress %s of bucket %s exceeds %d bytes",
+				key,
+				bucketName,
+				limit,
+			)
+		}
+		return result, nil
+	default:
+		return nil, errors.Errorf(ctx, "unknown compression algorithm %d", algorithm)
+	}
+}
+
+// rawCompressionValue returns value uncompressed. Only values looking like
+// compressed ones get a header.
+func rawCompressionValue(value []byte) []byte {
+	if bytes.HasPrefix(value, compressionMagic) {
+		return compressionValue(compressionAlgorithmNone, value)
+	}
+	return value
+}
+
+// compressionValue returns the header of algorithm followed by payload.
+func compressionValue(algorithm byte, payload []byte) []byte {
+	result := make([]byte, 0, len(compressionMagic)+1+len(payload))
+	result = append(result, compressionMagic...)
+	result = append(result, algorithm)
+	return append(result, payload...)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("DBWithCompression", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var metrics *mocks.CompressionMetrics
+	var db kv.DB
+	var bucketName kv.BucketName
+	var large string
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		metrics = &mocks.CompressionMetrics{}
+		db = kv.NewDBWithCompression(backend, kv.CompressionOptions{Threshold: 100}, metrics)
+		bucketName = kv.NewBucketName("bucket")
+		large = strings.Repeat(`{"name":"john","age":30}`, 100)
+	})
+	put := func(db kv.DB, key string, value string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})).To(Succeed())
+	}
+	get := func(db kv.DB, key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	It("compresses values above the threshold", func() {
+		put(db, "large", large)
+		Expect(get(db, "large")).To(Equal(large))
+		Expect(len(get(backend, "large"))).To(BeNumerically("<", len(large)/10))
+		Expect(metrics.DbCompressionRatioObserveCallCount()).To(Equal(1))
+		uncompressed, stored := metrics.DbCompressionBytesAddArgsForCall(0)
+		Expect(uncompressed).To(Equal(len(large)))
+		Expect(stored).To(Equal(len(get(backend, "large"))))
+	})
+	It("stores small values raw", func() {
+		put(db, "small", "small")
+		Expect(get(backend, "small")).To(Equal("small"))
+		Expect(get(db, "small")).To(Equal("small"))
+		Expect(metrics.DbCompressionRatioObserveCallCount()).To(Equal(0))
+	})
+	It("reads values written before compression", func() {
+		put(backend, "old", large)
+		Expect(get(db, "old")).To(Equal(large))
+	})
+	It("keeps raw values starting with the header", func() {
+		value := "\x00kvz\x01raw"
+		put(db, "tricky", value)
+		Expect(get(db, "tricky")).To(Equal(value))
+	})
+	It("stores values raw if they do not get smaller", func() {
+		random := rand.New(rand.NewPCG(1, 2))
+		value := make([]byte, 200)
+		for i := range value {
+			value[i] = byte(random.IntN(255) + 1)
+		}
+		put(db, "random", string(value))
+		Expect(get(backend, "random")).To(Equal(string(value)))
+		Expect(get(db, "random")).To(Equal(string(value)))
+	})
+	It("fails on values larger than the max decoded size", func() {
+		put(db, "large", large)
+		db = kv.NewDBWithCompression(
+			backend,
+			kv.CompressionOptions{Threshold: 100, MaxDecodedSize: len(large) - 1},
+			metrics,
+		)
+		err := db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			_, err = bucket.Get(ctx, []byte("large"))
+			return err
+		})
+		Expect(errors.Is(err, kv.ErrDecodedSizeExceeded)).To(BeTrue())
+	})
+	It("decompresses values of iterators", func() {
+		put(db, "large", large)
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return kv.ForEach(ctx, bucket, func(item kv.Item) error {
+				return item.Value(func(val []byte) error {
+					Expect(string(val)).To(Equal(large))
+					return nil
+				})
+			})
+		})).To(Succeed())
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewDBWithCompression(
+				newMemoryDB(),
+				kv.CompressionOptions{Threshold: 1},
+				&mocks.CompressionMetrics{},
+			), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
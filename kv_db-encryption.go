@@ -0,0 +1,277 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+const encryptionFormatVersion = 1
+
+// NewDBWithEncryption wraps a DB to encrypt the values of the given buckets,
+// or of all buckets if none is given, with AES-GCM. The bucket name and key are
+// bound to the ciphertext as associated data, so values can not be moved between keys.
+// Keys are not encrypted and empty values are stored as they are.
+func NewDBWithEncryption(
+	db DB,
+	keyProvider EncryptionKeyProvider,
+	bucketNames ...BucketName,
+) DB {
+	return newDBWithValueTransform(
+		db,
+		&encryptionTransform{keyProvider: keyProvider},
+		bucketNames,
+	)
+}
+
+// NewEncryptedBucket wraps a Bucket to encrypt its values, see NewDBWithEncryption.
+func NewEncryptedBucket(
+	bucket Bucket,
+	bucketName BucketName,
+	keyProvider EncryptionKeyProvider,
+) Bucket {
+	return newValueTransformBucket(
+		bucket,
+		bucketName,
+		&encryptionTransform{keyProvider: keyProvider},
+	)
+}
+
+// ReEncryptBucket encrypts all values of the bucket not encrypted with the current key
+// again with the current key, in transactions of at most chunkSize values.
+// db must be the DB without encryption. It returns the number of re-encrypted values.
+func ReEncryptBucket(
+	ctx context.Context,
+	db DB,
+	keyProvider EncryptionKeyProvider,
+	bucketName BucketName,
+	chunkSize int,
+) (int, error) {
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	transform := &encryptionTransform{keyProvider: keyProvider}
+	var total int
+	var lastKey []byte
+	for {
+		var count int
+		var chunkLastKey []byte
+		var completed bool
+		err := db.Update(ctx, func(ctx context.Context, tx Tx) error {
+			current, err := keyProvider.CurrentKey(ctx)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get current key failed")
+			}
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				if errors.Is(err, ErrBucketNotFound) {
+					completed = true
+					return nil
+				}
+				return errors.Wrapf(ctx, err, "get bucket failed")
+			}
+			var writes []writeBufferEntry
+			writes, chunkLastKey, completed, err = reEncryptChunk(
+				ctx,
+				bucket,
+				bucketName,
+				transform,
+				current,
+				lastKey,
+				chunkSize,
+			)
+			if err != nil {
+				return err
+			}
+			for _, write := range writes {
+				if err := bucket.Put(ctx, write.key, write.value); err != nil {
+					return errors.Wrapf(ctx, err, "put failed")
+				}
+			}
+			count = len(writes)
+			return nil
+		})
+		if err != nil {
+			return total, errors.Wrapf(ctx, err, "re-encrypt bucket %s failed", bucketName)
+		}
+		total += count
+		if chunkLastKey != nil {
+			lastKey = chunkLastKey
+		}
+		if completed {
+			glog.V(2).Infof("re-encrypted %d values of bucket %s", total, bucketName)
+			return total, nil
+		}
+	}
+}
+
+// reEncryptChunk returns up to chunkSize values after lastKey encrypted with current.
+func reEncryptChunk(
+	ctx context.Context,
+	bucket Bucket,
+	bucketName BucketName,
+	transform *encryptionTransform,
+	current *EncryptionKey,
+	lastKey []byte,
+	chunkSize int,
+) ([]writeBufferEntry, []byte, bool, error) {
+	var writes []writeBufferEntry
+	it := bucket.Iterator()
+	defer it.Close()
+	if lastKey == nil {
+		it.Rewind()
+	} else {
+		it.Seek(lastKey)
+	}
+	for ; it.Valid(); it.Next() {
+		if len(writes) >= chunkSize {
+			return writes, lastKey, false, nil
+		}
+		item := it.Item()
+		key := bytes.Clone(item.Key())
+		if bytes.Equal(key, lastKey) {
+			continue
+		}
+		lastKey = key
+		err := item.Value(func(val []byte) error {
+			if len(val) == 0 {
+				return nil
+			}
+			keyID, _, err := parseEncryptedValue(ctx, val)
+			if err != nil {
+				return err
+			}
+			if keyID == current.ID {
+				return nil
+			}
+			plain, err := transform.decode(ctx, bucketName, key, val)
+			if err != nil {
+				return err
+			}
+			encrypted, err := encrypt(ctx, current, bucketName, key, plain)
+			if err != nil {
+				return err
+			}
+			writes = append(writes, writeBufferEntry{key: key, value: encrypted})
+			return nil
+		})
+		if err != nil {
+			return nil, nil, false, errors.Wrapf(ctx, err, "re-encrypt %s failed", key)
+		}
+	}
+	return writes, lastKey, true, nil
+}
+
+type encryptionTransform struct {
+	keyProvider EncryptionKeyProvider
+}
+
+func (e *encryptionTransform) encode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	current, err := e.keyProvider.CurrentKey(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get current key failed")
+	}
+	return encrypt(ctx, current, bucketName, key, value)
+}
+
+func (e *encryptionTransform) decode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	keyID, sealed, err := parseEncryptedValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	encryptionKey, err := e.keyProvider.Key(ctx, keyID)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get key failed")
+	}
+	aead, err := newEncryptionAEAD(ctx, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.Wrapf(ctx, ErrInvalidCiphertext, "nonce missing")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, encryptionAssociatedData(bucketName, key))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, ErrInvalidCiphertext, "open failed: %v", err)
+	}
+	return plain, nil
+}
+
+// encrypt returns version, key ID length, key ID, nonce and ciphertext.
+func encrypt(
+	ctx context.Context,
+	encryptionKey *EncryptionKey,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	if len(encryptionKey.ID) > 255 {
+		return nil, errors.Errorf(ctx, "key id %s longer than 255 bytes", encryptionKey.ID)
+	}
+	aead, err := newEncryptionAEAD(ctx, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	size := 2 + len(encryptionKey.ID) + aead.NonceSize() + len(value) + aead.Overhead()
+	result := make([]byte, 0, size)
+	result = append(result, encryptionFormatVersion, byte(len(encryptionKey.ID)))
+	result = append(result, encryptionKey.ID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrapf(ctx, err, "create nonce failed")
+	}
+	result = append(result, nonce...)
+	return aead.Seal(result, nonce, value, encryptionAssociatedData(bucketName, key)), nil
+}
+
+// parseEncryptedValue returns the key ID and the nonce with the ciphertext.
+func parseEncryptedValue(ctx context.Context, value []byte) (string, []byte, error) {
+	if len(value) < 2 || value[0] != encryptionFormatVersion {
+		return "", nil, errors.Wrapf(ctx, ErrInvalidCiphertext, "unknown format")
+	}
+	idLength := int(value[1])
+	if len(value) < 2+idLength {
+		return "", nil, errors.Wrapf(ctx, ErrInvalidCiphertext, "key id missing")
+	}
+	return string(value[2 : 2+idLength]), value[2+idLength:], nil
+}
+
+func newEncryptionAEAD(ctx context.Context, encryptionKey *EncryptionKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(encryptionKey.Key)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create cipher for key id %s failed", encryptionKey.ID)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create gcm failed")
+	}
+	return aead, nil
+}
+
+// encryptionAssociatedData returns the length prefixed bucket name followed by the key.
+func encryptionAssociatedData(bucketName BucketName, key []byte) []byte {
+	result := binary.AppendUvarint(nil, uint64(len(bucketName)))
+	result = append(result, bucketName...)
+	return append(result, key...)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("DBWithEncryption", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var keys map[string][]byte
+	var bucketName kv.BucketName
+	var plainBucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		keys = map[string][]byte{
+			"k1": bytes.Repeat([]byte{1}, 32),
+			"k2": bytes.Repeat([]byte{2}, 32),
+		}
+		bucketName = kv.NewBucketName("personal")
+		plainBucketName = kv.NewBucketName("public")
+	})
+	newDB := func(currentID string) kv.DB {
+		return kv.NewDBWithEncryption(
+			backend,
+			kv.NewStaticEncryptionKeyProvider(currentID, keys),
+			bucketName,
+		)
+	}
+	put := func(db kv.DB, bucketName kv.BucketName, key string, value string) error {
+		return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})
+	}
+	get := func(db kv.DB, bucketName kv.BucketName, key string) (string, error) {
+		var result string
+		err := db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})
+		return result, err
+	}
+	It("encrypts values of the given buckets", func() {
+		db := newDB("k1")
+		Expect(put(db, bucketName, "name", "john")).To(Succeed())
+		Expect(put(db, plainBucketName, "name", "john")).To(Succeed())
+		Expect(get(db, bucketName, "name")).To(Equal("john"))
+
+		raw, err := get(backend, bucketName, "name")
+		Expect(err).To(BeNil())
+		Expect(raw).NotTo(ContainSubstring("john"))
+		Expect(get(backend, plainBucketName, "name")).To(Equal("john"))
+
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Rewind()
+			Expect(it.Valid()).To(BeTrue())
+			return it.Item().Value(func(val []byte) error {
+				Expect(string(val)).To(Equal("john"))
+				return nil
+			})
+		})).To(Succeed())
+	})
+	It("binds values to their key", func() {
+		db := newDB("k1")
+		Expect(put(db, bucketName, "a", "secret")).To(Succeed())
+		raw, err := get(backend, bucketName, "a")
+		Expect(err).To(BeNil())
+		Expect(put(backend, bucketName, "b", raw)).To(Succeed())
+		_, err = get(db, bucketName, "b")
+		Expect(errors.Is(err, kv.ErrInvalidCiphertext)).To(BeTrue())
+	})
+	It("rotates keys", func() {
+		Expect(put(newDB("k1"), bucketName, "a", "one")).To(Succeed())
+		Expect(put(newDB("k1"), bucketName, "b", "two")).To(Succeed())
+		Expect(put(newDB("k2"), bucketName, "c", "three")).To(Succeed())
+		Expect(get(newDB("k2"), bucketName, "a")).To(Equal("one"))
+
+		count, err := kv.ReEncryptBucket(
+			ctx,
+			backend,
+			kv.NewStaticEncryptionKeyProvider("k2", keys),
+			bucketName,
+			1,
+		)
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(2))
+
+		delete(keys, "k1")
+		Expect(get(newDB("k2"), bucketName, "a")).To(Equal("one"))
+		Expect(get(newDB("k2"), bucketName, "b")).To(Equal("two"))
+		Expect(get(newDB("k2"), bucketName, "c")).To(Equal("three"))
+	})
+	It("returns error for unknown key id", func() {
+		Expect(put(newDB("k1"), bucketName, "a", "one")).To(Succeed())
+		delete(keys, "k1")
+		_, err := get(newDB("k2"), bucketName, "a")
+		Expect(errors.Is(err, kv.ErrEncryptionKeyNotFound)).To(BeTrue())
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewDBWithEncryption(
+				newMemoryDB(),
+				kv.NewStaticEncryptionKeyProvider("k", map[string][]byte{
+					"k": bytes.Repeat([]byte{1}, 16),
+				}),
+			), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,397 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrFaultInjected is returned by a FaultDB for rules without Error.
+var ErrFaultInjected = stderrors.New("fault injected")
+
+// FaultOperation is an operation of a FaultDB a FaultRule applies to.
+type FaultOperation string
+
+const (
+	// FaultUpdate is checked before Update starts a transaction.
+	FaultUpdate FaultOperation = "update"
+	// FaultView is checked before View starts a transaction.
+	FaultView FaultOperation = "view"
+	// FaultCommit is checked after the fn of Update succeeded,
+	// an error rolls back the transaction as if the commit failed.
+	FaultCommit FaultOperation = "commit"
+	// FaultBucket is checked by Tx.Bucket.
+	FaultBucket FaultOperation = "bucket"
+	// FaultCreateBucket is checked by Tx.CreateBucket and Tx.CreateBucketIfNotExists.
+	FaultCreateBucket FaultOperation = "create_bucket"
+	// FaultDeleteBucket is checked by Tx.DeleteBucket.
+	FaultDeleteBucket FaultOperation = "delete_bucket"
+	// FaultListBucketNames is checked by Tx.ListBucketNames.
+	FaultListBucketNames FaultOperation = "list_bucket_names"
+	// FaultGet is checked by Bucket.Get.
+	FaultGet FaultOperation = "get"
+	// FaultPut is checked by Bucket.Put.
+	FaultPut FaultOperation = "put"
+	// FaultDelete is checked by Bucket.Delete.
+	FaultDelete FaultOperation = "delete"
+	// FaultIterate is checked by Item.Value of items returned by iterators.
+	FaultIterate FaultOperation = "iterate"
+)
+
+// FaultRule defines when and how a FaultDB injects a fault.
+// All set conditions must match, a rule without conditions matches every operation.
+type FaultRule struct {
+	// Operation the rule applies to, empty matches all operations.
+	Operation FaultOperation
+	// BucketName the rule applies to, nil matches all buckets.
+	// Operations without bucket like FaultUpdate do not match a BucketName.
+	BucketName BucketName
+	// KeyPrefix the rule applies to, nil matches all keys.
+	// Operations without key like FaultBucket do not match a KeyPrefix.
+	KeyPrefix []byte
+	// Nth fires the rule only on the nth matching call, zero fires on every call.
+	Nth int
+	// Probability fires the rule randomly with the given probability (0 to 1),
+	// zero fires always.
+	Probability float64
+	// Latency is waited before the operation, or until the context is done.
+	Latency time.Duration
+	// Corrupt flips the first byte of values returned by FaultGet and FaultIterate.
+	Corrupt bool
+	// Error returned by the operation. If neither Latency nor Corrupt is set,
+	// nil means ErrFaultInjected.
+	Error error
+}
+
+//counterfeiter:generate -o mocks/fault-db.go --fake-name FaultDB . FaultDB
+
+// FaultDB is a DB injecting faults defined by rules, for resilience tests.
+type FaultDB interface {
+	DB
+	// AddRule adds a rule, the first fired rule with an error wins.
+	AddRule(rule FaultRule)
+	// ClearRules removes all rules.
+	ClearRules()
+	// Seed resets the random source used for Probability.
+	Seed(seed uint64)
+	// Injected returns the number of fired rules.
+	Injected() int
+}
+
+// NewFaultDB wraps a DB to inject faults by the given rules.
+// Without rules all operations are passed through unchanged.
+// Probability uses a random source with a fixed seed, so runs are reproducible.
+func NewFaultDB(db DB, rules ...FaultRule) FaultDB {
+	f := &faultDB{
+		db: db,
+	}
+	f.Seed(0)
+	for _, rule := range rules {
+		f.AddRule(rule)
+	}
+	return f
+}
+
+type faultDB struct {
+	db DB
+
+	mux      sync.Mutex
+	rules    []*faultRuleState
+	random   *rand.Rand
+	injected int
+}
+
+type faultRuleState struct {
+	rule  FaultRule
+	calls int
+}
+
+func (f *faultDB) AddRule(rule FaultRule) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.rules = append(f.rules, &faultRuleState{rule: rule})
+}
+
+func (f *faultDB) ClearRules() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.rules = nil
+}
+
+func (f *faultDB) Seed(seed uint64) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.random = rand.New(rand.NewPCG(seed, seed))
+}
+
+func (f *faultDB) Injected() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.injected
+}
+
+// fired returns all rules firing for the operation.
+func (f *faultDB) fired(operation FaultOperation, bucketName BucketName, key []byte) []FaultRule {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	var result []FaultRule
+	for _, state := range f.rules {
+		rule := state.rule
+		if rule.Operation != "" && rule.Operation != operation {
+			continue
+		}
+		if rule.BucketName != nil && !rule.BucketName.Equal(bucketName) {
+			continue
+		}
+		if rule.KeyPrefix != nil && (key == nil || !bytes.HasPrefix(key, rule.KeyPrefix)) {
+			continue
+		}
+		state.calls++
+		if rule.Nth > 0 && state.calls != rule.Nth {
+			continue
+		}
+		if rule.Probability > 0 && f.random.Float64() >= rule.Probability {
+			continue
+		}
+		f.injected++
+		result = append(result, rule)
+	}
+	return result
+}
+
+// inject waits the latency of all fired rules and returns whether the value
+// must be corrupted or the error of the first fired rule with an error.
+func (f *faultDB) inject(
+	ctx context.Context,
+	operation FaultOperation,
+	bucketName BucketName,
+	key []byte,
+) (bool, error) {
+	corrupt := false
+	for _, rule := range f.fired(operation, bucketName, key) {
+		if rule.Latency > 0 {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-time.After(rule.Latency):
+			}
+		}
+		corrupt = corrupt || rule.Corrupt
+		err := rule.Error
+		if err == nil && rule.Latency == 0 && !rule.Corrupt {
+			err = ErrFaultInjected
+		}
+		if err != nil {
+			return false, errors.Wrapf(ctx, err, "inject fault into %s", operation)
+		}
+	}
+	return corrupt, nil
+}
+
+func (f *faultDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if _, err := f.inject(ctx, FaultUpdate, nil, nil); err != nil {
+		return err
+	}
+	return f.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		if err := fn(ctx, &faultTx{tx: tx, db: f, ctx: ctx}); err != nil {
+			return err
+		}
+		_, err := f.inject(ctx, FaultCommit, nil, nil)
+		return err
+	})
+}
+
+func (f *faultDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if _, err := f.inject(ctx, FaultView, nil, nil); err != nil {
+		return err
+	}
+	return f.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &faultTx{tx: tx, db: f, ctx: ctx})
+	})
+}
+
+func (f *faultDB) Sync() error {
+	return f.db.Sync()
+}
+
+func (f *faultDB) Close() error {
+	return f.db.Close()
+}
+
+func (f *faultDB) Remove() error {
+	return f.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (f *faultDB) ReadOnly() bool {
+	return IsReadOnly(f.db)
+}
+
+func (f *faultDB) Stats(ctx context.Context) (*Stats, error) {
+	return f.db.Stats(ctx)
+}
+
+func (f *faultDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return f.db.StatsDetailed(ctx)
+}
+
+type faultTx struct {
+	tx Tx
+	db *faultDB
+	// ctx of the transaction, used by iterators
+	ctx context.Context
+}
+
+func (f *faultTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	if _, err := f.db.inject(ctx, FaultBucket, name, nil); err != nil {
+		return nil, err
+	}
+	bucket, err := f.tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrap(name, bucket), nil
+}
+
+func (f *faultTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	if _, err := f.db.inject(ctx, FaultCreateBucket, name, nil); err != nil {
+		return nil, err
+	}
+	bucket, err := f.tx.CreateBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrap(name, bucket), nil
+}
+
+func (f *faultTx) CreateBucketIfNotExists(ctx context.Context, name BucketName) (Bucket, error) {
+	if _, err := f.db.inject(ctx, FaultCreateBucket, name, nil); err != nil {
+		return nil, err
+	}
+	bucket, err := f.tx.CreateBucketIfNotExists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrap(name, bucket), nil
+}
+
+func (f *faultTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	if _, err := f.db.inject(ctx, FaultDeleteBucket, name, nil); err != nil {
+		return err
+	}
+	return f.tx.DeleteBucket(ctx, name)
+}
+
+func (f *faultTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	if _, err := f.db.inject(ctx, FaultListBucketNames, nil, nil); err != nil {
+		return nil, err
+	}
+	return f.tx.ListBucketNames(ctx)
+}
+
+func (f *faultTx) wrap(name BucketName, bucket Bucket) Bucket {
+	return &faultBucket{
+		bucket: bucket,
+		tx:     f,
+		name:   name,
+	}
+}
+
+type faultBucket struct {
+	bucket Bucket
+	tx     *faultTx
+	name   BucketName
+}
+
+func (f *faultBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if _, err := f.tx.db.inject(ctx, FaultPut, f.name, key); err != nil {
+		return err
+	}
+	return f.bucket.Put(ctx, key, value)
+}
+
+func (f *faultBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	corrupt, err := f.tx.db.inject(ctx, FaultGet, f.name, key)
+	if err != nil {
+		return nil, err
+	}
+	item, err := f.bucket.Get(ctx, key)
+	if err != nil || !corrupt {
+		return item, err
+	}
+	value, err := itemValue(item)
+	if err != nil {
+		return nil, err
+	}
+	return NewByteItem(key, corruptValue(value)), nil
+}
+
+func (f *faultBucket) Delete(ctx context.Context, key []byte) error {
+	if _, err := f.tx.db.inject(ctx, FaultDelete, f.name, key); err != nil {
+		return err
+	}
+	return f.bucket.Delete(ctx, key)
+}
+
+func (f *faultBucket) Iterator() Iterator {
+	return &faultIterator{
+		Iterator: f.bucket.Iterator(),
+		bucket:   f,
+	}
+}
+
+func (f *faultBucket) IteratorReverse() Iterator {
+	return &faultIterator{
+		Iterator: f.bucket.IteratorReverse(),
+		bucket:   f,
+	}
+}
+
+type faultIterator struct {
+	Iterator
+	bucket *faultBucket
+}
+
+func (f *faultIterator) Item() Item {
+	return &faultItem{
+		Item:   f.Iterator.Item(),
+		bucket: f.bucket,
+	}
+}
+
+type faultItem struct {
+	Item
+	bucket *faultBucket
+}
+
+func (f *faultItem) Value(fn func(val []byte) error) error {
+	ctx := f.bucket.tx.ctx
+	corrupt, err := f.bucket.tx.db.inject(ctx, FaultIterate, f.bucket.name, f.Key())
+	if err != nil {
+		return err
+	}
+	if !corrupt {
+		return f.Item.Value(fn)
+	}
+	return f.Item.Value(func(val []byte) error {
+		return fn(corruptValue(val))
+	})
+}
+
+// corruptValue returns a copy of the value with the first byte flipped.
+func corruptValue(value []byte) []byte {
+	result := bytes.Clone(value)
+	if len(result) > 0 {
+		result[0] ^= 0xff
+	}
+	return result
+}
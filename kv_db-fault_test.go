@@ -0,0 +1,153 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("FaultDB", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var db kv.FaultDB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		db = kv.NewFaultDB(backend)
+		bucketName = kv.NewBucketName("bucket")
+		Expect(backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "user-1", "user-2"} {
+				if err := bucket.Put(ctx, []byte(key), []byte(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	})
+	put := func(key string) error {
+		return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(key))
+		})
+	}
+	get := func(db kv.DB, key string) (string, error) {
+		var result string
+		err := db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})
+		return result, err
+	}
+	It("returns errors for matching operations and key prefixes", func() {
+		banana := errors.New("banana")
+		db.AddRule(kv.FaultRule{Operation: kv.FaultPut, KeyPrefix: []byte("user-"), Error: banana})
+		Expect(put("a")).To(Succeed())
+		Expect(errors.Is(put("user-3"), banana)).To(BeTrue())
+		Expect(db.Injected()).To(Equal(1))
+
+		db.ClearRules()
+		Expect(put("user-3")).To(Succeed())
+	})
+	It("fires on the nth call only", func() {
+		db.AddRule(kv.FaultRule{Operation: kv.FaultUpdate, Nth: 2})
+		Expect(put("b")).To(Succeed())
+		Expect(errors.Is(put("c"), kv.ErrFaultInjected)).To(BeTrue())
+		Expect(put("d")).To(Succeed())
+	})
+	It("fires reproducibly by probability", func() {
+		db.AddRule(kv.FaultRule{Operation: kv.FaultView, Probability: 0.5})
+		run := func() []bool {
+			db.Seed(42)
+			var result []bool
+			for i := 0; i < 20; i++ {
+				_, err := get(db, "a")
+				result = append(result, err != nil)
+			}
+			return result
+		}
+		first := run()
+		Expect(first).To(ContainElement(true))
+		Expect(first).To(ContainElement(false))
+		Expect(run()).To(Equal(first))
+	})
+	It("fails the commit after fn succeeded and rolls back", func() {
+		db.AddRule(kv.FaultRule{Operation: kv.FaultCommit})
+		called := false
+		err := db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			called = true
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("b"), []byte("b"))
+		})
+		Expect(errors.Is(err, kv.ErrFaultInjected)).To(BeTrue())
+		Expect(called).To(BeTrue())
+		Expect(get(backend, "b")).To(Equal(""))
+	})
+	It("adds latency", func() {
+		db.AddRule(kv.FaultRule{Operation: kv.FaultGet, Latency: 50 * time.Millisecond})
+		start := time.Now()
+		Expect(get(db, "a")).To(Equal("a"))
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+	})
+	It("returns corrupted values of the bucket", func() {
+		db.AddRule(kv.FaultRule{BucketName: bucketName, KeyPrefix: []byte("user-"), Corrupt: true})
+		Expect(get(db, "a")).To(Equal("a"))
+		Expect(get(db, "user-1")).NotTo(Equal("user-1"))
+		Expect(get(backend, "user-1")).To(Equal("user-1"))
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			var values []string
+			err = kv.ForEach(ctx, bucket, func(item kv.Item) error {
+				return item.Value(func(val []byte) error {
+					values = append(values, string(val))
+					return nil
+				})
+			})
+			Expect(values).To(HaveLen(3))
+			Expect(values[0]).To(Equal("a"))
+			Expect(values[1]).NotTo(Equal("user-1"))
+			return err
+		})).To(Succeed())
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewFaultDB(newMemoryDB()), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+)
+
+// NewDBWithIntegrity wraps a DB to store a checksum with each value of the given buckets,
+// or of all buckets if none is given. Reading a value with a wrong checksum returns a
+// CorruptValueError. All values of these buckets must be written through the wrapper.
+func NewDBWithIntegrity(
+	db DB,
+	checksum IntegrityChecksum,
+	bucketNames ...BucketName,
+) DB {
+	return newDBWithValueTransform(
+		db,
+		&integrityTransform{checksum: checksum},
+		bucketNames,
+	)
+}
+
+// NewIntegrityBucket wraps a Bucket to store a checksum with each value, see NewDBWithIntegrity.
+func NewIntegrityBucket(
+	bucket Bucket,
+	bucketName BucketName,
+	checksum IntegrityChecksum,
+) Bucket {
+	return newValueTransformBucket(
+		bucket,
+		bucketName,
+		&integrityTransform{checksum: checksum},
+	)
+}
+
+type integrityTransform struct {
+	checksum IntegrityChecksum
+}
+
+// encode returns the checksum followed by the value.
+func (i *integrityTransform) encode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	result := make([]byte, 0, i.checksum.Size()+len(value))
+	result = append(result, i.checksum.Sum(bucketName, key, value)...)
+	return append(result, value...), nil
+}
+
+func (i *integrityTransform) decode(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) ([]byte, error) {
+	if err := i.verify(bucketName, key, value); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(value[i.checksum.Size():]), nil
+}
+
+func (i *integrityTransform) verify(bucketName BucketName, key []byte, value []byte) error {
+	size := i.checksum.Size()
+	if len(value) < size ||
+		!hmac.Equal(value[:size], i.checksum.Sum(bucketName, key, value[size:])) {
+		return CorruptValueError{
+			BucketName: bytes.Clone(bucketName),
+			Key:        bytes.Clone(key),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("DBWithIntegrity", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var checksum kv.IntegrityChecksum
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		checksum = kv.NewCRC32CChecksum()
+		db = kv.NewDBWithIntegrity(backend, checksum)
+		bucketName = kv.NewBucketName("bucket")
+	})
+	put := func(db kv.DB, key string, value string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})).To(Succeed())
+	}
+	get := func(db kv.DB, key string) (string, error) {
+		var result string
+		err := db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})
+		return result, err
+	}
+	corrupt := func(key string) {
+		stored, err := get(backend, key)
+		Expect(err).To(BeNil())
+		put(backend, key, stored[:len(stored)-1]+"X")
+	}
+	It("stores the checksum with the value", func() {
+		put(db, "key", "value")
+		Expect(get(db, "key")).To(Equal("value"))
+		stored, err := get(backend, "key")
+		Expect(err).To(BeNil())
+		Expect(stored).To(HaveLen(checksum.Size() + len("value")))
+	})
+	It("returns a CorruptValueError for modified values", func() {
+		put(db, "key", "value")
+		corrupt("key")
+		_, err := get(db, "key")
+		Expect(errors.Is(err, kv.ErrCorruptValue)).To(BeTrue())
+		var corruptValueError kv.CorruptValueError
+		Expect(errors.As(err, &corruptValueError)).To(BeTrue())
+		Expect(corruptValueError.BucketName).To(Equal(bucketName))
+		Expect(string(corruptValueError.Key)).To(Equal("key"))
+	})
+	It("returns a CorruptValueError for values moved to another key", func() {
+		put(db, "a", "value")
+		stored, err := get(backend, "a")
+		Expect(err).To(BeNil())
+		put(backend, "b", stored)
+		_, err = get(db, "b")
+		Expect(errors.Is(err, kv.ErrCorruptValue)).To(BeTrue())
+	})
+	It("returns a CorruptValueError from Value of iterator items", func() {
+		put(db, "key", "value")
+		corrupt("key")
+		err := db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return kv.ForEach(ctx, bucket, func(item kv.Item) error {
+				return item.Value(func(val []byte) error {
+					return nil
+				})
+			})
+		})
+		Expect(errors.Is(err, kv.ErrCorruptValue)).To(BeTrue())
+	})
+	It("detects values modified without the HMAC secret", func() {
+		db = kv.NewDBWithIntegrity(backend, kv.NewHMACChecksum([]byte("secret")))
+		put(db, "key", "value")
+		Expect(get(db, "key")).To(Equal("value"))
+		other := kv.NewDBWithIntegrity(backend, kv.NewHMACChecksum([]byte("other")))
+		put(other, "key", "forged")
+		_, err := get(db, "key")
+		Expect(errors.Is(err, kv.ErrCorruptValue)).To(BeTrue())
+	})
+	Context("Scrub", func() {
+		BeforeEach(func() {
+			put(db, "a", "value")
+			put(db, "b", "value")
+			put(db, "c", "value")
+			corrupt("b")
+		})
+		It("reports corrupted values", func() {
+			result, err := kv.Scrub(ctx, backend, checksum, bucketName)
+			Expect(err).To(BeNil())
+			Expect(result.Buckets).To(Equal(1))
+			Expect(result.Checked).To(Equal(3))
+			Expect(result.Corrupted).To(HaveLen(1))
+			Expect(result.Corrupted[0].BucketName).To(Equal(bucketName))
+			Expect(string(result.Corrupted[0].Key)).To(Equal("b"))
+		})
+		It("checks only the given buckets", func() {
+			plainName := kv.NewBucketName("plain")
+			Expect(backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				bucket, err := tx.CreateBucket(ctx, plainName)
+				if err != nil {
+					return err
+				}
+				return bucket.Put(ctx, []byte("key"), []byte("plain value"))
+			})).To(Succeed())
+			db = kv.NewDBWithIntegrity(backend, checksum, bucketName)
+			put(db, "d", "value")
+			result, err := kv.Scrub(ctx, backend, checksum, bucketName)
+			Expect(err).To(BeNil())
+			Expect(result.Buckets).To(Equal(1))
+			Expect(result.Checked).To(Equal(4))
+			Expect(result.Corrupted).To(HaveLen(1))
+			Expect(string(result.Corrupted[0].Key)).To(Equal("b"))
+		})
+		It("checks all buckets without bucket names", func() {
+			otherName := kv.NewBucketName("other")
+			Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				bucket, err := tx.CreateBucket(ctx, otherName)
+				if err != nil {
+					return err
+				}
+				return bucket.Put(ctx, []byte("key"), []byte("value"))
+			})).To(Succeed())
+			result, err := kv.Scrub(ctx, backend, checksum)
+			Expect(err).To(BeNil())
+			Expect(result.Buckets).To(Equal(2))
+			Expect(result.Checked).To(Equal(4))
+			Expect(result.Corrupted).To(HaveLen(1))
+			Expect(string(result.Corrupted[0].Key)).To(Equal("b"))
+		})
+		It("skips missing buckets", func() {
+			result, err := kv.Scrub(ctx, backend, checksum, kv.NewBucketName("missing"))
+			Expect(err).To(BeNil())
+			Expect(result.Checked).To(Equal(0))
+		})
+		It("lists corrupted values in the handler", func() {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/scrub", nil)
+			kv.NewScrubHandler(backend, checksum, bucketName).ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(
+				ContainSubstring("3 values in 1 buckets, 1 corrupted"),
+			)
+			Expect(recorder.Body.String()).To(ContainSubstring(`bucket "b"`))
+		})
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewDBWithIntegrity(newMemoryDB(), kv.NewCRC32CChecksum()), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -60,3 +60,8 @@ func (d *dbWithMetrics) Close() error {
 func (d *dbWithMetrics) Remove() error {
 	return d.db.Remove()
 }
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithMetrics) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+var (
+	// mirrorSequenceKey stores the last sequence of the change log in the primary.
+	mirrorSequenceKey = []byte("sequence")
+	// mirrorPositionKey stores the last applied sequence in the secondary.
+	mirrorPositionKey = []byte("position")
+)
+
+// NewMirrorDB returns a DB writing to primary and applying every committed write
+// to secondary as well. Each Update stores its changes in a change log of the primary
+// within the same transaction, so changes are applied in commit order even after the
+// secondary was offline. The applied position is stored in the secondary together with
+// the changes. The secondary must start as a copy of the primary, existing data is not copied.
+// Reads are served by the primary.
+func NewMirrorDB(
+	primary DB,
+	secondary DB,
+	options MirrorOptions,
+	metrics MirrorMetrics,
+) MirrorDB {
+	defaults := DefaultMirrorOptions()
+	if options.Interval <= 0 {
+		options.Interval = defaults.Interval
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = defaults.ChunkSize
+	}
+	if len(options.ChangeLogBucketName) == 0 {
+		options.ChangeLogBucketName = defaults.ChangeLogBucketName
+	}
+	if len(options.PositionBucketName) == 0 {
+		options.PositionBucketName = defaults.PositionBucketName
+	}
+	return &mirrorDB{
+		primary:   primary,
+		secondary: secondary,
+		options:   options,
+		metrics:   metrics,
+		trigger:   make(chan struct{}, 1),
+	}
+}
+
+type mirrorDB struct {
+	primary   DB
+	secondary DB
+	options   MirrorOptions
+	metrics   MirrorMetrics
+
+	// mux serializes catch-ups
+	mux     sync.Mutex
+	trigger chan struct{}
+}
+
+func (m *mirrorDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	var changes []mirrorChange
+	err := m.primary.Update(ctx, func(ctx context.Context, tx Tx) error {
+		recorder := &mirrorTx{Tx: tx}
+		if err := fn(ctx, recorder); err != nil {
+			return err
+		}
+		changes = recorder.changes
+		return m.appendChangeLog(ctx, tx, changes)
+	})
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if m.options.Mode == MirrorModeAsync {
+		select {
+		case m.trigger <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+	if _, err := m.CatchUp(ctx); err != nil {
+		return errors.Wrapf(ctx, ErrMirrorFailed, "catch up failed: %v", err)
+	}
+	return nil
+}
+
+// appendChangeLog stores the changes with the next sequence.
+func (m *mirrorDB) appendChangeLog(ctx context.Context, tx Tx, changes []mirrorChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	positions, err := tx.CreateBucketIfNotExists(ctx, m.options.PositionBucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get position bucket failed")
+	}
+	sequence, err := mirrorSequence(ctx, positions, mirrorSequenceKey)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get sequence failed")
+	}
+	sequence++
+	value, err := json.Marshal(changes)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	changeLog, err := tx.CreateBucketIfNotExists(ctx, m.options.ChangeLogBucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get change log bucket failed")
+	}
+	if err := changeLog.Put(ctx, outboxKey(sequence), value); err != nil {
+		return errors.Wrapf(ctx, err, "put change failed")
+	}
+	if err := positions.Put(ctx, mirrorSequenceKey, outboxKey(sequence)); err != nil {
+		return errors.Wrapf(ctx, err, "put sequence failed")
+	}
+	return nil
+}
+
+// View reads from the primary. With ReadCompare Get also reads from the secondary,
+// reads are not compared if the secondary is not available.
+func (m *mirrorDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if !m.options.ReadCompare {
+		return m.primary.View(ctx, fn)
+	}
+	return m.primary.View(ctx, func(txCtx context.Context, tx Tx) error {
+		var called bool
+		// open with the context of the caller, so the secondary does not see a nested transaction
+		err := m.secondary.View(ctx, func(_ context.Context, secondaryTx Tx) error {
+			called = true
+			return fn(txCtx, &mirrorCompareTx{
+				Tx:          tx,
+				secondaryTx: secondaryTx,
+				metrics:     m.metrics,
+			})
+		})
+		if called {
+			return err
+		}
+		glog.V(2).Infof("read compare skipped, open secondary failed: %v", err)
+		m.metrics.MirrorFailureInc()
+		return fn(txCtx, tx)
+	})
+}
+
+func (m *mirrorDB) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.options.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.CatchUp(ctx); err != nil {
+			glog.Warningf("mirror catch up failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-m.trigger:
+		}
+	}
+}
+
+func (m *mirrorDB) Sync() error {
+	if err := m.primary.Sync(); err != nil {
+		return err
+	}
+	return m.secondary.Sync()
+}
+
+func (m *mirrorDB) Close() error {
+	if err := m.primary.Close(); err != nil {
+		return err
+	}
+	return m.secondary.Close()
+}
+
+func (m *mirrorDB) Remove() error {
+	if err := m.primary.Remove(); err != nil {
+		return err
+	}
+	return m.secondary.Remove()
+}
+
+// ReadOnly reports whether primary or secondary is read only.
+func (m *mirrorDB) ReadOnly() bool {
+	return IsReadOnly(m.primary) || IsReadOnly(m.secondary)
+}
+
+func (m *mirrorDB) Stats(ctx context.Context) (*Stats, error) {
+	return m.primary.Stats(ctx)
+}
+
+func (m *mirrorDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return m.primary.StatsDetailed(ctx)
+}
+
+// mirrorTx records all writes of a transaction.
+type mirrorTx struct {
+	Tx
+	changes []mirrorChange
+}
+
+func (m *mirrorTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := m.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return m.wrap(name, bucket), nil
+}
+
+func (m *mirrorTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := m.Tx.CreateBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	m.record(mirrorOperationCreateBucket, name, nil, nil)
+	return m.wrap(name, bucket), nil
+}
+
+func (m *mirrorTx) CreateBucketIfNotExists(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := m.Tx.CreateBucketIfNotExists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	m.record(mirrorOperationCreateBucket, name, nil, nil)
+	return m.wrap(name, bucket), nil
+}
+
+func (m *mirrorTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	if err := m.Tx.DeleteBucket(ctx, name); err != nil {
+		return err
+	}
+	m.record(mirrorOperationDeleteBucket, name, nil, nil)
+	return nil
+}
+
+func (m *mirrorTx) record(
+	operation mirrorOperation,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) {
+	m.changes = append(m.changes, mirrorChange{
+		Operation:  operation,
+		BucketName: bytes.Clone(bucketName),
+		Key:        bytes.Clone(key),
+		Value:      bytes.Clone(value),
+	})
+}
+
+func (m *mirrorTx) wrap(name BucketName, bucket Bucket) Bucket {
+	return &mirrorBucket{
+		Bucket: bucket,
+		tx:     m,
+		name:   name,
+	}
+}
+
+type mirrorBucket struct {
+	Bucket
+	tx   *mirrorTx
+	name BucketName
+}
+
+func (m *mirrorBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if err := m.Bucket.Put(ctx, key, value); err != nil {
+		return err
+	}
+	m.tx.record(mirrorOperationPut, m.name, key, value)
+	return nil
+}
+
+func (m *mirrorBucket) Delete(ctx context.Context, key []byte) error {
+	if err := m.Bucket.Delete(ctx, key); err != nil {
+		return err
+	}
+	m.tx.record(mirrorOperationDelete, m.name, key, nil)
+	return nil
+}
+
+// mirrorCompareTx compares Get of the primary with the secondary.
+type mirrorCompareTx struct {
+	Tx
+	secondaryTx Tx
+	metrics     MirrorMetrics
+}
+
+func (m *mirrorCompareTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := m.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &mirrorCompareBucket{
+		Bucket: bucket,
+		tx:     m,
+		name:   name,
+	}, nil
+}
+
+type mirrorCompareBucket struct {
+	Bucket
+	tx   *mirrorCompareTx
+	name BucketName
+}
+
+func (m *mirrorCompareBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	item, err := m.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	primary, err := itemValue(item)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := mirrorGet(ctx, m.tx.secondaryTx, m.name, key)
+	if err != nil {
+		glog.V(2).Infof("read compare of %s in bucket %s failed: %v", key, m.name, err)
+		m.tx.metrics.MirrorFailureInc()
+		return item, nil
+	}
+	if !bytes.Equal(primary, secondary) {
+		glog.V(2).Infof("read mismatch of %s in bucket %s", key, m.name)
+		m.tx.metrics.MirrorReadMismatchInc(m.name)
+	}
+	return item, nil
+}
+
+// mirrorGet returns the value of the key or nil if the bucket or key does not exist.
+func mirrorGet(ctx context.Context, tx Tx, bucketName BucketName, key []byte) ([]byte, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	item, err := bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return itemValue(item)
+}
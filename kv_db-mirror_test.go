@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("MirrorDB", func() {
+	var ctx context.Context
+	var primary *memoryDB
+	var secondary *memoryDB
+	var metrics *mocks.MirrorMetrics
+	var options kv.MirrorOptions
+	var db kv.MirrorDB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		primary = newMemoryDB()
+		secondary = newMemoryDB()
+		metrics = &mocks.MirrorMetrics{}
+		options = kv.MirrorOptions{}
+		bucketName = kv.NewBucketName("bucket")
+	})
+	JustBeforeEach(func() {
+		db = kv.NewMirrorDB(primary, secondary, options, metrics)
+	})
+	put := func(db kv.DB, key string, value string) error {
+		return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte(key), []byte(value))
+		})
+	}
+	get := func(db kv.DB, key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if errors.Is(err, kv.ErrBucketNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	changeLogSize := func() int {
+		var result int
+		Expect(primary.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, kv.DefaultMirrorOptions().ChangeLogBucketName)
+			if err != nil {
+				return err
+			}
+			result = len(collectKeys(bucket.Iterator(), nil))
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	Context("sync", func() {
+		It("applies writes to the secondary", func() {
+			Expect(put(db, "a", "1")).To(Succeed())
+			Expect(put(db, "b", "2")).To(Succeed())
+			Expect(get(secondary, "a")).To(Equal("1"))
+			Expect(get(secondary, "b")).To(Equal("2"))
+			Expect(db.Position(ctx)).To(Equal(uint64(2)))
+			Expect(changeLogSize()).To(Equal(0))
+			Expect(metrics.MirrorAppliedAddCallCount()).To(Equal(2))
+		})
+		It("applies deletes to the secondary", func() {
+			Expect(put(db, "a", "1")).To(Succeed())
+			Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				bucket, err := tx.Bucket(ctx, bucketName)
+				if err != nil {
+					return err
+				}
+				return bucket.Delete(ctx, []byte("a"))
+			})).To(Succeed())
+			Expect(get(secondary, "a")).To(Equal(""))
+			Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return tx.DeleteBucket(ctx, bucketName)
+			})).To(Succeed())
+			Expect(secondary.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+				_, err := tx.Bucket(ctx, bucketName)
+				return err
+			})).To(MatchError(kv.ErrBucketNotFound))
+		})
+		It("does not log failed transactions", func() {
+			err := db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+				Expect(err).To(BeNil())
+				Expect(bucket.Put(ctx, []byte("a"), []byte("1"))).To(Succeed())
+				return errors.New("banana")
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(db.Position(ctx)).To(Equal(uint64(0)))
+			Expect(get(secondary, "a")).To(Equal(""))
+		})
+		It("catches up after the secondary was offline", func() {
+			offline := &mocks.DB{}
+			offline.UpdateReturns(errors.New("offline"))
+			mirror := kv.NewMirrorDB(primary, offline, options, metrics)
+			err := put(mirror, "a", "1")
+			Expect(errors.Is(err, kv.ErrMirrorFailed)).To(BeTrue())
+			Expect(get(primary, "a")).To(Equal("1"))
+			Expect(metrics.MirrorFailureIncCallCount()).To(Equal(1))
+			Expect(put(mirror, "b", "2")).NotTo(Succeed())
+			Expect(changeLogSize()).To(Equal(2))
+
+			count, err := db.CatchUp(ctx)
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(2))
+			Expect(get(secondary, "a")).To(Equal("1"))
+			Expect(get(secondary, "b")).To(Equal("2"))
+			Expect(changeLogSize()).To(Equal(0))
+		})
+	})
+	Context("async", func() {
+		BeforeEach(func() {
+			options.Mode = kv.MirrorModeAsync
+			options.ChunkSize = 2
+		})
+		It("applies writes in CatchUp", func() {
+			for _, key := range []string{"a", "b", "c"} {
+				Expect(put(db, key, key)).To(Succeed())
+			}
+			Expect(get(secondary, "a")).To(Equal(""))
+			count, err := db.CatchUp(ctx)
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(3))
+			Expect(get(secondary, "c")).To(Equal("c"))
+			Expect(db.Position(ctx)).To(Equal(uint64(3)))
+		})
+		It("applies writes in Run", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() {
+				done <- db.Run(ctx)
+			}()
+			Expect(put(db, "a", "1")).To(Succeed())
+			Eventually(func() string {
+				return get(secondary, "a")
+			}).WithTimeout(time.Second).Should(Equal("1"))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+	Context("Verify", func() {
+		JustBeforeEach(func() {
+			Expect(put(db, "a", "1")).To(Succeed())
+			Expect(put(db, "b", "2")).To(Succeed())
+		})
+		It("finds no divergence", func() {
+			divergences, err := db.Verify(ctx)
+			Expect(err).To(BeNil())
+			Expect(divergences).To(BeEmpty())
+		})
+		It("reports divergences", func() {
+			Expect(put(secondary, "b", "changed")).To(Succeed())
+			Expect(put(secondary, "c", "3")).To(Succeed())
+			divergences, err := db.Verify(ctx)
+			Expect(err).To(BeNil())
+			Expect(divergences).To(Equal([]kv.MirrorDivergence{
+				{
+					BucketName: bucketName,
+					Key:        []byte("b"),
+					Primary:    []byte("2"),
+					Secondary:  []byte("changed"),
+				},
+				{
+					BucketName: bucketName,
+					Key:        []byte("c"),
+					Secondary:  []byte("3"),
+				},
+			}))
+			Expect(metrics.MirrorDivergenceIncCallCount()).To(Equal(2))
+		})
+	})
+	Context("read compare", func() {
+		BeforeEach(func() {
+			options.ReadCompare = true
+		})
+		It("counts mismatches", func() {
+			Expect(put(db, "a", "1")).To(Succeed())
+			Expect(get(db, "a")).To(Equal("1"))
+			Expect(metrics.MirrorReadMismatchIncCallCount()).To(Equal(0))
+			Expect(put(secondary, "a", "changed")).To(Succeed())
+			Expect(get(db, "a")).To(Equal("1"))
+			Expect(metrics.MirrorReadMismatchIncCallCount()).To(Equal(1))
+			Expect(metrics.MirrorReadMismatchIncArgsForCall(0)).To(Equal(bucketName))
+		})
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewMirrorDB(
+				newMemoryDB(),
+				newMemoryDB(),
+				kv.MirrorOptions{ReadCompare: true},
+				&mocks.MirrorMetrics{},
+			), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// NewNamespacedDB wraps a DB to prefix all bucket names with the namespace.
+// ListBucketNames and Stats only return the buckets of the namespace, without prefix.
+// Stores and RelationStores using the returned DB are isolated from other namespaces.
+// It returns ErrInvalidNamespace for namespaces failing Validate.
+func NewNamespacedDB(db DB, namespace Namespace) (DB, error) {
+	ctx := context.Background()
+	if err := namespace.Validate(ctx); err != nil {
+		return nil, errors.Wrapf(ctx, err, "create namespaced db failed")
+	}
+	return &namespacedDB{
+		db:        db,
+		namespace: namespace,
+	}, nil
+}
+
+type namespacedDB struct {
+	db        DB
+	namespace Namespace
+}
+
+func (n *namespacedDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return n.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, n.wrap(tx))
+	})
+}
+
+func (n *namespacedDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return n.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, n.wrap(tx))
+	})
+}
+
+func (n *namespacedDB) wrap(tx Tx) Tx {
+	return &namespacedTx{
+		tx:        tx,
+		namespace: n.namespace,
+	}
+}
+
+func (n *namespacedDB) Sync() error {
+	return n.db.Sync()
+}
+
+func (n *namespacedDB) Close() error {
+	return n.db.Close()
+}
+
+func (n *namespacedDB) Remove() error {
+	return n.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (n *namespacedDB) ReadOnly() bool {
+	return IsReadOnly(n.db)
+}
+
+func (n *namespacedDB) Stats(ctx context.Context) (*Stats, error) {
+	stats, err := n.db.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.filterStats(stats), nil
+}
+
+func (n *namespacedDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	stats, err := n.db.StatsDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.filterStats(stats), nil
+}
+
+// filterStats returns the buckets of the namespace. The size is the sum of their
+// sizes, so it is only known for detailed stats.
+func (n *namespacedDB) filterStats(stats *Stats) *Stats {
+	result := &Stats{
+		Backend:  stats.Backend,
+		Buckets:  []BucketStats{},
+		Detailed: stats.Detailed,
+	}
+	for _, bucket := range stats.Buckets {
+		if !n.namespace.Contains(bucket.Name) {
+			continue
+		}
+		bucket.Name = n.namespace.TrimBucketName(bucket.Name)
+		result.SizeB += bucket.SizeB
+		result.Buckets = append(result.Buckets, bucket)
+	}
+	return result
+}
+
+type namespacedTx struct {
+	tx        Tx
+	namespace Namespace
+}
+
+func (n *namespacedTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	return n.tx.Bucket(ctx, n.namespace.BucketName(name))
+}
+
+func (n *namespacedTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	return n.tx.CreateBucket(ctx, n.namespace.BucketName(name))
+}
+
+func (n *namespacedTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	return n.tx.CreateBucketIfNotExists(ctx, n.namespace.BucketName(name))
+}
+
+func (n *namespacedTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	return n.tx.DeleteBucket(ctx, n.namespace.BucketName(name))
+}
+
+func (n *namespacedTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	names, err := n.tx.ListBucketNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := BucketNames{}
+	for _, name := range names {
+		if n.namespace.Contains(name) {
+			result = append(result, n.namespace.TrimBucketName(name))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("NamespacedDB", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var tenantA kv.DB
+	var tenantB kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		var err error
+		tenantA, err = kv.NewNamespacedDB(backend, "a")
+		Expect(err).To(BeNil())
+		tenantB, err = kv.NewNamespacedDB(backend, "b")
+		Expect(err).To(BeNil())
+		bucketName = kv.NewBucketName("users")
+	})
+	listBucketNames := func(db kv.DB) []string {
+		var result []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			names, err := tx.ListBucketNames(ctx)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				result = append(result, name.String())
+			}
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	It("isolates stores of different namespaces", func() {
+		storeA := kv.NewStore[string, TestObject](tenantA, bucketName)
+		storeB := kv.NewStore[string, TestObject](tenantB, bucketName)
+		Expect(storeA.Add(ctx, "john", TestObject{Name: "John", Age: 30})).To(Succeed())
+		Expect(storeB.Add(ctx, "john", TestObject{Name: "Johnny", Age: 40})).To(Succeed())
+
+		object, err := storeA.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+		object, err = storeB.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("Johnny"))
+
+		Expect(listBucketNames(backend)).To(Equal([]string{"a:users", "b:users"}))
+		Expect(listBucketNames(tenantA)).To(Equal([]string{"users"}))
+	})
+	It("deletes only buckets of the namespace", func() {
+		Expect(kv.NewStore[string, TestObject](tenantA, bucketName).
+			Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+		Expect(kv.NewStore[string, TestObject](tenantB, bucketName).
+			Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+		Expect(tenantA.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			return tx.DeleteBucket(ctx, bucketName)
+		})).To(Succeed())
+		Expect(listBucketNames(backend)).To(Equal([]string{"b:users"}))
+		Expect(tenantA.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.Bucket(ctx, bucketName)
+			return err
+		})).To(MatchError(kv.ErrBucketNotFound))
+	})
+	It("filters stats", func() {
+		Expect(kv.NewStore[string, TestObject](tenantA, bucketName).
+			Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+		Expect(kv.NewStore[string, TestObject](tenantB, bucketName).
+			Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+		stats, err := tenantA.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.Buckets).To(HaveLen(1))
+		Expect(stats.Buckets[0].Name.String()).To(Equal("users"))
+		Expect(stats.Buckets[0].KeyCount).To(Equal(int64(1)))
+		total, err := backend.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.SizeB).To(Equal(total.SizeB / 2))
+	})
+	It("rejects empty and nested-looking namespaces", func() {
+		for _, namespace := range []kv.Namespace{"", "a:b", "a:", ":b"} {
+			_, err := kv.NewNamespacedDB(backend, namespace)
+			Expect(errors.Is(err, kv.ErrInvalidNamespace)).To(BeTrue(), string(namespace))
+			err = kv.DropNamespace(ctx, backend, namespace)
+			Expect(errors.Is(err, kv.ErrInvalidNamespace)).To(BeTrue(), string(namespace))
+			err = kv.ExportNamespace(ctx, backend, namespace, io.Discard)
+			Expect(errors.Is(err, kv.ErrInvalidNamespace)).To(BeTrue(), string(namespace))
+		}
+	})
+	Context("tools", func() {
+		BeforeEach(func() {
+			Expect(kv.NewStore[string, TestObject](tenantA, bucketName).
+				Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+			Expect(kv.NewStore[string, TestObject](tenantB, bucketName).
+				Add(ctx, "jane", TestObject{Name: "Jane"})).To(Succeed())
+		})
+		It("drops a namespace", func() {
+			Expect(kv.DropNamespace(ctx, backend, "a")).To(Succeed())
+			Expect(listBucketNames(backend)).To(Equal([]string{"b:users"}))
+		})
+		It("does not drop other namespaces with a nested-looking name", func() {
+			Expect(kv.DropNamespace(ctx, backend, "a:users")).NotTo(Succeed())
+			Expect(kv.DropNamespace(ctx, backend, "")).NotTo(Succeed())
+			Expect(listBucketNames(backend)).To(Equal([]string{"a:users", "b:users"}))
+		})
+		It("exports a namespace", func() {
+			buf := &bytes.Buffer{}
+			Expect(kv.ExportNamespace(ctx, backend, "a", buf)).To(Succeed())
+			decoder := json.NewDecoder(buf)
+			var entry kv.NamespaceExportEntry
+			Expect(decoder.Decode(&entry)).To(Succeed())
+			Expect(entry.BucketName.String()).To(Equal("users"))
+			Expect(string(entry.Key)).To(Equal("john"))
+			Expect(string(entry.Value)).To(ContainSubstring("John"))
+			Expect(errors.Is(decoder.Decode(&entry), io.EOF)).To(BeTrue())
+		})
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewNamespacedDB(newMemoryDB(), "tenant")
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,252 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/bborbe/errors"
+)
+
+// OverlayChange is a difference between an OverlayDB and its base.
+type OverlayChange struct {
+	BucketName BucketName
+	// BucketCreated is true if the bucket does not exist in the base.
+	BucketCreated bool
+	// BucketDeleted is true if the bucket of the base was deleted, Key is nil then.
+	// Later writes to the bucket are reported as separate changes.
+	BucketDeleted bool
+	Key           []byte
+	// BaseValue is the value of the key in the base, nil if it does not exist.
+	BaseValue []byte
+	// Value is the value of the key in the overlay, nil if it was deleted.
+	Value   []byte
+	Deleted bool
+}
+
+//counterfeiter:generate -o mocks/overlay-db.go --fake-name OverlayDB . OverlayDB
+
+// OverlayDB keeps all writes in memory on top of a base DB, which is never modified
+// except by Commit.
+type OverlayDB interface {
+	DB
+	// Diff returns all changes of the overlay compared to the current base.
+	Diff(ctx context.Context) ([]OverlayChange, error)
+	// Discard drops all changes of the overlay.
+	Discard(ctx context.Context) error
+	// Commit writes all changes of the overlay to the base in one transaction
+	// and discards them afterwards.
+	Commit(ctx context.Context) error
+}
+
+// NewOverlayDB returns an OverlayDB on top of base. Reads see the writes of the overlay
+// first, deleted keys and buckets are hidden by tombstones and iterators merge the overlay
+// with the base in key order. Update runs serialized and supports savepoints.
+// Remove fails with ErrReadOnly, Stats are the stats of the base.
+func NewOverlayDB(base DB) OverlayDB {
+	return &overlayDB{
+		base:  base,
+		layer: newSavepointLayer(0),
+	}
+}
+
+type overlayTxContextKey struct{}
+
+type overlayDB struct {
+	base DB
+
+	// writeMux serializes Update, Commit and Discard
+	writeMux sync.Mutex
+	// mux guards layer, which is never modified once it is set
+	mux   sync.RWMutex
+	layer *savepointLayer
+}
+
+func (o *overlayDB) current() *savepointLayer {
+	o.mux.RLock()
+	defer o.mux.RUnlock()
+	return o.layer
+}
+
+func (o *overlayDB) replace(layer *savepointLayer) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.layer = layer
+}
+
+// Update writes into a new layer on top of the overlay. If fn succeeds, the overlay is
+// replaced by a copy with the new layer applied, which only copies the changed buckets.
+// Savepoints still open when fn returns are released, like NewDBWithSavepoints does.
+func (o *overlayDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if ctx.Value(overlayTxContextKey{}) != nil {
+		return ErrTransactionAlreadyOpen
+	}
+	o.writeMux.Lock()
+	defer o.writeMux.Unlock()
+	current := o.current()
+	var next *savepointLayer
+	err := o.base.View(ctx, func(ctx context.Context, tx Tx) error {
+		written := newSavepointLayer(0)
+		overlayTx := newOverlayTx(tx, current, written)
+		ctx = context.WithValue(ctx, overlayTxContextKey{}, overlayTx)
+		if err := fn(ctx, overlayTx); err != nil {
+			return err
+		}
+		if len(overlayTx.layers) > 2 {
+			if err := overlayTx.Release(ctx, overlayTx.layers[2].id); err != nil {
+				return errors.Wrapf(ctx, err, "release open savepoints failed")
+			}
+		}
+		next = current.with(written)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	o.replace(next)
+	return nil
+}
+
+func (o *overlayDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	layer := o.current()
+	return o.base.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &readOnlyTx{tx: newOverlayTx(tx, layer)})
+	})
+}
+
+// newOverlayTx returns a Tx reading tx of the base with layers on top and writing into
+// the last layer.
+func newOverlayTx(tx Tx, layers ...*savepointLayer) *savepointTx {
+	return &savepointTx{
+		tx:      &readOnlyTx{tx: tx},
+		layers:  layers,
+		nextID:  layers[len(layers)-1].id,
+		buckets: map[string]*savepointBucket{},
+	}
+}
+
+func (o *overlayDB) Diff(ctx context.Context) ([]OverlayChange, error) {
+	layer := o.current()
+	var result []OverlayChange
+	err := o.base.View(ctx, func(ctx context.Context, tx Tx) error {
+		result = nil
+		for _, name := range sortedBucketChangeNames(layer.buckets) {
+			changes, err := overlayBucketDiff(ctx, tx, NewBucketName(name), layer.buckets[name])
+			if err != nil {
+				return errors.Wrapf(ctx, err, "diff bucket %s failed", name)
+			}
+			result = append(result, changes...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "diff failed")
+	}
+	return result, nil
+}
+
+// overlayBucketDiff returns the changes of a single bucket, without writes equal to the base.
+func overlayBucketDiff(
+	ctx context.Context,
+	tx Tx,
+	bucketName BucketName,
+	change *savepointBucketChange,
+) ([]OverlayChange, error) {
+	bucket, err := optionalBucket(ctx, tx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var result []OverlayChange
+	if change.deleted && bucket != nil {
+		result = append(result, OverlayChange{
+			BucketName:    bucketName,
+			BucketDeleted: true,
+		})
+	}
+	if change.deleted {
+		bucket = nil
+	}
+	if change.exists && bucket == nil {
+		result = append(result, OverlayChange{
+			BucketName:    bucketName,
+			BucketCreated: true,
+		})
+	}
+	for _, entry := range change.writes.entries {
+		var baseValue []byte
+		if bucket != nil {
+			item, err := bucket.Get(ctx, entry.key)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "get %s failed", entry.key)
+			}
+			if baseValue, err = itemValue(item); err != nil {
+				return nil, errors.Wrapf(ctx, err, "get value of %s failed", entry.key)
+			}
+		}
+		if entry.deleted && baseValue == nil {
+			continue
+		}
+		if !entry.deleted && bytes.Equal(baseValue, entry.value) {
+			continue
+		}
+		result = append(result, OverlayChange{
+			BucketName: bucketName,
+			Key:        entry.key,
+			BaseValue:  baseValue,
+			Value:      entry.value,
+			Deleted:    entry.deleted,
+		})
+	}
+	return result, nil
+}
+
+func (o *overlayDB) Discard(ctx context.Context) error {
+	o.writeMux.Lock()
+	defer o.writeMux.Unlock()
+	o.replace(newSavepointLayer(0))
+	return nil
+}
+
+func (o *overlayDB) Commit(ctx context.Context) error {
+	o.writeMux.Lock()
+	defer o.writeMux.Unlock()
+	layer := o.current()
+	err := o.base.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return (&savepointTx{tx: tx}).apply(ctx, layer)
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "commit overlay failed")
+	}
+	o.replace(newSavepointLayer(0))
+	return nil
+}
+
+func (o *overlayDB) Sync() error {
+	return o.base.Sync()
+}
+
+func (o *overlayDB) Close() error {
+	return o.base.Close()
+}
+
+func (o *overlayDB) Remove() error {
+	return errors.Wrapf(context.Background(), ErrReadOnly, "remove overlay failed")
+}
+
+// ReadOnly is false, because Update accepts writes even on a read-only base.
+// Reset handlers still refuse to remove the base, because Remove fails with ErrReadOnly.
+func (o *overlayDB) ReadOnly() bool {
+	return false
+}
+
+func (o *overlayDB) Stats(ctx context.Context) (*Stats, error) {
+	return o.base.Stats(ctx)
+}
+
+func (o *overlayDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return o.base.StatsDetailed(ctx)
+}
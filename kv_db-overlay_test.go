@@ -0,0 +1,210 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("OverlayDB", func() {
+	var ctx context.Context
+	var base *memoryDB
+	var db kv.OverlayDB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		base = newMemoryDB()
+		bucketName = kv.NewBucketName("bucket")
+		Expect(base.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "c", "e"} {
+				if err := bucket.Put(ctx, []byte(key), []byte("base")); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+		db = kv.NewOverlayDB(base)
+	})
+	update := func(fn func(bucket kv.Bucket) error) error {
+		return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return fn(bucket)
+		})
+	}
+	keys := func(db kv.DB) []string {
+		var result []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			result = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		Expect(update(func(bucket kv.Bucket) error {
+			if err := bucket.Put(ctx, []byte("b"), []byte("overlay")); err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("e"), []byte("overlay")); err != nil {
+				return err
+			}
+			return bucket.Delete(ctx, []byte("c"))
+		})).To(Succeed())
+	})
+	It("merges overlay and base", func() {
+		Expect(keys(db)).To(Equal([]string{"a", "b", "e"}))
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(collectKeys(bucket.IteratorReverse(), nil)).To(Equal([]string{"e", "b", "a"}))
+			item, err := bucket.Get(ctx, []byte("e"))
+			Expect(err).To(BeNil())
+			return item.Value(func(val []byte) error {
+				Expect(string(val)).To(Equal("overlay"))
+				return nil
+			})
+		})).To(Succeed())
+	})
+	It("does not modify the base", func() {
+		Expect(keys(base)).To(Equal([]string{"a", "c", "e"}))
+	})
+	It("discards failed updates", func() {
+		err := update(func(bucket kv.Bucket) error {
+			Expect(bucket.Delete(ctx, []byte("a"))).To(Succeed())
+			return errors.New("banana")
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(keys(db)).To(Equal([]string{"a", "b", "e"}))
+	})
+	It("does not modify the overlay seen by a running View", func() {
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			Expect(update(func(bucket kv.Bucket) error {
+				if err := bucket.Put(ctx, []byte("d"), []byte("overlay")); err != nil {
+					return err
+				}
+				return bucket.Delete(ctx, []byte("b"))
+			})).To(Succeed())
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(collectKeys(bucket.Iterator(), nil)).To(Equal([]string{"a", "b", "e"}))
+			return nil
+		})).To(Succeed())
+		Expect(keys(db)).To(Equal([]string{"a", "d", "e"}))
+	})
+	It("releases savepoints left open by fn", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			savepointTx := tx.(kv.SavepointTx)
+			if _, err := savepointTx.Savepoint(ctx); err != nil {
+				return err
+			}
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("d"), []byte("overlay")); err != nil {
+				return err
+			}
+			savepoint, err := savepointTx.Savepoint(ctx)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("f"), []byte("overlay")); err != nil {
+				return err
+			}
+			return savepointTx.RollbackTo(ctx, savepoint)
+		})).To(Succeed())
+		Expect(keys(db)).To(Equal([]string{"a", "b", "d", "e"}))
+	})
+	It("rejects writes in View", func() {
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return bucket.Put(ctx, []byte("x"), []byte("x"))
+		})).To(MatchError(kv.ErrReadOnly))
+	})
+	It("hides deleted buckets", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			return tx.DeleteBucket(ctx, bucketName)
+		})).To(Succeed())
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.Bucket(ctx, bucketName)
+			return err
+		})).To(MatchError(kv.ErrBucketNotFound))
+		changes, err := db.Diff(ctx)
+		Expect(err).To(BeNil())
+		Expect(changes).To(Equal([]kv.OverlayChange{
+			{BucketName: bucketName, BucketDeleted: true},
+		}))
+	})
+	It("returns the diff", func() {
+		Expect(update(func(bucket kv.Bucket) error {
+			return bucket.Put(ctx, []byte("a"), []byte("base"))
+		})).To(Succeed())
+		changes, err := db.Diff(ctx)
+		Expect(err).To(BeNil())
+		Expect(changes).To(Equal([]kv.OverlayChange{
+			{BucketName: bucketName, Key: []byte("b"), Value: []byte("overlay")},
+			{BucketName: bucketName, Key: []byte("c"), BaseValue: []byte("base"), Deleted: true},
+			{
+				BucketName: bucketName,
+				Key:        []byte("e"),
+				BaseValue:  []byte("base"),
+				Value:      []byte("overlay"),
+			},
+		}))
+	})
+	It("discards the overlay", func() {
+		Expect(db.Discard(ctx)).To(Succeed())
+		Expect(keys(db)).To(Equal([]string{"a", "c", "e"}))
+		changes, err := db.Diff(ctx)
+		Expect(err).To(BeNil())
+		Expect(changes).To(BeEmpty())
+	})
+	It("commits the overlay to the base", func() {
+		Expect(db.Commit(ctx)).To(Succeed())
+		Expect(keys(base)).To(Equal([]string{"a", "b", "e"}))
+		changes, err := db.Diff(ctx)
+		Expect(err).To(BeNil())
+		Expect(changes).To(BeEmpty())
+	})
+	It("refuses to remove the base", func() {
+		Expect(errors.Is(db.Remove(), kv.ErrReadOnly)).To(BeTrue())
+	})
+	It("is not read only, because it accepts writes", func() {
+		Expect(kv.IsReadOnly(db)).To(BeFalse())
+		Expect(kv.IsReadOnly(kv.NewOverlayDB(kv.NewReadOnlyDB(base)))).To(BeFalse())
+	})
+	It("syncs the base", func() {
+		mockDB := &mocks.DB{}
+		Expect(kv.NewOverlayDB(mockDB).Sync()).To(Succeed())
+		Expect(mockDB.SyncCallCount()).To(Equal(1))
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewOverlayDB(newMemoryDB()), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,238 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUpdateInViewTransaction is returned if Update tries to join a read only transaction.
+var ErrUpdateInViewTransaction = errors.New("update inside view transaction not allowed")
+
+// ErrNoActiveTransaction is returned for PropagationMandatory if no transaction is active.
+var ErrNoActiveTransaction = errors.New("no active transaction")
+
+// ErrRollbackOnly is returned if a joined transaction failed but the outer fn returned no error.
+var ErrRollbackOnly = errors.New("transaction marked as rollback only")
+
+// Propagation defines how Update and View of NewDBWithPropagation behave
+// if a transaction is already active in the context.
+type Propagation int
+
+const (
+	// PropagationRequired joins the active transaction or opens a new one. This is the default.
+	PropagationRequired Propagation = iota
+	// PropagationRequiresNew always opens a new, independent transaction.
+	// A write transaction inside a write transaction requires a backend with
+	// concurrent writers (badger); backends with a single writer (bolt) block.
+	PropagationRequiresNew
+	// PropagationNever fails with ErrTransactionAlreadyOpen if a transaction is active.
+	PropagationNever
+	// PropagationMandatory fails with ErrNoActiveTransaction if no transaction is active.
+	PropagationMandatory
+)
+
+// String returns the name of the propagation.
+func (p Propagation) String() string {
+	switch p {
+	case PropagationRequired:
+		return "Required"
+	case PropagationRequiresNew:
+		return "RequiresNew"
+	case PropagationNever:
+		return "Never"
+	case PropagationMandatory:
+		return "Mandatory"
+	default:
+		return fmt.Sprintf("Propagation(%d)", int(p))
+	}
+}
+
+type propagationContextKey struct{}
+
+type activeTransactionContextKey struct{}
+
+// WithPropagation returns a context that applies the given propagation
+// to the next Update or View of a DB created with NewDBWithPropagation.
+func WithPropagation(ctx context.Context, propagation Propagation) context.Context {
+	return context.WithValue(ctx, propagationContextKey{}, propagation)
+}
+
+// TxFromContext returns the transaction opened by NewDBWithPropagation for the given context.
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	active, ok := ctx.Value(activeTransactionContextKey{}).(*activeTransaction)
+	if !ok {
+		return nil, false
+	}
+	return active.tx, true
+}
+
+// NewDBWithPropagation wraps a DB so nested Update and View calls join the
+// transaction stored in the context instead of opening a new one.
+// A View inside an Update joins the write transaction, an Update inside
+// a View fails with ErrUpdateInViewTransaction.
+// If a joined fn returns an error, the outer transaction is rolled back even
+// if the outer fn ignores the error.
+// Wrap it outside of other wrappers like NewDBWithCallbacks so joined calls
+// do not reach them.
+func NewDBWithPropagation(db DB) DB {
+	return &dbWithPropagation{
+		db: db,
+	}
+}
+
+type dbWithPropagation struct {
+	db DB
+}
+
+type activeTransaction struct {
+	db           *dbWithPropagation
+	tx           Tx
+	writable     bool
+	rollbackOnly bool
+	// ctx is the context the transaction was opened with and txCtx the context the
+	// backend passed to fn, their difference are the markers of the backend transaction
+	ctx   context.Context
+	txCtx context.Context
+}
+
+func (d *dbWithPropagation) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.run(ctx, true, fn)
+}
+
+func (d *dbWithPropagation) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.run(ctx, false, fn)
+}
+
+func (d *dbWithPropagation) run(
+	ctx context.Context,
+	writable bool,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	propagation, _ := ctx.Value(propagationContextKey{}).(Propagation)
+	active, ok := ctx.Value(activeTransactionContextKey{}).(*activeTransaction)
+	if !ok || active.db != d {
+		if propagation == PropagationMandatory {
+			return ErrNoActiveTransaction
+		}
+		return d.open(ctx, writable, fn)
+	}
+	switch propagation {
+	case PropagationNever:
+		return ErrTransactionAlreadyOpen
+	case PropagationRequiresNew:
+		return d.open(requiresNewContext{Context: ctx, active: active}, writable, fn)
+	default:
+		return d.join(ctx, active, writable, fn)
+	}
+}
+
+// requiresNewContext is the context of the caller without the values the backend
+// added for the active transaction, which would reject the new transaction.
+// Values added by the caller inside the transaction are kept.
+type requiresNewContext struct {
+	context.Context
+	active *activeTransaction
+}
+
+func (c requiresNewContext) Value(key any) any {
+	value := c.Context.Value(key)
+	opened := c.active.ctx.Value(key)
+	if sameContextValue(value, c.active.txCtx.Value(key)) && !sameContextValue(value, opened) {
+		return opened
+	}
+	return value
+}
+
+// sameContextValue reports whether a and b are equal, values of types that can
+// not be compared are never equal.
+func sameContextValue(a any, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	typeOfA := reflect.TypeOf(a)
+	if typeOfA != reflect.TypeOf(b) || !typeOfA.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func (d *dbWithPropagation) join(
+	ctx context.Context,
+	active *activeTransaction,
+	writable bool,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	if writable && !active.writable {
+		return ErrUpdateInViewTransaction
+	}
+	if err := fn(WithPropagation(ctx, PropagationRequired), active.tx); err != nil {
+		active.rollbackOnly = true
+		return err
+	}
+	return nil
+}
+
+func (d *dbWithPropagation) open(
+	ctx context.Context,
+	writable bool,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	action := d.db.View
+	if writable {
+		action = d.db.Update
+	}
+	return action(ctx, func(txCtx context.Context, tx Tx) error {
+		active := &activeTransaction{
+			db:       d,
+			tx:       tx,
+			writable: writable,
+			ctx:      ctx,
+			txCtx:    txCtx,
+		}
+		txCtx = context.WithValue(txCtx, activeTransactionContextKey{}, active)
+		if err := fn(WithPropagation(txCtx, PropagationRequired), tx); err != nil {
+			return err
+		}
+		if active.rollbackOnly {
+			return ErrRollbackOnly
+		}
+		return nil
+	})
+}
+
+func (d *dbWithPropagation) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithPropagation) Close() error {
+	return d.db.Close()
+}
+
+func (d *dbWithPropagation) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithPropagation) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithPropagation) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithPropagation) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("DBWithPropagation", func() {
+	var ctx context.Context
+	var err error
+	var backend *memoryDB
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		db = kv.NewDBWithPropagation(backend)
+		bucketName = kv.NewBucketName("bucket")
+	})
+	put := func(ctx context.Context, tx kv.Tx, key string) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(ctx, []byte(key), []byte("value"))
+	}
+	exists := func(key string) bool {
+		var result bool
+		Expect(backend.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return nil
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			result = item.Exists()
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	Context("Required", func() {
+		It("joins the outer update", func() {
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				Expect(put(ctx, outerTx, "outer")).To(Succeed())
+				return db.Update(ctx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).To(BeIdenticalTo(outerTx))
+					return put(ctx, innerTx, "inner")
+				})
+			})
+			Expect(err).To(BeNil())
+			Expect(exists("outer")).To(BeTrue())
+			Expect(exists("inner")).To(BeTrue())
+		})
+		It("joins a view inside an update", func() {
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				Expect(put(ctx, outerTx, "outer")).To(Succeed())
+				return db.View(ctx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).To(BeIdenticalTo(outerTx))
+					return nil
+				})
+			})
+			Expect(err).To(BeNil())
+		})
+		It("fails an update inside a view", func() {
+			err = db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+					return nil
+				})
+			})
+			Expect(errors.Is(err, kv.ErrUpdateInViewTransaction)).To(BeTrue())
+		})
+		It("rolls back if a joined fn failed", func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				Expect(put(ctx, tx, "outer")).To(Succeed())
+				_ = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+					return errors.New("banana")
+				})
+				return nil
+			})
+			Expect(errors.Is(err, kv.ErrRollbackOnly)).To(BeTrue())
+			Expect(exists("outer")).To(BeFalse())
+		})
+		It("provides the tx through the context", func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				txFromContext, ok := kv.TxFromContext(ctx)
+				Expect(ok).To(BeTrue())
+				Expect(txFromContext).To(BeIdenticalTo(tx))
+				return nil
+			})
+			Expect(err).To(BeNil())
+			_, ok := kv.TxFromContext(ctx)
+			Expect(ok).To(BeFalse())
+		})
+	})
+	Context("RequiresNew", func() {
+		It("opens an independent transaction", func() {
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				Expect(put(ctx, outerTx, "outer")).To(Succeed())
+				ctx = kv.WithPropagation(ctx, kv.PropagationRequiresNew)
+				return db.View(ctx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).NotTo(BeIdenticalTo(outerTx))
+					_, err := innerTx.Bucket(ctx, bucketName)
+					Expect(errors.Is(err, kv.ErrBucketNotFound)).To(BeTrue())
+					return nil
+				})
+			})
+			Expect(err).To(BeNil())
+		})
+		It("keeps values the caller added inside the transaction", func() {
+			type requestIDKey struct{}
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				ctx = context.WithValue(ctx, requestIDKey{}, "request")
+				ctx = kv.WithPropagation(ctx, kv.PropagationRequiresNew)
+				return db.View(ctx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).NotTo(BeIdenticalTo(outerTx))
+					Expect(ctx.Value(requestIDKey{})).To(Equal("request"))
+					return nil
+				})
+			})
+			Expect(err).To(BeNil())
+		})
+		It("aborts the new transaction if the inner context is cancelled", func() {
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				Expect(put(ctx, outerTx, "outer")).To(Succeed())
+				innerCtx, cancel := context.WithCancel(
+					kv.WithPropagation(ctx, kv.PropagationRequiresNew),
+				)
+				cancel()
+				return db.View(innerCtx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).NotTo(BeIdenticalTo(outerTx))
+					return ctx.Err()
+				})
+			})
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(exists("outer")).To(BeFalse())
+		})
+	})
+	Context("Never", func() {
+		It("fails inside a transaction", func() {
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				ctx = kv.WithPropagation(ctx, kv.PropagationNever)
+				return db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+					return nil
+				})
+			})
+			Expect(errors.Is(err, kv.ErrTransactionAlreadyOpen)).To(BeTrue())
+		})
+		It("opens a transaction without active transaction", func() {
+			ctx = kv.WithPropagation(ctx, kv.PropagationNever)
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return put(ctx, tx, "key")
+			})
+			Expect(err).To(BeNil())
+			Expect(exists("key")).To(BeTrue())
+		})
+	})
+	Context("Mandatory", func() {
+		It("fails without transaction", func() {
+			ctx = kv.WithPropagation(ctx, kv.PropagationMandatory)
+			err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return nil
+			})
+			Expect(errors.Is(err, kv.ErrNoActiveTransaction)).To(BeTrue())
+		})
+		It("joins the active transaction", func() {
+			err = db.Update(ctx, func(ctx context.Context, outerTx kv.Tx) error {
+				ctx = kv.WithPropagation(ctx, kv.PropagationMandatory)
+				return db.Update(ctx, func(ctx context.Context, innerTx kv.Tx) error {
+					Expect(innerTx).To(BeIdenticalTo(outerTx))
+					return nil
+				})
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+	It("joins through Store", func() {
+		store := kv.NewStore[string, string](db, bucketName)
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			if err := store.Add(ctx, "a", "b"); err != nil {
+				return err
+			}
+			value, err := store.Get(ctx, "a")
+			Expect(err).To(BeNil())
+			Expect(*value).To(Equal("b"))
+			return nil
+		})
+		Expect(err).To(BeNil())
+	})
+	It("String", func() {
+		Expect(kv.PropagationRequiresNew.String()).To(Equal("RequiresNew"))
+	})
+})
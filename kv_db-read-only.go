@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrReadOnly is returned by all writes to a DB returned by NewReadOnlyDB.
+var ErrReadOnly = stderrors.New("db is read only")
+
+// NewReadOnlyDB wraps a DB to reject all writes with ErrReadOnly.
+// Update, Sync and Remove fail, as do all writes to buckets opened in View.
+func NewReadOnlyDB(db DB) DB {
+	return &readOnlyDB{
+		db: db,
+	}
+}
+
+// IsReadOnly returns true if the DB rejects all writes, see NewReadOnlyDB.
+// The wrappers of this package forward ReadOnly to the wrapped DB, so a read-only
+// DB is also detected below them.
+func IsReadOnly(db DB) bool {
+	readOnly, ok := db.(interface{ ReadOnly() bool })
+	return ok && readOnly.ReadOnly()
+}
+
+type readOnlyDB struct {
+	db DB
+}
+
+func (r *readOnlyDB) ReadOnly() bool {
+	return true
+}
+
+func (r *readOnlyDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	return errors.Wrapf(ctx, ErrReadOnly, "update failed")
+}
+
+func (r *readOnlyDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	return r.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &readOnlyTx{tx: tx})
+	})
+}
+
+func (r *readOnlyDB) Sync() error {
+	return errors.Wrapf(context.Background(), ErrReadOnly, "sync failed")
+}
+
+func (r *readOnlyDB) Close() error {
+	return r.db.Close()
+}
+
+func (r *readOnlyDB) Remove() error {
+	return errors.Wrapf(context.Background(), ErrReadOnly, "remove failed")
+}
+
+func (r *readOnlyDB) Stats(ctx context.Context) (*Stats, error) {
+	return r.db.Stats(ctx)
+}
+
+func (r *readOnlyDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return r.db.StatsDetailed(ctx)
+}
+
+type readOnlyTx struct {
+	tx Tx
+}
+
+func (r *readOnlyTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := r.tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyBucket{bucket: bucket}, nil
+}
+
+func (r *readOnlyTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	return nil, errors.Wrapf(ctx, ErrReadOnly, "create bucket %s failed", name)
+}
+
+// CreateBucketIfNotExists returns existing buckets, because it does not write in this case.
+func (r *readOnlyTx) CreateBucketIfNotExists(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := r.Bucket(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return nil, errors.Wrapf(ctx, ErrReadOnly, "create bucket %s failed", name)
+		}
+		return nil, err
+	}
+	return bucket, nil
+}
+
+func (r *readOnlyTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	return errors.Wrapf(ctx, ErrReadOnly, "delete bucket %s failed", name)
+}
+
+func (r *readOnlyTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	return r.tx.ListBucketNames(ctx)
+}
+
+type readOnlyBucket struct {
+	bucket Bucket
+}
+
+func (r *readOnlyBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	return errors.Wrapf(ctx, ErrReadOnly, "put %s failed", key)
+}
+
+func (r *readOnlyBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	return r.bucket.Get(ctx, key)
+}
+
+func (r *readOnlyBucket) Delete(ctx context.Context, key []byte) error {
+	return errors.Wrapf(ctx, ErrReadOnly, "delete %s failed", key)
+}
+
+func (r *readOnlyBucket) Iterator() Iterator {
+	return r.bucket.Iterator()
+}
+
+func (r *readOnlyBucket) IteratorReverse() Iterator {
+	return r.bucket.IteratorReverse()
+}
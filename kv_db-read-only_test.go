@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("ReadOnlyDB", func() {
+	var ctx context.Context
+	var backend *memoryDB
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		db = kv.NewReadOnlyDB(backend)
+		bucketName = kv.NewBucketName("bucket")
+		Expect(backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("key"), []byte("value"))
+		})).To(Succeed())
+	})
+	view := func(fn func(ctx context.Context, tx kv.Tx) error) error {
+		return db.View(ctx, fn)
+	}
+	It("is read only", func() {
+		Expect(kv.IsReadOnly(db)).To(BeTrue())
+		Expect(kv.IsReadOnly(backend)).To(BeFalse())
+	})
+	It("rejects Update, Sync and Remove", func() {
+		called := false
+		err := db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			called = true
+			return nil
+		})
+		Expect(errors.Is(err, kv.ErrReadOnly)).To(BeTrue())
+		Expect(called).To(BeFalse())
+		Expect(errors.Is(db.Sync(), kv.ErrReadOnly)).To(BeTrue())
+		Expect(errors.Is(db.Remove(), kv.ErrReadOnly)).To(BeTrue())
+	})
+	It("reads values", func() {
+		Expect(view(func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte("key"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeTrue())
+			names, err := tx.ListBucketNames(ctx)
+			Expect(err).To(BeNil())
+			Expect(names).To(HaveLen(1))
+			return nil
+		})).To(Succeed())
+	})
+	It("rejects writes to buckets", func() {
+		Expect(view(func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(errors.Is(bucket.Put(ctx, []byte("key"), []byte("new")), kv.ErrReadOnly)).
+				To(BeTrue())
+			Expect(errors.Is(bucket.Delete(ctx, []byte("key")), kv.ErrReadOnly)).To(BeTrue())
+			return nil
+		})).To(Succeed())
+		Expect(backend.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte("key"))
+			Expect(err).To(BeNil())
+			return item.Value(func(val []byte) error {
+				Expect(string(val)).To(Equal("value"))
+				return nil
+			})
+		})).To(Succeed())
+	})
+	It("rejects changes of buckets", func() {
+		Expect(view(func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.CreateBucket(ctx, kv.NewBucketName("new"))
+			Expect(errors.Is(err, kv.ErrReadOnly)).To(BeTrue())
+			_, err = tx.CreateBucketIfNotExists(ctx, kv.NewBucketName("new"))
+			Expect(errors.Is(err, kv.ErrReadOnly)).To(BeTrue())
+			_, err = tx.CreateBucketIfNotExists(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(errors.Is(tx.DeleteBucket(ctx, bucketName), kv.ErrReadOnly)).To(BeTrue())
+			return nil
+		})).To(Succeed())
+	})
+})
@@ -0,0 +1,415 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// NewRecordingDB wraps a DB to write every transaction with all its bucket and
+// iterator operations, timings and results as JSON lines of RecordedOperation to writer.
+// Transactions are written in sequence order: a view when its fn starts, an update
+// when its fn returns, so a view overlapping an update is written before the update
+// if it did not see its writes. A transaction is held back until all transactions
+// with a lower sequence ended. The sequence is taken by the wrapper, not the backend,
+// so a view starting right between the end of fn and the commit of an update, and
+// updates of backends with concurrent writers, can still replay differently.
+// Values of iterator items are only recorded if the caller reads them.
+// Wrap writer with gzip for a smaller recording, see Replay to re-execute it.
+// Failures to write the recording are logged and do not fail the transaction.
+func NewRecordingDB(db DB, writer io.Writer) DB {
+	return &recordingDB{
+		db:      db,
+		encoder: json.NewEncoder(writer),
+		pending: map[uint64][]RecordedOperation{},
+	}
+}
+
+type recordingDB struct {
+	db     DB
+	nextTx atomic.Uint64
+
+	mux      sync.Mutex
+	encoder  *json.Encoder
+	sequence uint64
+	written  uint64
+	// pending holds ended transactions by sequence until all lower sequences are written
+	pending map[uint64][]RecordedOperation
+}
+
+func (r *recordingDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	return r.record(ctx, RecordBeginUpdate, r.db.Update, fn)
+}
+
+func (r *recordingDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	return r.record(ctx, RecordBeginView, r.db.View, fn)
+}
+
+func (r *recordingDB) record(
+	ctx context.Context,
+	operation RecordOperation,
+	run func(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	start := time.Now()
+	id := r.nextTx.Add(1)
+	newRecorder := func() *txRecorder {
+		recorder := &txRecorder{id: id}
+		recorder.record(start, RecordedOperation{Operation: operation})
+		return recorder
+	}
+	recorder := newRecorder()
+	var sequence uint64
+	finished := false
+	var err error
+	// the end is written even if fn panics, otherwise all later transactions stay pending
+	defer func() {
+		if sequence == 0 {
+			sequence = r.nextSequence()
+		}
+		end := RecordedOperation{
+			Operation: RecordEnd,
+			Error:     recordError(err),
+		}
+		if !finished {
+			end.Error = "panic"
+		}
+		recorder.record(start, end)
+		r.write(sequence, recorder.operations)
+	}()
+	err = run(ctx, func(ctx context.Context, tx Tx) error {
+		if operation == RecordBeginView && sequence == 0 {
+			sequence = r.nextSequence()
+		}
+		// a retried fn starts a new recorder, so the aborted attempt is not recorded
+		recorder = newRecorder()
+		err := fn(ctx, &recordingTx{tx: tx, recorder: recorder})
+		if sequence == 0 {
+			sequence = r.nextSequence()
+		}
+		return err
+	})
+	finished = true
+	return err
+}
+
+// nextSequence returns the sequence of a transaction, every transaction takes exactly one.
+func (r *recordingDB) nextSequence() uint64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.sequence++
+	return r.sequence
+}
+
+// write writes the operations of the transaction with the given sequence and all
+// pending transactions following it.
+func (r *recordingDB) write(sequence uint64, operations []RecordedOperation) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.pending[sequence] = operations
+	for {
+		operations, ok := r.pending[r.written+1]
+		if !ok {
+			return
+		}
+		delete(r.pending, r.written+1)
+		r.written++
+		for _, operation := range operations {
+			if err := r.encoder.Encode(operation); err != nil {
+				glog.Warningf("write recording of tx %d failed: %v", operation.Tx, err)
+				break
+			}
+		}
+	}
+}
+
+func (r *recordingDB) Sync() error {
+	return r.db.Sync()
+}
+
+func (r *recordingDB) Close() error {
+	return r.db.Close()
+}
+
+func (r *recordingDB) Remove() error {
+	return r.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (r *recordingDB) ReadOnly() bool {
+	return IsReadOnly(r.db)
+}
+
+func (r *recordingDB) Stats(ctx context.Context) (*Stats, error) {
+	return r.db.Stats(ctx)
+}
+
+func (r *recordingDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return r.db.StatsDetailed(ctx)
+}
+
+// txRecorder collects the operations of a single transaction.
+type txRecorder struct {
+	id           uint64
+	operations   []RecordedOperation
+	nextIterator int
+}
+
+// record adds the operation started at start, all byte slices are copied.
+func (t *txRecorder) record(start time.Time, operation RecordedOperation) {
+	operation.Tx = t.id
+	operation.Start = start.UnixNano()
+	if operation.Operation != RecordBeginUpdate && operation.Operation != RecordBeginView {
+		operation.Duration = time.Since(start)
+	}
+	operation.BucketName = bytes.Clone(operation.BucketName)
+	operation.Key = bytes.Clone(operation.Key)
+	operation.Value = bytes.Clone(operation.Value)
+	operation.Result = bytes.Clone(operation.Result)
+	t.operations = append(t.operations, operation)
+}
+
+type recordingTx struct {
+	tx       Tx
+	recorder *txRecorder
+}
+
+func (r *recordingTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	return r.bucket(ctx, RecordBucket, name, r.tx.Bucket)
+}
+
+func (r *recordingTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	return r.bucket(ctx, RecordCreateBucket, name, r.tx.CreateBucket)
+}
+
+func (r *recordingTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	return r.bucket(ctx, RecordCreateBucketIfNotExists, name, r.tx.CreateBucketIfNotExists)
+}
+
+func (r *recordingTx) bucket(
+	ctx context.Context,
+	operation RecordOperation,
+	name BucketName,
+	fn func(ctx context.Context, name BucketName) (Bucket, error),
+) (Bucket, error) {
+	start := time.Now()
+	bucket, err := fn(ctx, name)
+	r.recorder.record(start, RecordedOperation{
+		Operation:  operation,
+		BucketName: name,
+		Error:      recordError(err),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &recordingBucket{
+		bucket:   bucket,
+		recorder: r.recorder,
+		name:     bytes.Clone(name),
+	}, nil
+}
+
+func (r *recordingTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	start := time.Now()
+	err := r.tx.DeleteBucket(ctx, name)
+	r.recorder.record(start, RecordedOperation{
+		Operation:  RecordDeleteBucket,
+		BucketName: name,
+		Error:      recordError(err),
+	})
+	return err
+}
+
+func (r *recordingTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	start := time.Now()
+	names, err := r.tx.ListBucketNames(ctx)
+	recorded := make(BucketNames, 0, len(names))
+	for _, name := range names {
+		recorded = append(recorded, bytes.Clone(name))
+	}
+	r.recorder.record(start, RecordedOperation{
+		Operation:   RecordListBucketNames,
+		BucketNames: recorded,
+		Error:       recordError(err),
+	})
+	return names, err
+}
+
+type recordingBucket struct {
+	bucket   Bucket
+	recorder *txRecorder
+	name     BucketName
+}
+
+func (r *recordingBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	start := time.Now()
+	err := r.bucket.Put(ctx, key, value)
+	r.recorder.record(start, RecordedOperation{
+		Operation:  RecordPut,
+		BucketName: r.name,
+		Key:        key,
+		Value:      value,
+		Error:      recordError(err),
+	})
+	return err
+}
+
+func (r *recordingBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	start := time.Now()
+	item, err := r.bucket.Get(ctx, key)
+	var value []byte
+	if err == nil {
+		value, err = itemValue(item)
+	}
+	r.recorder.record(start, RecordedOperation{
+		Operation:  RecordGet,
+		BucketName: r.name,
+		Key:        key,
+		Value:      value,
+		Error:      recordError(err),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *recordingBucket) Delete(ctx context.Context, key []byte) error {
+	start := time.Now()
+	err := r.bucket.Delete(ctx, key)
+	r.recorder.record(start, RecordedOperation{
+		Operation:  RecordDelete,
+		BucketName: r.name,
+		Key:        key,
+		Error:      recordError(err),
+	})
+	return err
+}
+
+func (r *recordingBucket) Iterator() Iterator {
+	return r.iterator(RecordIterator, r.bucket.Iterator)
+}
+
+func (r *recordingBucket) IteratorReverse() Iterator {
+	return r.iterator(RecordIteratorReverse, r.bucket.IteratorReverse)
+}
+
+func (r *recordingBucket) iterator(operation RecordOperation, fn func() Iterator) Iterator {
+	start := time.Now()
+	r.recorder.nextIterator++
+	iterator := &recordingIterator{
+		Iterator: fn(),
+		bucket:   r,
+		id:       r.recorder.nextIterator,
+	}
+	r.recorder.record(start, RecordedOperation{
+		Operation:  operation,
+		BucketName: r.name,
+		Iterator:   iterator.id,
+	})
+	return iterator
+}
+
+type recordingIterator struct {
+	Iterator
+	bucket *recordingBucket
+	id     int
+}
+
+func (r *recordingIterator) Rewind() {
+	start := time.Now()
+	r.Iterator.Rewind()
+	r.record(start, RecordRewind, nil)
+}
+
+func (r *recordingIterator) Seek(key []byte) {
+	start := time.Now()
+	r.Iterator.Seek(key)
+	r.record(start, RecordSeek, key)
+}
+
+func (r *recordingIterator) Next() {
+	start := time.Now()
+	r.Iterator.Next()
+	r.record(start, RecordNext, nil)
+}
+
+func (r *recordingIterator) Close() {
+	start := time.Now()
+	r.Iterator.Close()
+	r.bucket.recorder.record(start, RecordedOperation{
+		Operation:  RecordClose,
+		BucketName: r.bucket.name,
+		Iterator:   r.id,
+	})
+}
+
+func (r *recordingIterator) Item() Item {
+	return &recordingItem{
+		Item:     r.Iterator.Item(),
+		iterator: r,
+	}
+}
+
+// record adds the move of the iterator with the key of the current item.
+func (r *recordingIterator) record(start time.Time, operation RecordOperation, key []byte) {
+	r.bucket.recorder.record(start, RecordedOperation{
+		Operation:  operation,
+		BucketName: r.bucket.name,
+		Iterator:   r.id,
+		Key:        key,
+		Result:     currentIteratorKey(r.Iterator),
+	})
+}
+
+// recordingItem records the value of an iterator item once the caller reads it.
+type recordingItem struct {
+	Item
+	iterator *recordingIterator
+}
+
+func (r *recordingItem) Value(fn func(val []byte) error) error {
+	start := time.Now()
+	var value []byte
+	var fnErr error
+	err := r.Item.Value(func(val []byte) error {
+		value = bytes.Clone(val)
+		fnErr = fn(val)
+		return fnErr
+	})
+	recorded := err
+	if fnErr != nil {
+		// the error of fn is not a result of the db
+		recorded = nil
+	}
+	r.iterator.bucket.recorder.record(start, RecordedOperation{
+		Operation:  RecordValue,
+		BucketName: r.iterator.bucket.name,
+		Iterator:   r.iterator.id,
+		Result:     r.Item.Key(),
+		Value:      value,
+		Error:      recordError(recorded),
+	})
+	return err
+}
+
+// currentIteratorKey returns the key of the current item, nil if not valid.
+func currentIteratorKey(iterator Iterator) []byte {
+	if !iterator.Valid() {
+		return nil
+	}
+	return bytes.Clone(iterator.Item().Key())
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("RecordingDB", func() {
+	var ctx context.Context
+	var recording *bytes.Buffer
+	var backend *memoryDB
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		recording = &bytes.Buffer{}
+		backend = newMemoryDB()
+		db = kv.NewRecordingDB(backend, recording)
+		bucketName = kv.NewBucketName("bucket")
+
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "b", "c"} {
+				if err := bucket.Put(ctx, []byte(key), []byte(key)); err != nil {
+					return err
+				}
+			}
+			return bucket.Delete(ctx, []byte("b"))
+		})).To(Succeed())
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("d"), []byte("d")); err != nil {
+				return err
+			}
+			return errors.New("banana")
+		})).NotTo(Succeed())
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.Bucket(ctx, kv.NewBucketName("missing"))
+			Expect(errors.Is(err, kv.ErrBucketNotFound)).To(BeTrue())
+			names, err := tx.ListBucketNames(ctx)
+			Expect(err).To(BeNil())
+			Expect(names).To(HaveLen(1))
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte("a"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeTrue())
+			Expect(collectKeys(bucket.IteratorReverse(), []byte("b"))).To(Equal([]string{"a"}))
+			return nil
+		})).To(Succeed())
+	})
+	decode := func() []kv.RecordedOperation {
+		decoder := json.NewDecoder(bytes.NewReader(recording.Bytes()))
+		var operations []kv.RecordedOperation
+		for {
+			var operation kv.RecordedOperation
+			if err := decoder.Decode(&operation); errors.Is(err, io.EOF) {
+				return operations
+			} else {
+				Expect(err).To(BeNil())
+			}
+			operations = append(operations, operation)
+		}
+	}
+	It("records all operations with timings and results", func() {
+		operations := decode()
+		var names []kv.RecordOperation
+		for _, operation := range operations {
+			names = append(names, operation.Operation)
+			Expect(operation.Start).NotTo(BeZero())
+		}
+		Expect(names).To(Equal([]kv.RecordOperation{
+			kv.RecordBeginUpdate, kv.RecordCreateBucket,
+			kv.RecordPut, kv.RecordPut, kv.RecordPut, kv.RecordDelete, kv.RecordEnd,
+			kv.RecordBeginUpdate, kv.RecordBucket, kv.RecordPut, kv.RecordEnd,
+			kv.RecordBeginView, kv.RecordBucket, kv.RecordListBucketNames, kv.RecordBucket,
+			kv.RecordGet, kv.RecordIteratorReverse, kv.RecordSeek, kv.RecordNext, kv.RecordClose,
+			kv.RecordEnd,
+		}))
+		Expect(operations[10].Error).To(Equal("banana"))
+		Expect(operations[12].Error).To(Equal("bucket_not_found"))
+		Expect(operations[15].Value).To(Equal([]byte("a")))
+		Expect(operations[17].Result).To(Equal([]byte("a")))
+	})
+	It("records values of iterator items only if read", func() {
+		recording.Reset()
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			it := bucket.Iterator()
+			defer it.Close()
+			it.Rewind()
+			it.Next()
+			return it.Item().Value(func(val []byte) error {
+				Expect(val).To(Equal([]byte("c")))
+				return nil
+			})
+		})).To(Succeed())
+		operations := decode()
+		var names []kv.RecordOperation
+		for _, operation := range operations {
+			names = append(names, operation.Operation)
+			if operation.Operation != kv.RecordValue {
+				Expect(operation.Value).To(BeNil())
+			}
+		}
+		Expect(names).To(Equal([]kv.RecordOperation{
+			kv.RecordBeginView, kv.RecordBucket, kv.RecordIterator, kv.RecordRewind,
+			kv.RecordNext, kv.RecordValue, kv.RecordClose, kv.RecordEnd,
+		}))
+		Expect(operations[5].Result).To(Equal([]byte("c")))
+		Expect(operations[5].Value).To(Equal([]byte("c")))
+
+		target := newMemoryDB()
+		Expect(target.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("a"), []byte("a")); err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("c"), []byte("x"))
+		})).To(Succeed())
+		_, err := kv.Replay(ctx, bytes.NewReader(recording.Bytes()), target)
+		Expect(errors.Is(err, kv.ErrReplayMismatch)).To(BeTrue())
+	})
+	It("replays a view overlapping an update in sequence order", func() {
+		recording.Reset()
+		started := make(chan struct{})
+		updated := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+				close(started)
+				<-updated
+				bucket, err := tx.Bucket(ctx, bucketName)
+				if err != nil {
+					return err
+				}
+				item, err := bucket.Get(ctx, []byte("e"))
+				if err != nil {
+					return err
+				}
+				Expect(item.Exists()).To(BeFalse())
+				return nil
+			})
+		}()
+		<-started
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("e"), []byte("e"))
+		})).To(Succeed())
+		Expect(recording.Len()).To(BeZero())
+		close(updated)
+		Expect(<-done).To(Succeed())
+		operations := decode()
+		Expect(operations[0].Operation).To(Equal(kv.RecordBeginView))
+
+		target := newMemoryDB()
+		Expect(target.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "c"} {
+				if err := bucket.Put(ctx, []byte(key), []byte(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+		count, err := kv.Replay(ctx, bytes.NewReader(recording.Bytes()), target)
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(2))
+		result, err := kv.Diff(ctx, backend, target, kv.DiffOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Equal()).To(BeTrue())
+	})
+	It("replays the recording with identical results", func() {
+		target := newMemoryDB()
+		count, err := kv.Replay(ctx, bytes.NewReader(recording.Bytes()), target)
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(3))
+		result, err := kv.Diff(ctx, backend, target, kv.DiffOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Equal()).To(BeTrue())
+	})
+	It("records only the last attempt of a retried fn", func() {
+		retrying := &mocks.DB{}
+		retrying.ViewStub = backend.View
+		retrying.UpdateStub = func(
+			ctx context.Context,
+			fn func(ctx context.Context, tx kv.Tx) error,
+		) error {
+			_ = backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				if err := fn(ctx, tx); err != nil {
+					return err
+				}
+				return errors.New("conflict")
+			})
+			return backend.Update(ctx, fn)
+		}
+		recording.Reset()
+		db = kv.NewRecordingDB(retrying, recording)
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("e"), []byte("e"))
+		})).To(Succeed())
+		var operations []kv.RecordOperation
+		for _, operation := range decode() {
+			operations = append(operations, operation.Operation)
+		}
+		Expect(operations).To(Equal([]kv.RecordOperation{
+			kv.RecordBeginUpdate,
+			kv.RecordBucket,
+			kv.RecordPut,
+			kv.RecordEnd,
+		}))
+	})
+	It("keeps recording after fn panics", func() {
+		recording.Reset()
+		db = kv.NewRecordingDB(backend, recording)
+		Expect(func() {
+			_ = db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+				panic("banana")
+			})
+		}).To(Panic())
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			return nil
+		})).To(Succeed())
+		operations := decode()
+		Expect(operations).To(HaveLen(4))
+		Expect(operations[1].Operation).To(Equal(kv.RecordEnd))
+		Expect(operations[1].Error).To(Equal("panic"))
+		Expect(operations[3].Operation).To(Equal(kv.RecordEnd))
+		Expect(operations[3].Error).To(BeEmpty())
+	})
+	It("fails with ErrReplayMismatch on different results", func() {
+		target := newMemoryDB()
+		Expect(target.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.CreateBucket(ctx, bucketName)
+			return err
+		})).To(Succeed())
+		count, err := kv.Replay(ctx, bytes.NewReader(recording.Bytes()), target)
+		Expect(errors.Is(err, kv.ErrReplayMismatch)).To(BeTrue())
+		Expect(count).To(Equal(0))
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewRecordingDB(newMemoryDB(), io.Discard), nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
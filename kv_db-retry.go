@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// RetryOptions configures NewDBWithRetry.
+// Unset values except Jitter are replaced by the defaults of DefaultRetryOptions.
+type RetryOptions struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait time before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait time between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the backoff after each retry.
+	Multiplier float64
+	// Jitter randomly reduces each backoff by up to this fraction (0 to 1).
+	Jitter float64
+}
+
+// DefaultRetryOptions returns the default RetryOptions.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// NewDBWithRetry wraps a DB and retries Update if it fails with ErrConflict.
+// Each attempt runs fn from scratch in a new transaction, so fn must not have
+// side effects outside the transaction (use OnCommit for those).
+// View is passed through.
+func NewDBWithRetry(db DB, options RetryOptions, metrics RetryMetrics) DB {
+	defaults := DefaultRetryOptions()
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaults.InitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = defaults.Multiplier
+	}
+	if options.Jitter < 0 || options.Jitter > 1 {
+		options.Jitter = defaults.Jitter
+	}
+	return &dbWithRetry{
+		db:      db,
+		options: options,
+		metrics: metrics,
+	}
+}
+
+type dbWithRetry struct {
+	db      DB
+	options RetryOptions
+	metrics RetryMetrics
+}
+
+func (d *dbWithRetry) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	backoff := d.options.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.db.Update(ctx, fn)
+		if err == nil || !errors.Is(err, ErrConflict) {
+			return err
+		}
+		if attempt >= d.options.MaxAttempts {
+			return errors.Wrapf(ctx, err, "update failed after %d attempts", attempt)
+		}
+		d.metrics.DbUpdateRetryInc()
+		wait := d.jitter(backoff)
+		glog.V(3).Infof("update attempt %d conflicted, retry in %v", attempt, wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = time.Duration(float64(backoff) * d.options.Multiplier)
+		if backoff > d.options.MaxBackoff {
+			backoff = d.options.MaxBackoff
+		}
+	}
+}
+
+func (d *dbWithRetry) jitter(backoff time.Duration) time.Duration {
+	if d.options.Jitter == 0 {
+		return backoff
+	}
+	// #nosec G404 -- jitter does not need a cryptographic random source
+	return backoff - time.Duration(rand.Float64()*d.options.Jitter*float64(backoff))
+}
+
+func (d *dbWithRetry) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.View(ctx, fn)
+}
+
+func (d *dbWithRetry) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithRetry) Close() error {
+	return d.db.Close()
+}
+
+func (d *dbWithRetry) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithRetry) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithRetry) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithRetry) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
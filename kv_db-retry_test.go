@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("DBWithRetry", func() {
+	var ctx context.Context
+	var err error
+	var db *mocks.DB
+	var metrics *mocks.RetryMetrics
+	var options kv.RetryOptions
+	var fnCalls int
+	var conflicts int
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = &mocks.DB{}
+		metrics = &mocks.RetryMetrics{}
+		options = kv.RetryOptions{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     2 * time.Millisecond,
+			Jitter:         0.5,
+		}
+		fnCalls = 0
+		conflicts = 0
+		db.UpdateStub = func(ctx context.Context, fn func(context.Context, kv.Tx) error) error {
+			if err := fn(ctx, &mocks.Tx{}); err != nil {
+				return err
+			}
+			if db.UpdateCallCount() <= conflicts {
+				return fmt.Errorf("badger: %w", kv.ErrConflict)
+			}
+			return nil
+		}
+	})
+	JustBeforeEach(func() {
+		err = kv.NewDBWithRetry(db, options, metrics).
+			Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				fnCalls++
+				return nil
+			})
+	})
+	Context("without conflict", func() {
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("calls Update once", func() {
+			Expect(db.UpdateCallCount()).To(Equal(1))
+			Expect(metrics.DbUpdateRetryIncCallCount()).To(Equal(0))
+		})
+	})
+	Context("with conflicts", func() {
+		BeforeEach(func() {
+			conflicts = 2
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("re-runs fn in a new transaction", func() {
+			Expect(db.UpdateCallCount()).To(Equal(3))
+			Expect(fnCalls).To(Equal(3))
+		})
+		It("counts retries", func() {
+			Expect(metrics.DbUpdateRetryIncCallCount()).To(Equal(2))
+		})
+	})
+	Context("too many conflicts", func() {
+		BeforeEach(func() {
+			conflicts = 3
+		})
+		It("returns conflict error", func() {
+			Expect(errors.Is(err, kv.ErrConflict)).To(BeTrue())
+		})
+		It("stops after max attempts", func() {
+			Expect(db.UpdateCallCount()).To(Equal(3))
+		})
+	})
+	Context("other error", func() {
+		BeforeEach(func() {
+			db.UpdateStub = nil
+			db.UpdateReturns(errors.New("banana"))
+		})
+		It("returns error without retry", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(db.UpdateCallCount()).To(Equal(1))
+		})
+	})
+	Context("context canceled", func() {
+		BeforeEach(func() {
+			conflicts = 3
+			options.InitialBackoff = time.Hour
+			options.MaxBackoff = time.Hour
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			cancel()
+		})
+		It("returns context error", func() {
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(db.UpdateCallCount()).To(Equal(1))
+		})
+	})
+	It("passes View through", func() {
+		Expect(kv.NewDBWithRetry(db, options, metrics).View(ctx, nil)).To(Succeed())
+		Expect(db.ViewCallCount()).To(Equal(1))
+	})
+})
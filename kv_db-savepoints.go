@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// NewDBWithSavepoints wraps a DB so the Tx passed to Update is a SavepointTx
+// and can be used with WithSavepoint. Savepoints still open when fn returns
+// are released before the transaction commits. View is passed through.
+func NewDBWithSavepoints(db DB) DB {
+	return &dbWithSavepoints{
+		db: db,
+	}
+}
+
+type dbWithSavepoints struct {
+	db DB
+}
+
+func (d *dbWithSavepoints) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		savepointTx := &savepointTx{
+			tx:      tx,
+			buckets: map[string]*savepointBucket{},
+		}
+		if err := fn(ctx, savepointTx); err != nil {
+			return err
+		}
+		if len(savepointTx.layers) == 0 {
+			return nil
+		}
+		if err := savepointTx.Release(ctx, savepointTx.layers[0].id); err != nil {
+			return errors.Wrapf(ctx, err, "release open savepoints failed")
+		}
+		return nil
+	})
+}
+
+func (d *dbWithSavepoints) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.View(ctx, fn)
+}
+
+func (d *dbWithSavepoints) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithSavepoints) Close() error {
+	return d.db.Close()
+}
+
+func (d *dbWithSavepoints) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithSavepoints) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithSavepoints) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithSavepoints) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
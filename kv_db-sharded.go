@@ -0,0 +1,405 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/bborbe/errors"
+)
+
+// NewShardedDB spreads the keys of all buckets over the given shards
+// by a consistent hash of bucket name and key.
+//
+// Transactions open a transaction on every shard, in the order of the shards.
+// Each shard commits atomically, but a transaction is not atomic across shards:
+// if a shard fails to commit, the shards committed before it keep their changes.
+// Reads within a transaction are isolated per shard.
+//
+// Buckets are created on all shards. Keys still stored on another shard than their
+// owner, for example after adding a shard, stay readable until RebalanceShards moved them.
+//
+// It fails without shards, for shards without DB and for empty or duplicate shard names.
+func NewShardedDB(shards ...Shard) (DB, error) {
+	ctx := context.Background()
+	if err := validateShards(ctx, shards); err != nil {
+		return nil, errors.Wrapf(ctx, err, "create sharded db failed")
+	}
+	return &shardedDB{
+		shards: shards,
+		ring:   newShardRing(shards),
+	}, nil
+}
+
+type shardedTxContextKey struct{}
+
+type shardedDB struct {
+	shards []Shard
+	ring   *shardRing
+}
+
+func (s *shardedDB) Update(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if ctx.Value(shardedTxContextKey{}) != nil {
+		return ErrTransactionAlreadyOpen
+	}
+	return s.open(ctx, true, make([]Tx, 0, len(s.shards)), fn)
+}
+
+func (s *shardedDB) View(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	if ctx.Value(shardedTxContextKey{}) != nil {
+		return ErrTransactionAlreadyOpen
+	}
+	return s.open(ctx, false, make([]Tx, 0, len(s.shards)), fn)
+}
+
+// open nests the transactions of all shards and calls fn in the innermost.
+// The transactions are opened with the context of the caller,
+// so backends do not consider them as nested. Nested transactions of
+// the sharded DB itself are detected by its own context key.
+func (s *shardedDB) open(
+	ctx context.Context,
+	writable bool,
+	txs []Tx,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	if len(txs) == len(s.shards) {
+		tx := &shardedTx{
+			db:       s,
+			txs:      txs,
+			writable: writable,
+		}
+		return fn(context.WithValue(ctx, shardedTxContextKey{}, tx), tx)
+	}
+	db := s.shards[len(txs)].DB
+	action := db.View
+	if writable {
+		action = db.Update
+	}
+	return action(ctx, func(_ context.Context, tx Tx) error {
+		return s.open(ctx, writable, append(txs[:len(txs):len(txs)], tx), fn)
+	})
+}
+
+func (s *shardedDB) Sync() error {
+	var result error
+	for _, shard := range s.shards {
+		if err := shard.DB.Sync(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (s *shardedDB) Close() error {
+	var result error
+	for _, shard := range s.shards {
+		if err := shard.DB.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (s *shardedDB) Remove() error {
+	var result error
+	for _, shard := range s.shards {
+		if err := shard.DB.Remove(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// ReadOnly reports whether any shard is read only.
+func (s *shardedDB) ReadOnly() bool {
+	for _, shard := range s.shards {
+		if IsReadOnly(shard.DB) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shardedDB) Stats(ctx context.Context) (*Stats, error) {
+	return s.stats(ctx, DB.Stats)
+}
+
+func (s *shardedDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return s.stats(ctx, DB.StatsDetailed)
+}
+
+// stats sums the stats of all shards per bucket.
+func (s *shardedDB) stats(
+	ctx context.Context,
+	get func(db DB, ctx context.Context) (*Stats, error),
+) (*Stats, error) {
+	result := &Stats{
+		Backend:  "sharded",
+		Buckets:  []BucketStats{},
+		Detailed: true,
+	}
+	buckets := map[string]int{}
+	for _, shard := range s.shards {
+		stats, err := get(shard.DB, ctx)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "get stats of shard %s failed", shard.Name)
+		}
+		result.SizeB += stats.SizeB
+		result.Detailed = result.Detailed && stats.Detailed
+		for _, bucket := range stats.Buckets {
+			i, ok := buckets[bucket.Name.String()]
+			if !ok {
+				buckets[bucket.Name.String()] = len(result.Buckets)
+				result.Buckets = append(result.Buckets, bucket)
+				continue
+			}
+			result.Buckets[i].KeyCount += bucket.KeyCount
+			result.Buckets[i].SizeB += bucket.SizeB
+		}
+	}
+	sort.Slice(result.Buckets, func(i, j int) bool {
+		return bytes.Compare(result.Buckets[i].Name, result.Buckets[j].Name) < 0
+	})
+	return result, nil
+}
+
+type shardedTx struct {
+	db       *shardedDB
+	txs      []Tx
+	writable bool
+}
+
+func (s *shardedTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	buckets := make([]Bucket, len(s.txs))
+	var found bool
+	for i, tx := range s.txs {
+		bucket, err := tx.Bucket(ctx, name)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		buckets[i] = bucket
+		found = true
+	}
+	if !found {
+		return nil, errors.Wrapf(ctx, ErrBucketNotFound, "bucket %s not found", name)
+	}
+	return s.newBucket(name, buckets), nil
+}
+
+func (s *shardedTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	buckets := make([]Bucket, len(s.txs))
+	for i, tx := range s.txs {
+		bucket, err := tx.CreateBucket(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		buckets[i] = bucket
+	}
+	return s.newBucket(name, buckets), nil
+}
+
+func (s *shardedTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	buckets := make([]Bucket, len(s.txs))
+	for i, tx := range s.txs {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		buckets[i] = bucket
+	}
+	return s.newBucket(name, buckets), nil
+}
+
+func (s *shardedTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	var found bool
+	for _, tx := range s.txs {
+		if err := tx.DeleteBucket(ctx, name); err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				continue
+			}
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return errors.Wrapf(ctx, ErrBucketNotFound, "bucket %s not found", name)
+	}
+	return nil
+}
+
+// ListBucketNames returns the sorted names of the buckets of all shards.
+func (s *shardedTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	result := BucketNames{}
+	for _, tx := range s.txs {
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !result.Contains(name) {
+				result = append(result, name)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i], result[j]) < 0
+	})
+	return result, nil
+}
+
+func (s *shardedTx) newBucket(name BucketName, buckets []Bucket) *shardedBucket {
+	return &shardedBucket{
+		tx:      s,
+		name:    name,
+		buckets: buckets,
+	}
+}
+
+// shardedBucket holds the bucket of each shard, nil if the shard does not have it.
+type shardedBucket struct {
+	tx      *shardedTx
+	name    BucketName
+	buckets []Bucket
+}
+
+// Put writes to the owner of the key and creates its bucket on the shard if missing.
+func (s *shardedBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	owner := s.tx.db.ring.owner(s.name, key)
+	if s.buckets[owner] == nil {
+		bucket, err := s.tx.txs[owner].CreateBucketIfNotExists(ctx, s.name)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket %s on shard failed", s.name)
+		}
+		s.buckets[owner] = bucket
+	}
+	return s.buckets[owner].Put(ctx, key, value)
+}
+
+// Get reads from the owner of the key and falls back to the other shards
+// for keys not rebalanced yet.
+func (s *shardedBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	owner := s.tx.db.ring.owner(s.name, key)
+	var result Item
+	for _, i := range s.order(owner) {
+		if s.buckets[i] == nil {
+			continue
+		}
+		item, err := s.buckets[i].Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if item.Exists() {
+			return item, nil
+		}
+		if result == nil {
+			result = item
+		}
+	}
+	if result == nil {
+		return NewByteItem(key, nil), nil
+	}
+	return result, nil
+}
+
+// Delete removes the key from all shards, so no copy of a not rebalanced key remains.
+func (s *shardedBucket) Delete(ctx context.Context, key []byte) error {
+	for _, bucket := range s.buckets {
+		if bucket == nil {
+			continue
+		}
+		if err := bucket.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *shardedBucket) Iterator() Iterator {
+	return s.iterator(false)
+}
+
+func (s *shardedBucket) IteratorReverse() Iterator {
+	return s.iterator(true)
+}
+
+// iterator merges the iterators of all shards in key order. Copies of keys
+// hidden by a value on their owner are skipped.
+func (s *shardedBucket) iterator(reverse bool) Iterator {
+	var iterators []Iterator
+	for i, bucket := range s.buckets {
+		if bucket == nil {
+			continue
+		}
+		it := bucket.Iterator()
+		if reverse {
+			it = bucket.IteratorReverse()
+		}
+		iterators = append(iterators, &shardIterator{
+			Iterator: it,
+			bucket:   s,
+			index:    i,
+		})
+	}
+	return newMergeIterator(reverse, iterators...)
+}
+
+// order returns the index of owner followed by the indexes of all other shards.
+func (s *shardedBucket) order(owner int) []int {
+	result := make([]int, 0, len(s.buckets))
+	result = append(result, owner)
+	for i := range s.buckets {
+		if i != owner {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// shadowed returns true if the key is stored on shard index,
+// but the owner of the key has a value for it.
+func (s *shardedBucket) shadowed(index int, key []byte) bool {
+	owner := s.tx.db.ring.owner(s.name, key)
+	if owner == index || s.buckets[owner] == nil {
+		return false
+	}
+	item, err := s.buckets[owner].Get(context.Background(), key)
+	return err == nil && item.Exists()
+}
+
+// shardIterator skips the keys of a shard shadowed by their owner.
+type shardIterator struct {
+	Iterator
+	bucket *shardedBucket
+	index  int
+}
+
+func (s *shardIterator) Rewind() {
+	s.Iterator.Rewind()
+	s.skip()
+}
+
+func (s *shardIterator) Seek(key []byte) {
+	s.Iterator.Seek(key)
+	s.skip()
+}
+
+func (s *shardIterator) Next() {
+	s.Iterator.Next()
+	s.skip()
+}
+
+func (s *shardIterator) skip() {
+	for s.Iterator.Valid() && s.bucket.shadowed(s.index, s.Iterator.Item().Key()) {
+		s.Iterator.Next()
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("ShardedDB", func() {
+	var ctx context.Context
+	var shards []kv.Shard
+	var db kv.DB
+	var bucketName kv.BucketName
+	var keys []string
+	BeforeEach(func() {
+		ctx = context.Background()
+		shards = []kv.Shard{
+			{Name: "shard-0", DB: newMemoryDB()},
+			{Name: "shard-1", DB: newMemoryDB()},
+		}
+		var err error
+		db, err = kv.NewShardedDB(shards...)
+		Expect(err).To(BeNil())
+		bucketName = kv.NewBucketName("bucket")
+		keys = nil
+		for i := 0; i < 100; i++ {
+			keys = append(keys, fmt.Sprintf("key-%03d", i))
+		}
+		sort.Strings(keys)
+	})
+	putAll := func(db kv.DB, value string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				if err := bucket.Put(ctx, []byte(key), []byte(value)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	}
+	get := func(db kv.DB, key string) string {
+		var result string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			item, err := bucket.Get(ctx, []byte(key))
+			if err != nil {
+				return err
+			}
+			return item.Value(func(val []byte) error {
+				result = string(val)
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	count := func(db kv.DB) int {
+		var result int
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			result = len(collectKeys(bucket.Iterator(), nil))
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	iterate := func(db kv.DB, reverse bool) []string {
+		var result []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if reverse {
+				result = collectKeys(bucket.IteratorReverse(), nil)
+			} else {
+				result = collectKeys(bucket.Iterator(), nil)
+			}
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	It("spreads keys over all shards", func() {
+		putAll(db, "value")
+		Expect(count(shards[0].DB)).To(BeNumerically(">", 10))
+		Expect(count(shards[1].DB)).To(BeNumerically(">", 10))
+		Expect(count(shards[0].DB) + count(shards[1].DB)).To(Equal(len(keys)))
+		for _, key := range keys {
+			Expect(get(db, key)).To(Equal("value"))
+		}
+	})
+	It("merges iterators in key order", func() {
+		putAll(db, "value")
+		Expect(iterate(db, false)).To(Equal(keys))
+		reversed := append([]string{}, keys...)
+		sort.Sort(sort.Reverse(sort.StringSlice(reversed)))
+		Expect(iterate(db, true)).To(Equal(reversed))
+	})
+	It("aggregates stats", func() {
+		putAll(db, "value")
+		stats, err := db.StatsDetailed(ctx)
+		Expect(err).To(BeNil())
+		Expect(stats.Backend).To(Equal("sharded"))
+		Expect(stats.Buckets).To(HaveLen(1))
+		Expect(stats.Buckets[0].KeyCount).To(Equal(int64(len(keys))))
+	})
+	It("rejects invalid shards", func() {
+		for _, invalid := range [][]kv.Shard{
+			nil,
+			{{Name: "a", DB: newMemoryDB()}, {Name: "a", DB: newMemoryDB()}},
+			{{Name: "", DB: newMemoryDB()}},
+			{{Name: "a"}},
+		} {
+			_, err := kv.NewShardedDB(invalid...)
+			Expect(err).NotTo(BeNil())
+			_, err = kv.RebalanceShards(ctx, 10, invalid...)
+			Expect(err).NotTo(BeNil())
+		}
+	})
+	Context("with an added shard", func() {
+		var added kv.DB
+		BeforeEach(func() {
+			putAll(db, "old")
+			added = newMemoryDB()
+			shards = append(shards, kv.Shard{Name: "shard-2", DB: added})
+			var err error
+			db, err = kv.NewShardedDB(shards...)
+			Expect(err).To(BeNil())
+		})
+		It("reads keys not rebalanced yet", func() {
+			for _, key := range keys {
+				Expect(get(db, key)).To(Equal("old"))
+			}
+			Expect(iterate(db, false)).To(Equal(keys))
+		})
+		It("hides copies overwritten on the owner", func() {
+			putAll(db, "new")
+			Expect(iterate(db, false)).To(Equal(keys))
+			for _, key := range keys {
+				Expect(get(db, key)).To(Equal("new"))
+			}
+		})
+		It("moves keys to their owner", func() {
+			moved, err := kv.RebalanceShards(ctx, 10, shards...)
+			Expect(err).To(BeNil())
+			Expect(moved).To(BeNumerically(">", 10))
+			Expect(count(added)).To(Equal(moved))
+			Expect(count(shards[0].DB) + count(shards[1].DB) + count(added)).To(Equal(len(keys)))
+			for _, key := range keys {
+				Expect(get(db, key)).To(Equal("old"))
+			}
+			moved, err = kv.RebalanceShards(ctx, 10, shards...)
+			Expect(err).To(BeNil())
+			Expect(moved).To(Equal(0))
+		})
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewShardedDB(
+				kv.Shard{Name: "a", DB: newMemoryDB()},
+				kv.Shard{Name: "b", DB: newMemoryDB()},
+				kv.Shard{Name: "c", DB: newMemoryDB()},
+			)
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
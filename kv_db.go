@@ -15,6 +15,10 @@ var ErrTransactionAlreadyOpen = errors.New("transaction already open")
 // TransactionAlreadyOpenError is deprecated: use ErrTransactionAlreadyOpen instead.
 var TransactionAlreadyOpenError = ErrTransactionAlreadyOpen
 
+// ErrConflict is returned when a transaction conflicts with a concurrent transaction.
+// Backends wrap their native conflict error with it, so callers can retry (see NewDBWithRetry).
+var ErrConflict = errors.New("transaction conflict")
+
 //counterfeiter:generate -o mocks/db.go --fake-name DB . DB
 
 // DB represents a key-value database that supports transactions and lifecycle management.
@@ -0,0 +1,308 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// errDiffChunkFull stops the walk of Diff after ChunkSize differences to apply them.
+var errDiffChunkFull = stderrors.New("diff chunk full")
+
+// DiffKind is the kind of a Difference.
+type DiffKind string
+
+const (
+	// DiffMissingBucket is a bucket of a that does not exist in b.
+	// All its keys are reported as DiffMissingKey afterwards.
+	DiffMissingBucket DiffKind = "missing_bucket"
+	// DiffExtraBucket is a bucket of b that does not exist in a.
+	// Its keys are not reported.
+	DiffExtraBucket DiffKind = "extra_bucket"
+	// DiffMissingKey is a key of a that does not exist in b.
+	DiffMissingKey DiffKind = "missing_key"
+	// DiffExtraKey is a key of b that does not exist in a.
+	DiffExtraKey DiffKind = "extra_key"
+	// DiffChangedValue is a key with different values in a and b.
+	DiffChangedValue DiffKind = "changed_value"
+)
+
+// Difference between two DBs found by Diff.
+// Key is nil for bucket differences, AValue or BValue is nil if the key is missing.
+type Difference struct {
+	Kind       DiffKind
+	BucketName BucketName
+	Key        []byte
+	AValue     []byte
+	BValue     []byte
+}
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// BucketNames limits the diff to the given buckets, default are all buckets of a and b.
+	BucketNames BucketNames
+	// Handler is called for each difference in bucket and key order.
+	Handler func(ctx context.Context, difference Difference) error
+	// Apply writes the differences to b, so b is equal to a afterwards.
+	Apply bool
+	// ChunkSize is the maximum number of differences applied per transaction.
+	ChunkSize int
+}
+
+// DefaultDiffOptions returns the default DiffOptions.
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		ChunkSize: 1000,
+	}
+}
+
+// DiffResult counts the differences found by Diff.
+type DiffResult struct {
+	MissingBuckets int
+	ExtraBuckets   int
+	MissingKeys    int
+	ExtraKeys      int
+	ChangedValues  int
+}
+
+// Equal reports whether no differences were found.
+func (d DiffResult) Equal() bool {
+	return d == DiffResult{}
+}
+
+func (d *DiffResult) add(kind DiffKind) {
+	switch kind {
+	case DiffMissingBucket:
+		d.MissingBuckets++
+	case DiffExtraBucket:
+		d.ExtraBuckets++
+	case DiffMissingKey:
+		d.MissingKeys++
+	case DiffExtraKey:
+		d.ExtraKeys++
+	case DiffChangedValue:
+		d.ChangedValues++
+	}
+}
+
+// Diff walks the buckets and keys of a and b in order and passes each difference
+// to the Handler of the options, without loading the DBs into memory.
+// Without Apply both DBs are compared in a single read transaction each.
+// With Apply the walk stops every ChunkSize differences, applies them to b
+// in one transaction and continues after the last difference.
+func Diff(ctx context.Context, a DB, b DB, options DiffOptions) (*DiffResult, error) {
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultDiffOptions().ChunkSize
+	}
+	d := &differ{
+		a:       a,
+		b:       b,
+		options: options,
+		result:  &DiffResult{},
+	}
+	for {
+		full, err := d.chunk(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "diff failed")
+		}
+		if !full {
+			return d.result, nil
+		}
+	}
+}
+
+type differ struct {
+	a       DB
+	b       DB
+	options DiffOptions
+	result  *DiffResult
+	// position is the last reported difference, the walk continues after it
+	position Difference
+	pending  []Difference
+}
+
+// chunk walks from the current position and applies the found differences.
+// It returns true if the walk was stopped because the chunk is full.
+func (d *differ) chunk(ctx context.Context) (bool, error) {
+	d.pending = nil
+	err := d.a.View(ctx, func(aCtx context.Context, aTx Tx) error {
+		// open with the context of the caller, so b does not see a nested transaction
+		return d.b.View(ctx, func(ctx context.Context, bTx Tx) error {
+			return d.walk(ctx, aTx, bTx)
+		})
+	})
+	full := errors.Is(err, errDiffChunkFull)
+	if err != nil && !full {
+		return false, err
+	}
+	if len(d.pending) == 0 {
+		return full, nil
+	}
+	err = d.b.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return applyDifferences(ctx, tx, d.pending)
+	})
+	if err != nil {
+		return false, errors.Wrapf(ctx, err, "apply %d differences failed", len(d.pending))
+	}
+	glog.V(2).Infof("applied %d differences", len(d.pending))
+	return full, nil
+}
+
+func (d *differ) walk(ctx context.Context, aTx Tx, bTx Tx) error {
+	names, err := d.bucketNames(ctx, aTx, bTx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		bucketName := NewBucketName(name)
+		resumed := false
+		var after []byte
+		if d.position.BucketName != nil {
+			compare := bytes.Compare(bucketName, d.position.BucketName)
+			if compare < 0 {
+				continue
+			}
+			if compare == 0 {
+				resumed = true
+				after = d.position.Key
+			}
+		}
+		aBucket, err := optionalBucket(ctx, aTx, bucketName)
+		if err != nil {
+			return err
+		}
+		bBucket, err := optionalBucket(ctx, bTx, bucketName)
+		if err != nil {
+			return err
+		}
+		if aBucket == nil && (resumed || bBucket == nil) {
+			continue
+		}
+		if !resumed && aBucket == nil {
+			if err := d.emit(ctx, Difference{
+				Kind:       DiffExtraBucket,
+				BucketName: bucketName,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if !resumed && bBucket == nil {
+			if err := d.emit(ctx, Difference{
+				Kind:       DiffMissingBucket,
+				BucketName: bucketName,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := d.walkBucket(ctx, bucketName, aBucket, bBucket, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *differ) walkBucket(
+	ctx context.Context,
+	bucketName BucketName,
+	aBucket Bucket,
+	bBucket Bucket,
+	after []byte,
+) error {
+	fn := func(key []byte, aValue []byte, bValue []byte) error {
+		kind := DiffChangedValue
+		if aValue == nil {
+			kind = DiffExtraKey
+		} else if bValue == nil {
+			kind = DiffMissingKey
+		}
+		return d.emit(ctx, Difference{
+			Kind:       kind,
+			BucketName: bucketName,
+			Key:        key,
+			AValue:     aValue,
+			BValue:     bValue,
+		})
+	}
+	return diffBucketsAfter(ctx, aBucket, bBucket, after, fn)
+}
+
+// bucketNames returns the names to compare in bucket order.
+func (d *differ) bucketNames(ctx context.Context, aTx Tx, bTx Tx) ([]string, error) {
+	names := map[string]bool{}
+	for _, name := range d.options.BucketNames {
+		names[name.String()] = true
+	}
+	if len(names) > 0 {
+		return sortedBucketChangeNames(names), nil
+	}
+	for _, tx := range []Tx{aTx, bTx} {
+		bucketNames, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+		for _, name := range bucketNames {
+			names[name.String()] = true
+		}
+	}
+	return sortedBucketChangeNames(names), nil
+}
+
+func (d *differ) emit(ctx context.Context, difference Difference) error {
+	d.result.add(difference.Kind)
+	if d.options.Handler != nil {
+		if err := d.options.Handler(ctx, difference); err != nil {
+			return errors.Wrapf(ctx, err, "handle difference failed")
+		}
+	}
+	d.position = difference
+	if !d.options.Apply {
+		return nil
+	}
+	d.pending = append(d.pending, difference)
+	if len(d.pending) >= d.options.ChunkSize {
+		return errDiffChunkFull
+	}
+	return nil
+}
+
+// applyDifferences writes the differences to tx, so it matches a afterwards.
+func applyDifferences(ctx context.Context, tx Tx, differences []Difference) error {
+	for _, difference := range differences {
+		switch difference.Kind {
+		case DiffMissingBucket:
+			if _, err := tx.CreateBucketIfNotExists(ctx, difference.BucketName); err != nil {
+				return errors.Wrapf(ctx, err, "create bucket %s failed", difference.BucketName)
+			}
+		case DiffExtraBucket:
+			if err := tx.DeleteBucket(ctx, difference.BucketName); err != nil &&
+				!errors.Is(err, ErrBucketNotFound) {
+				return errors.Wrapf(ctx, err, "delete bucket %s failed", difference.BucketName)
+			}
+		case DiffMissingKey, DiffChangedValue:
+			bucket, err := tx.CreateBucketIfNotExists(ctx, difference.BucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "create bucket %s failed", difference.BucketName)
+			}
+			if err := bucket.Put(ctx, difference.Key, difference.AValue); err != nil {
+				return errors.Wrapf(ctx, err, "put %s failed", difference.Key)
+			}
+		case DiffExtraKey:
+			bucket, err := tx.Bucket(ctx, difference.BucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", difference.BucketName)
+			}
+			if err := bucket.Delete(ctx, difference.Key); err != nil {
+				return errors.Wrapf(ctx, err, "delete %s failed", difference.Key)
+			}
+		}
+	}
+	return nil
+}
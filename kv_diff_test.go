@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("Diff", func() {
+	var ctx context.Context
+	var a *memoryDB
+	var b *memoryDB
+	var differences []kv.Difference
+	var options kv.DiffOptions
+	put := func(db kv.DB, bucketName string, keyValues ...string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, kv.NewBucketName(bucketName))
+			if err != nil {
+				return err
+			}
+			for i := 0; i < len(keyValues); i += 2 {
+				err := bucket.Put(ctx, []byte(keyValues[i]), []byte(keyValues[i+1]))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	}
+	difference := func(kind kv.DiffKind, bucketName, key, aValue, bValue string) kv.Difference {
+		orNil := func(value string) []byte {
+			if value == "" {
+				return nil
+			}
+			return []byte(value)
+		}
+		return kv.Difference{
+			Kind:       kind,
+			BucketName: kv.NewBucketName(bucketName),
+			Key:        orNil(key),
+			AValue:     orNil(aValue),
+			BValue:     orNil(bValue),
+		}
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = newMemoryDB()
+		b = newMemoryDB()
+		differences = nil
+		options = kv.DefaultDiffOptions()
+		options.Handler = func(ctx context.Context, difference kv.Difference) error {
+			differences = append(differences, difference)
+			return nil
+		}
+		put(a, "equal", "a", "1", "b", "2")
+		put(b, "equal", "a", "1", "b", "2")
+		put(a, "keys", "a", "1", "b", "2", "c", "3")
+		put(b, "keys", "b", "X", "c", "3", "d", "4")
+		put(a, "only-a", "a", "1", "b", "2")
+		put(b, "only-b", "a", "1")
+	})
+	It("reports all differences in order", func() {
+		result, err := kv.Diff(ctx, a, b, options)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(&kv.DiffResult{
+			MissingBuckets: 1,
+			ExtraBuckets:   1,
+			MissingKeys:    3,
+			ExtraKeys:      1,
+			ChangedValues:  1,
+		}))
+		Expect(result.Equal()).To(BeFalse())
+		Expect(differences).To(Equal([]kv.Difference{
+			difference(kv.DiffMissingKey, "keys", "a", "1", ""),
+			difference(kv.DiffChangedValue, "keys", "b", "2", "X"),
+			difference(kv.DiffExtraKey, "keys", "d", "", "4"),
+			difference(kv.DiffMissingBucket, "only-a", "", "", ""),
+			difference(kv.DiffMissingKey, "only-a", "a", "1", ""),
+			difference(kv.DiffMissingKey, "only-a", "b", "2", ""),
+			difference(kv.DiffExtraBucket, "only-b", "", "", ""),
+		}))
+	})
+	It("limits the diff to the given buckets", func() {
+		options.BucketNames = kv.BucketNames{kv.NewBucketName("equal"), kv.NewBucketName("missing")}
+		result, err := kv.Diff(ctx, a, b, options)
+		Expect(err).To(BeNil())
+		Expect(result.Equal()).To(BeTrue())
+		Expect(differences).To(BeEmpty())
+	})
+	It("applies the differences in chunks", func() {
+		options.Apply = true
+		options.ChunkSize = 2
+		result, err := kv.Diff(ctx, a, b, options)
+		Expect(err).To(BeNil())
+		Expect(result.Equal()).To(BeFalse())
+		Expect(differences).To(HaveLen(7))
+
+		differences = nil
+		result, err = kv.Diff(ctx, a, b, kv.DiffOptions{})
+		Expect(err).To(BeNil())
+		Expect(result.Equal()).To(BeTrue())
+	})
+	It("returns the error of the handler", func() {
+		options.Handler = func(ctx context.Context, difference kv.Difference) error {
+			return errors.New("banana")
+		}
+		_, err := kv.Diff(ctx, a, b, options)
+		Expect(err).NotTo(BeNil())
+	})
+})
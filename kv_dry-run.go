@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// DryRunOperation is the kind of a DryRunChange.
+type DryRunOperation string
+
+const (
+	// DryRunCreateBucket is recorded if fn creates a bucket that does not exist.
+	DryRunCreateBucket DryRunOperation = "create_bucket"
+	// DryRunDeleteBucket is recorded if fn deletes an existing bucket.
+	DryRunDeleteBucket DryRunOperation = "delete_bucket"
+	// DryRunPut is recorded for each value written by fn.
+	DryRunPut DryRunOperation = "put"
+	// DryRunDelete is recorded for each key deleted by fn.
+	DryRunDelete DryRunOperation = "delete"
+)
+
+// DryRunChange is a single write intended by the fn of DryRun.
+// OldValue is the value before the write as seen by fn, nil if the key did not exist.
+type DryRunChange struct {
+	Operation  DryRunOperation
+	BucketName BucketName
+	Key        []byte
+	OldValue   []byte
+	NewValue   []byte
+}
+
+// DryRun runs fn like an Update of db and returns all writes in the order of fn,
+// without persisting any of them. fn reads its own writes. The writes are recorded
+// in a read transaction of db, so fn must not depend on being in a write transaction.
+// If fn fails, the changes until the failure are returned together with the error.
+func DryRun(
+	ctx context.Context,
+	db DB,
+	fn func(ctx context.Context, tx Tx) error,
+) ([]DryRunChange, error) {
+	var changes []DryRunChange
+	err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+		recorder := &dryRunTx{
+			Tx: newOverlayTx(tx, newSavepointLayer(0)),
+		}
+		err := fn(ctx, recorder)
+		changes = recorder.changes
+		return err
+	})
+	if err != nil {
+		return changes, errors.Wrapf(ctx, err, "dry run failed")
+	}
+	return changes, nil
+}
+
+// dryRunTx records all writes to an overlay.
+type dryRunTx struct {
+	Tx
+	changes []DryRunChange
+}
+
+func (d *dryRunTx) record(change DryRunChange) {
+	change.BucketName = bytes.Clone(change.BucketName)
+	change.Key = bytes.Clone(change.Key)
+	change.NewValue = bytes.Clone(change.NewValue)
+	d.changes = append(d.changes, change)
+}
+
+func (d *dryRunTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := d.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return d.wrap(name, bucket), nil
+}
+
+func (d *dryRunTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := d.Tx.CreateBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	d.record(DryRunChange{Operation: DryRunCreateBucket, BucketName: name})
+	return d.wrap(name, bucket), nil
+}
+
+func (d *dryRunTx) CreateBucketIfNotExists(ctx context.Context, name BucketName) (Bucket, error) {
+	if _, err := d.Tx.Bucket(ctx, name); err == nil {
+		return d.Bucket(ctx, name)
+	} else if !errors.Is(err, ErrBucketNotFound) {
+		return nil, err
+	}
+	return d.CreateBucket(ctx, name)
+}
+
+func (d *dryRunTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	if err := d.Tx.DeleteBucket(ctx, name); err != nil {
+		return err
+	}
+	d.record(DryRunChange{Operation: DryRunDeleteBucket, BucketName: name})
+	return nil
+}
+
+func (d *dryRunTx) wrap(name BucketName, bucket Bucket) Bucket {
+	return &dryRunBucket{
+		Bucket: bucket,
+		tx:     d,
+		name:   name,
+	}
+}
+
+type dryRunBucket struct {
+	Bucket
+	tx   *dryRunTx
+	name BucketName
+}
+
+func (d *dryRunBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	oldValue, err := d.oldValue(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := d.Bucket.Put(ctx, key, value); err != nil {
+		return err
+	}
+	d.tx.record(DryRunChange{
+		Operation:  DryRunPut,
+		BucketName: d.name,
+		Key:        key,
+		OldValue:   oldValue,
+		NewValue:   value,
+	})
+	return nil
+}
+
+func (d *dryRunBucket) Delete(ctx context.Context, key []byte) error {
+	oldValue, err := d.oldValue(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := d.Bucket.Delete(ctx, key); err != nil {
+		return err
+	}
+	d.tx.record(DryRunChange{
+		Operation:  DryRunDelete,
+		BucketName: d.name,
+		Key:        key,
+		OldValue:   oldValue,
+	})
+	return nil
+}
+
+func (d *dryRunBucket) oldValue(ctx context.Context, key []byte) ([]byte, error) {
+	item, err := d.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get old value of %s failed", key)
+	}
+	return itemValue(item)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("DryRun", func() {
+	var ctx context.Context
+	var db *memoryDB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = newMemoryDB()
+		bucketName = kv.NewBucketName("bucket")
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("a"), []byte("a"))
+		})).To(Succeed())
+	})
+	keys := func() []string {
+		var keys []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			keys = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return keys
+	}
+	It("returns all intended changes without persisting them", func() {
+		changes, err := kv.DryRun(ctx, db, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("a"), []byte("A")); err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("a"), []byte("AA")); err != nil {
+				return err
+			}
+			if err := bucket.Delete(ctx, []byte("a")); err != nil {
+				return err
+			}
+			other, err := tx.CreateBucket(ctx, kv.NewBucketName("other"))
+			if err != nil {
+				return err
+			}
+			if err := other.Put(ctx, []byte("b"), []byte("b")); err != nil {
+				return err
+			}
+			Expect(collectKeys(other.Iterator(), nil)).To(Equal([]string{"b"}))
+			return tx.DeleteBucket(ctx, bucketName)
+		})
+		Expect(err).To(BeNil())
+		Expect(changes).To(Equal([]kv.DryRunChange{
+			{
+				Operation:  kv.DryRunPut,
+				BucketName: bucketName,
+				Key:        []byte("a"),
+				OldValue:   []byte("a"),
+				NewValue:   []byte("A"),
+			},
+			{
+				Operation:  kv.DryRunPut,
+				BucketName: bucketName,
+				Key:        []byte("a"),
+				OldValue:   []byte("A"),
+				NewValue:   []byte("AA"),
+			},
+			{
+				Operation:  kv.DryRunDelete,
+				BucketName: bucketName,
+				Key:        []byte("a"),
+				OldValue:   []byte("AA"),
+			},
+			{
+				Operation:  kv.DryRunCreateBucket,
+				BucketName: kv.NewBucketName("other"),
+			},
+			{
+				Operation:  kv.DryRunPut,
+				BucketName: kv.NewBucketName("other"),
+				Key:        []byte("b"),
+				NewValue:   []byte("b"),
+			},
+			{
+				Operation:  kv.DryRunDeleteBucket,
+				BucketName: bucketName,
+			},
+		}))
+		Expect(keys()).To(Equal([]string{"a"}))
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			names, err := tx.ListBucketNames(ctx)
+			Expect(err).To(BeNil())
+			Expect(names).To(HaveLen(1))
+			return nil
+		})).To(Succeed())
+	})
+	It("returns the changes until fn failed", func() {
+		changes, err := kv.DryRun(ctx, db, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("b"), []byte("b")); err != nil {
+				return err
+			}
+			return errors.New("banana")
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(changes).To(HaveLen(1))
+		Expect(keys()).To(Equal([]string{"a"}))
+	})
+	It("returns ErrBucketAlreadyExists like a real transaction", func() {
+		changes, err := kv.DryRun(ctx, db, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.CreateBucket(ctx, bucketName)
+			return err
+		})
+		Expect(errors.Is(err, kv.ErrBucketAlreadyExists)).To(BeTrue())
+		Expect(changes).To(BeEmpty())
+	})
+})
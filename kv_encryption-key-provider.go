@@ -0,0 +1,43 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// NewStaticEncryptionKeyProvider returns an EncryptionKeyProvider with fixed keys by ID.
+// New values are encrypted with the key of currentID, the others are kept to decrypt old values.
+func NewStaticEncryptionKeyProvider(
+	currentID string,
+	keys map[string][]byte,
+) EncryptionKeyProvider {
+	return &staticEncryptionKeyProvider{
+		currentID: currentID,
+		keys:      keys,
+	}
+}
+
+type staticEncryptionKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+func (s *staticEncryptionKeyProvider) CurrentKey(ctx context.Context) (*EncryptionKey, error) {
+	return s.Key(ctx, s.currentID)
+}
+
+func (s *staticEncryptionKeyProvider) Key(ctx context.Context, id string) (*EncryptionKey, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, errors.Wrapf(ctx, ErrEncryptionKeyNotFound, "key id %s", id)
+	}
+	return &EncryptionKey{
+		ID:  id,
+		Key: key,
+	}, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrEncryptionKeyNotFound is returned if the EncryptionKeyProvider has no key with the ID.
+	ErrEncryptionKeyNotFound = errors.New("encryption key not found")
+	// ErrInvalidCiphertext is returned if a stored value can not be decrypted.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// EncryptionKey is an AES key of 16, 24 or 32 bytes with its ID.
+// The ID is stored with each value, so the key can be found after rotation.
+type EncryptionKey struct {
+	ID  string
+	Key []byte
+}
+
+//counterfeiter:generate -o mocks/encryption-key-provider.go --fake-name EncryptionKeyProvider . EncryptionKeyProvider
+
+// EncryptionKeyProvider provides the keys for encrypted buckets.
+type EncryptionKeyProvider interface {
+	// CurrentKey returns the key used to encrypt new values.
+	CurrentKey(ctx context.Context) (*EncryptionKey, error)
+	// Key returns the key with the given ID or ErrEncryptionKeyNotFound.
+	Key(ctx context.Context, id string) (*EncryptionKey, error)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ScrubResult lists the corrupted values found by Scrub.
+type ScrubResult struct {
+	Buckets   int
+	Checked   int
+	Corrupted []CorruptValueError
+}
+
+// Scrub verifies the checksums of all values in the given buckets, which must be the
+// buckets wrapped by NewDBWithIntegrity. Without bucket names all buckets of
+// ListBucketNames are checked, for a NewDBWithIntegrity covering all buckets.
+// For a wrapper scoped to some buckets pass the same bucket names, otherwise values
+// of buckets without integrity are reported as corrupted.
+// db must be the DB without integrity wrapper. Each bucket is checked in its own read
+// transaction.
+func Scrub(
+	ctx context.Context,
+	db DB,
+	checksum IntegrityChecksum,
+	bucketNames ...BucketName,
+) (*ScrubResult, error) {
+	if len(bucketNames) == 0 {
+		err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+			var err error
+			bucketNames, err = tx.ListBucketNames(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+	}
+	transform := &integrityTransform{checksum: checksum}
+	result := &ScrubResult{}
+	for _, bucketName := range bucketNames {
+		var checked int
+		var corrupted []CorruptValueError
+		err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+			checked = 0
+			corrupted = nil
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				if errors.Is(err, ErrBucketNotFound) {
+					return nil
+				}
+				return errors.Wrapf(ctx, err, "get bucket failed")
+			}
+			return ForEach(ctx, bucket, func(item Item) error {
+				return item.Value(func(val []byte) error {
+					if len(val) == 0 {
+						return nil
+					}
+					checked++
+					err := transform.verify(bucketName, item.Key(), val)
+					var corruptValueError CorruptValueError
+					if errors.As(err, &corruptValueError) {
+						corrupted = append(corrupted, corruptValueError)
+					}
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			return result, errors.Wrapf(ctx, err, "scrub bucket %s failed", bucketName)
+		}
+		result.Buckets++
+		result.Checked += checked
+		result.Corrupted = append(result.Corrupted, corrupted...)
+	}
+	if len(result.Corrupted) > 0 {
+		glog.Warningf(
+			"scrub found %d corrupted of %d values",
+			len(result.Corrupted),
+			result.Checked,
+		)
+	}
+	return result, nil
+}
+
+// NewScrubHandler returns a http.Handler
+// that runs Scrub and lists all corrupted values
+func NewScrubHandler(db DB, checksum IntegrityChecksum, bucketNames ...BucketName) http.Handler {
+	var lock sync.Mutex
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !lock.TryLock() {
+			glog.V(2).Infof("scrub running")
+			http.Error(resp, "scrub already running", http.StatusInternalServerError)
+			return
+		}
+		defer lock.Unlock()
+		glog.V(2).Infof("scrub started")
+
+		result, err := Scrub(req.Context(), db, checksum, bucketNames...)
+		if err != nil {
+			http.Error(
+				resp,
+				fmt.Sprintf("scrub failed: %v", err),
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		resp.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(
+			resp,
+			"scrubbed %d values in %d buckets, %d corrupted\n",
+			result.Checked,
+			result.Buckets,
+			len(result.Corrupted),
+		)
+		for _, corrupted := range result.Corrupted {
+			_, _ = fmt.Fprintf(resp, "%s %q\n", corrupted.BucketName, corrupted.Key)
+		}
+		glog.V(2).Infof("scrub successful")
+	})
+}
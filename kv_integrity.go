@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+)
+
+// ErrCorruptValue is matched by all CorruptValueErrors.
+var ErrCorruptValue = errors.New("corrupt value")
+
+// CorruptValueError is returned if the checksum of a value does not match.
+type CorruptValueError struct {
+	BucketName BucketName
+	Key        []byte
+}
+
+func (c CorruptValueError) Error() string {
+	return fmt.Sprintf("corrupt value of key %q in bucket %s", c.Key, c.BucketName)
+}
+
+// Is reports whether target is ErrCorruptValue.
+func (c CorruptValueError) Is(target error) bool {
+	return target == ErrCorruptValue
+}
+
+//counterfeiter:generate -o mocks/integrity-checksum.go --fake-name IntegrityChecksum . IntegrityChecksum
+
+// IntegrityChecksum calculates the checksum stored with each value by NewDBWithIntegrity.
+type IntegrityChecksum interface {
+	// Size returns the length of the checksum in bytes.
+	Size() int
+	// Sum returns the checksum of value stored in key of the bucket.
+	Sum(bucketName BucketName, key []byte, value []byte) []byte
+}
+
+// NewCRC32CChecksum returns an IntegrityChecksum detecting accidental corruption.
+func NewCRC32CChecksum() IntegrityChecksum {
+	table := crc32.MakeTable(crc32.Castagnoli)
+	return &hashChecksum{
+		newHash: func() hash.Hash {
+			return crc32.New(table)
+		},
+		size: crc32.Size,
+	}
+}
+
+// NewHMACChecksum returns an IntegrityChecksum using HMAC-SHA256 with the given secret,
+// which also detects deliberate modification.
+func NewHMACChecksum(secret []byte) IntegrityChecksum {
+	return &hashChecksum{
+		newHash: func() hash.Hash {
+			return hmac.New(sha256.New, secret)
+		},
+		size: sha256.Size,
+	}
+}
+
+type hashChecksum struct {
+	newHash func() hash.Hash
+	size    int
+}
+
+func (h *hashChecksum) Size() int {
+	return h.size
+}
+
+// Sum covers the length prefixed bucket name and key, so moved values are detected.
+func (h *hashChecksum) Sum(bucketName BucketName, key []byte, value []byte) []byte {
+	sum := h.newHash()
+	_, _ = sum.Write(binary.AppendUvarint(nil, uint64(len(bucketName))))
+	_, _ = sum.Write(bucketName)
+	_, _ = sum.Write(binary.AppendUvarint(nil, uint64(len(key))))
+	_, _ = sum.Write(key)
+	_, _ = sum.Write(value)
+	return sum.Sum(nil)
+}
@@ -4,6 +4,10 @@
 
 package kv
 
+import (
+	"bytes"
+)
+
 //counterfeiter:generate -o mocks/item.go --fake-name Item . Item
 
 // Item represents a key-value pair retrieved from a bucket with existence checking.
@@ -40,3 +44,16 @@ func (b byteItem) Key() []byte {
 func (b byteItem) Value(fn func(val []byte) error) error {
 	return fn(b.value)
 }
+
+// itemValue returns a copy of the value or nil if the item does not exist.
+func itemValue(item Item) ([]byte, error) {
+	if !item.Exists() {
+		return nil, nil
+	}
+	var result []byte
+	err := item.Value(func(val []byte) error {
+		result = bytes.Clone(val)
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// lruCache is a thread-safe least recently used cache bounded by entries and bytes.
+// A bound of zero or less disables it.
+type lruCache[V any] struct {
+	mux        sync.Mutex
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	list       *list.List
+	elements   map[string]*list.Element
+}
+
+type lruCacheEntry[V any] struct {
+	key   string
+	value V
+	size  int
+}
+
+func newLRUCache[V any](maxEntries int, maxBytes int) *lruCache[V] {
+	return &lruCache[V]{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		list:       list.New(),
+		elements:   map[string]*list.Element{},
+	}
+}
+
+// Get returns the value of key and marks it as recently used.
+func (l *lruCache[V]) Get(key string) (V, bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	element, ok := l.elements[key]
+	if !ok {
+		var empty V
+		return empty, false
+	}
+	l.list.MoveToFront(element)
+	return element.Value.(*lruCacheEntry[V]).value, true
+}
+
+// Add stores value with the given size and evicts the least recently used
+// entries until the cache is within its bounds. Values larger than the byte bound are not stored.
+func (l *lruCache[V]) Add(key string, value V, size int) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.remove(key)
+	if l.maxBytes > 0 && size > l.maxBytes {
+		return
+	}
+	l.elements[key] = l.list.PushFront(&lruCacheEntry[V]{
+		key:   key,
+		value: value,
+		size:  size,
+	})
+	l.bytes += size
+	for (l.maxEntries > 0 && l.list.Len() > l.maxEntries) ||
+		(l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.remove(l.list.Back().Value.(*lruCacheEntry[V]).key)
+	}
+}
+
+// Remove deletes key from the cache.
+func (l *lruCache[V]) Remove(key string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.remove(key)
+}
+
+// RemovePrefix deletes all keys starting with prefix from the cache.
+func (l *lruCache[V]) RemovePrefix(prefix string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	for key := range l.elements {
+		if strings.HasPrefix(key, prefix) {
+			l.remove(key)
+		}
+	}
+}
+
+// Purge deletes all keys from the cache.
+func (l *lruCache[V]) Purge() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.list.Init()
+	l.elements = map[string]*list.Element{}
+	l.bytes = 0
+}
+
+// Len returns the number of entries.
+func (l *lruCache[V]) Len() int {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.list.Len()
+}
+
+func (l *lruCache[V]) remove(key string) {
+	element, ok := l.elements[key]
+	if !ok {
+		return
+	}
+	l.list.Remove(element)
+	delete(l.elements, key)
+	l.bytes -= element.Value.(*lruCacheEntry[V]).size
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	"github.com/bborbe/kv"
+)
+
+// errMemoryTxNotWritable is returned by the test memory DB on writes inside View.
+var errMemoryTxNotWritable = errors.New("tx not writable")
+
+type memoryTxContextKey struct{}
+
+// newMemoryDB returns a minimal in-memory kv.DB used to exercise wrappers
+// against real transaction semantics (copy on write, commit on success).
+func newMemoryDB() *memoryDB {
+	return &memoryDB{
+		buckets: map[string]map[string][]byte{},
+	}
+}
+
+func newMemoryProvider() kv.Provider {
+	return kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+		return newMemoryDB(), nil
+	})
+}
+
+// memoryDB allows a single writer and concurrent readers. Committed bucket
+// maps are never modified, so View can work on a snapshot without locking.
+type memoryDB struct {
+	writeMux sync.Mutex
+	mux      sync.RWMutex
+	buckets  map[string]map[string][]byte
+}
+
+func (m *memoryDB) snapshot() map[string]map[string][]byte {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.buckets
+}
+
+func (m *memoryDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	if ctx.Value(memoryTxContextKey{}) != nil {
+		return kv.ErrTransactionAlreadyOpen
+	}
+	m.writeMux.Lock()
+	defer m.writeMux.Unlock()
+	tx := &memoryTx{
+		buckets:  copyMemoryBuckets(m.snapshot()),
+		cache:    map[string]*memoryBucket{},
+		writable: true,
+	}
+	if err := fn(context.WithValue(ctx, memoryTxContextKey{}, tx), tx); err != nil {
+		return err
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.buckets = tx.buckets
+	return nil
+}
+
+func (m *memoryDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	if ctx.Value(memoryTxContextKey{}) != nil {
+		return kv.ErrTransactionAlreadyOpen
+	}
+	tx := &memoryTx{
+		buckets: m.snapshot(),
+		cache:   map[string]*memoryBucket{},
+	}
+	return fn(context.WithValue(ctx, memoryTxContextKey{}, tx), tx)
+}
+
+func (m *memoryDB) Sync() error {
+	return nil
+}
+
+func (m *memoryDB) Close() error {
+	return nil
+}
+
+func (m *memoryDB) Remove() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.buckets = map[string]map[string][]byte{}
+	return nil
+}
+
+func (m *memoryDB) Stats(ctx context.Context) (*kv.Stats, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	stats := &kv.Stats{Backend: "memory"}
+	for _, name := range sortedMemoryKeys(m.buckets) {
+		stats.Buckets = append(stats.Buckets, kv.BucketStats{Name: kv.BucketName(name)})
+	}
+	return stats, nil
+}
+
+func (m *memoryDB) StatsDetailed(ctx context.Context) (*kv.Stats, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	stats := &kv.Stats{Backend: "memory", Detailed: true}
+	for _, name := range sortedMemoryKeys(m.buckets) {
+		bucketStats := kv.BucketStats{Name: kv.BucketName(name)}
+		for key, value := range m.buckets[name] {
+			bucketStats.KeyCount++
+			bucketStats.SizeB += int64(len(key) + len(value))
+		}
+		stats.SizeB += bucketStats.SizeB
+		stats.Buckets = append(stats.Buckets, bucketStats)
+	}
+	return stats, nil
+}
+
+type memoryTx struct {
+	buckets  map[string]map[string][]byte
+	cache    map[string]*memoryBucket
+	writable bool
+}
+
+func (t *memoryTx) Bucket(ctx context.Context, name kv.BucketName) (kv.Bucket, error) {
+	data, ok := t.buckets[name.String()]
+	if !ok {
+		return nil, kv.ErrBucketNotFound
+	}
+	if bucket, ok := t.cache[name.String()]; ok {
+		return bucket, nil
+	}
+	bucket := &memoryBucket{data: data, writable: t.writable}
+	t.cache[name.String()] = bucket
+	return bucket, nil
+}
+
+func (t *memoryTx) CreateBucket(ctx context.Context, name kv.BucketName) (kv.Bucket, error) {
+	if !t.writable {
+		return nil, errMemoryTxNotWritable
+	}
+	if _, ok := t.buckets[name.String()]; ok {
+		return nil, kv.ErrBucketAlreadyExists
+	}
+	t.buckets[name.String()] = map[string][]byte{}
+	return t.Bucket(ctx, name)
+}
+
+func (t *memoryTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name kv.BucketName,
+) (kv.Bucket, error) {
+	if _, ok := t.buckets[name.String()]; ok {
+		return t.Bucket(ctx, name)
+	}
+	return t.CreateBucket(ctx, name)
+}
+
+func (t *memoryTx) DeleteBucket(ctx context.Context, name kv.BucketName) error {
+	if !t.writable {
+		return errMemoryTxNotWritable
+	}
+	if _, ok := t.buckets[name.String()]; !ok {
+		return kv.ErrBucketNotFound
+	}
+	delete(t.buckets, name.String())
+	delete(t.cache, name.String())
+	return nil
+}
+
+func (t *memoryTx) ListBucketNames(ctx context.Context) (kv.BucketNames, error) {
+	result := kv.BucketNames{}
+	for _, name := range sortedMemoryKeys(t.buckets) {
+		result = append(result, kv.BucketName(name))
+	}
+	return result, nil
+}
+
+type memoryBucket struct {
+	data     map[string][]byte
+	writable bool
+}
+
+func (b *memoryBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if !b.writable {
+		return errMemoryTxNotWritable
+	}
+	b.data[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+func (b *memoryBucket) Get(ctx context.Context, key []byte) (kv.Item, error) {
+	return kv.NewByteItem(key, b.data[string(key)]), nil
+}
+
+func (b *memoryBucket) Delete(ctx context.Context, key []byte) error {
+	if !b.writable {
+		return errMemoryTxNotWritable
+	}
+	delete(b.data, string(key))
+	return nil
+}
+
+func (b *memoryBucket) Iterator() kv.Iterator {
+	return &memoryIterator{bucket: b}
+}
+
+func (b *memoryBucket) IteratorReverse() kv.Iterator {
+	return &memoryIterator{bucket: b, reverse: true}
+}
+
+type memoryIterator struct {
+	bucket  *memoryBucket
+	reverse bool
+	keys    []string
+	pos     int
+}
+
+func (i *memoryIterator) load() {
+	i.keys = sortedMemoryKeys(i.bucket.data)
+	if i.reverse {
+		for l, r := 0, len(i.keys)-1; l < r; l, r = l+1, r-1 {
+			i.keys[l], i.keys[r] = i.keys[r], i.keys[l]
+		}
+	}
+}
+
+func (i *memoryIterator) Close() {}
+
+func (i *memoryIterator) Item() kv.Item {
+	key := i.keys[i.pos]
+	return kv.NewByteItem([]byte(key), i.bucket.data[key])
+}
+
+func (i *memoryIterator) Next() {
+	i.pos++
+}
+
+func (i *memoryIterator) Valid() bool {
+	return i.pos < len(i.keys)
+}
+
+func (i *memoryIterator) Rewind() {
+	i.load()
+	i.pos = 0
+}
+
+func (i *memoryIterator) Seek(key []byte) {
+	i.load()
+	i.pos = sort.Search(len(i.keys), func(n int) bool {
+		if i.reverse {
+			return i.keys[n] <= string(key)
+		}
+		return i.keys[n] >= string(key)
+	})
+}
+
+func copyMemoryBuckets(buckets map[string]map[string][]byte) map[string]map[string][]byte {
+	result := make(map[string]map[string][]byte, len(buckets))
+	for name, data := range buckets {
+		copied := make(map[string][]byte, len(data))
+		for key, value := range data {
+			copied[key] = value
+		}
+		result[name] = copied
+	}
+	return result
+}
+
+func sortedMemoryKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ = Describe("memoryDB", func() {
+	kv.BasicTestSuite(newMemoryProvider())
+	kv.BucketTestSuite(newMemoryProvider())
+	kv.IteratorTestSuite(newMemoryProvider())
+	kv.RelationStoreTestSuite(newMemoryProvider())
+	kv.ModelTestSuite(newMemoryProvider())
+})
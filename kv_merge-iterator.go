@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+)
+
+// newMergeIterator merges iterators of the same direction into a single iterator in key order.
+// If several iterators contain the same key, the item of the first of them wins.
+// Winning tombstones (see deletedItem) hide the key.
+func newMergeIterator(reverse bool, iterators ...Iterator) Iterator {
+	return &mergeIterator{
+		reverse:   reverse,
+		iterators: iterators,
+		current:   -1,
+	}
+}
+
+type mergeIterator struct {
+	reverse   bool
+	iterators []Iterator
+	current   int
+}
+
+func (m *mergeIterator) Close() {
+	for _, it := range m.iterators {
+		it.Close()
+	}
+}
+
+func (m *mergeIterator) Item() Item {
+	return m.iterators[m.current].Item()
+}
+
+func (m *mergeIterator) Next() {
+	if m.current < 0 {
+		return
+	}
+	m.advance(bytes.Clone(m.Item().Key()))
+	m.settle()
+}
+
+func (m *mergeIterator) Valid() bool {
+	return m.current >= 0
+}
+
+func (m *mergeIterator) Rewind() {
+	for _, it := range m.iterators {
+		it.Rewind()
+	}
+	m.settle()
+}
+
+func (m *mergeIterator) Seek(key []byte) {
+	for _, it := range m.iterators {
+		it.Seek(key)
+	}
+	m.settle()
+}
+
+// settle selects the iterator with the next key and skips tombstones.
+func (m *mergeIterator) settle() {
+	for {
+		m.current = -1
+		var key []byte
+		for i, it := range m.iterators {
+			if !it.Valid() {
+				continue
+			}
+			itemKey := it.Item().Key()
+			if m.current < 0 || m.before(itemKey, key) {
+				m.current = i
+				key = itemKey
+			}
+		}
+		if m.current < 0 || !isDeletedItem(m.Item()) {
+			return
+		}
+		m.advance(bytes.Clone(key))
+	}
+}
+
+// advance moves all iterators positioned at key to their next item.
+func (m *mergeIterator) advance(key []byte) {
+	for _, it := range m.iterators {
+		if it.Valid() && bytes.Equal(it.Item().Key(), key) {
+			it.Next()
+		}
+	}
+}
+
+func (m *mergeIterator) before(a, b []byte) bool {
+	if m.reverse {
+		return bytes.Compare(a, b) > 0
+	}
+	return bytes.Compare(a, b) < 0
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/mirror-metrics.go --fake-name MirrorMetrics . MirrorMetrics
+
+// MirrorMetrics provides monitoring of NewMirrorDB using Prometheus.
+type MirrorMetrics interface {
+	MirrorAppliedAdd(count int)
+	MirrorPositionSet(position uint64)
+	MirrorFailureInc()
+	MirrorDivergenceInc(bucketName BucketName)
+	MirrorReadMismatchInc(bucketName BucketName)
+}
+
+// NewMirrorMetrics creates a new MirrorMetrics instance with default Prometheus collectors.
+func NewMirrorMetrics() MirrorMetrics {
+	return &mirrorMetrics{}
+}
+
+type mirrorMetrics struct {
+}
+
+func (m *mirrorMetrics) MirrorAppliedAdd(count int) {
+	dbMirrorAppliedCounter.Add(float64(count))
+}
+
+func (m *mirrorMetrics) MirrorPositionSet(position uint64) {
+	dbMirrorPositionGauge.Set(float64(position))
+}
+
+func (m *mirrorMetrics) MirrorFailureInc() {
+	dbMirrorFailureCounter.Inc()
+}
+
+func (m *mirrorMetrics) MirrorDivergenceInc(bucketName BucketName) {
+	dbMirrorDivergenceCounter.With(prometheus.Labels{
+		"bucket": bucketName.String(),
+	}).Inc()
+}
+
+func (m *mirrorMetrics) MirrorReadMismatchInc(bucketName BucketName) {
+	dbMirrorReadMismatchCounter.With(prometheus.Labels{
+		"bucket": bucketName.String(),
+	}).Inc()
+}
+
+var (
+	dbMirrorAppliedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "mirror_applied_total",
+		Help:      "Counts changes applied to the secondary DB",
+	})
+	dbMirrorPositionGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "mirror_position",
+		Help:      "Sequence of the last change applied to the secondary DB",
+	})
+	dbMirrorFailureCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "mirror_failures_total",
+		Help:      "Counts failed attempts to apply changes to the secondary DB",
+	})
+	dbMirrorDivergenceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "mirror_divergences_total",
+		Help:      "Counts keys with different values in primary and secondary DB found by Verify",
+	}, []string{"bucket"})
+	dbMirrorReadMismatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "mirror_read_mismatches_total",
+		Help:      "Counts reads with different values in primary and secondary DB",
+	}, []string{"bucket"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbMirrorAppliedCounter,
+		dbMirrorPositionGauge,
+		dbMirrorFailureCounter,
+		dbMirrorDivergenceCounter,
+		dbMirrorReadMismatchCounter,
+	)
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// mirrorEntry is a transaction of the change log.
+type mirrorEntry struct {
+	sequence uint64
+	changes  []mirrorChange
+}
+
+func (m *mirrorDB) CatchUp(ctx context.Context) (int, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var total int
+	for {
+		count, err := m.catchUpChunk(ctx)
+		if err != nil {
+			m.metrics.MirrorFailureInc()
+			return total, errors.Wrapf(ctx, err, "catch up failed")
+		}
+		total += count
+		if count < m.options.ChunkSize {
+			break
+		}
+	}
+	if total > 0 {
+		glog.V(3).Infof("applied %d changes to secondary", total)
+	}
+	return total, nil
+}
+
+// catchUpChunk applies up to ChunkSize entries of the change log in one transaction
+// of the secondary and deletes them from the change log afterwards.
+func (m *mirrorDB) catchUpChunk(ctx context.Context) (int, error) {
+	position, err := m.Position(ctx)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := m.readChangeLog(ctx, position)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	last := entries[len(entries)-1].sequence
+	err = m.secondary.Update(ctx, func(ctx context.Context, tx Tx) error {
+		for _, entry := range entries {
+			if err := applyMirrorChanges(ctx, tx, entry.changes); err != nil {
+				return errors.Wrapf(ctx, err, "apply change %d failed", entry.sequence)
+			}
+		}
+		positions, err := tx.CreateBucketIfNotExists(ctx, m.options.PositionBucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get position bucket failed")
+		}
+		return positions.Put(ctx, mirrorPositionKey, outboxKey(last))
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "update secondary failed")
+	}
+	m.metrics.MirrorAppliedAdd(len(entries))
+	m.metrics.MirrorPositionSet(last)
+	if err := m.trimChangeLog(ctx, last); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// readChangeLog returns up to ChunkSize entries after position.
+func (m *mirrorDB) readChangeLog(ctx context.Context, position uint64) ([]mirrorEntry, error) {
+	var entries []mirrorEntry
+	err := m.primary.View(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, m.options.ChangeLogBucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get change log bucket failed")
+		}
+		it := bucket.Iterator()
+		defer it.Close()
+		for it.Seek(outboxKey(position + 1)); it.Valid(); it.Next() {
+			if len(entries) >= m.options.ChunkSize {
+				return nil
+			}
+			item := it.Item()
+			sequence, err := parseOutboxKey(ctx, item.Key())
+			if err != nil {
+				return err
+			}
+			entry := mirrorEntry{sequence: sequence}
+			err = item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry.changes)
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "unmarshal change %d failed", sequence)
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read change log failed")
+	}
+	return entries, nil
+}
+
+// trimChangeLog deletes all entries of the change log up to position.
+func (m *mirrorDB) trimChangeLog(ctx context.Context, position uint64) error {
+	err := m.primary.Update(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, m.options.ChangeLogBucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get change log bucket failed")
+		}
+		end := outboxKey(position)
+		var keys [][]byte
+		it := bucket.Iterator()
+		for it.Rewind(); it.Valid() && bytes.Compare(it.Item().Key(), end) <= 0; it.Next() {
+			keys = append(keys, bytes.Clone(it.Item().Key()))
+		}
+		it.Close()
+		for _, key := range keys {
+			if err := bucket.Delete(ctx, key); err != nil {
+				return errors.Wrapf(ctx, err, "delete failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "trim change log failed")
+	}
+	return nil
+}
+
+func (m *mirrorDB) Position(ctx context.Context) (uint64, error) {
+	var position uint64
+	err := m.secondary.View(ctx, func(ctx context.Context, tx Tx) error {
+		var err error
+		position, err = m.readPosition(ctx, tx, mirrorPositionKey)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "get position failed")
+	}
+	return position, nil
+}
+
+// readPosition returns the sequence stored in key of the position bucket or 0.
+func (m *mirrorDB) readPosition(ctx context.Context, tx Tx, key []byte) (uint64, error) {
+	bucket, err := tx.Bucket(ctx, m.options.PositionBucketName)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(ctx, err, "get position bucket failed")
+	}
+	return mirrorSequence(ctx, bucket, key)
+}
+
+// mirrorSequence returns the sequence stored in key of the bucket or 0.
+func mirrorSequence(ctx context.Context, bucket Bucket, key []byte) (uint64, error) {
+	item, err := bucket.Get(ctx, key)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "get %s failed", key)
+	}
+	if !item.Exists() {
+		return 0, nil
+	}
+	var sequence uint64
+	err = item.Value(func(val []byte) error {
+		sequence, err = parseOutboxKey(ctx, val)
+		return err
+	})
+	return sequence, err
+}
+
+func (m *mirrorDB) Verify(
+	ctx context.Context,
+	bucketNames ...BucketName,
+) ([]MirrorDivergence, error) {
+	if _, err := m.CatchUp(ctx); err != nil {
+		return nil, err
+	}
+	var divergences []MirrorDivergence
+	err := m.primary.View(ctx, func(primaryCtx context.Context, primaryTx Tx) error {
+		sequence, err := m.readPosition(primaryCtx, primaryTx, mirrorSequenceKey)
+		if err != nil {
+			return err
+		}
+		// open with the context of the caller, so the secondary does not see a nested transaction
+		return m.secondary.View(ctx, func(ctx context.Context, secondaryTx Tx) error {
+			position, err := m.readPosition(ctx, secondaryTx, mirrorPositionKey)
+			if err != nil {
+				return err
+			}
+			if position != sequence {
+				return errors.Errorf(
+					ctx,
+					"secondary at position %d but primary at %d, primary changed while verifying",
+					position,
+					sequence,
+				)
+			}
+			names, err := m.verifyBucketNames(ctx, primaryTx, secondaryTx, bucketNames)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				result, err := verifyMirrorBucket(ctx, primaryTx, secondaryTx, name)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "verify bucket %s failed", name)
+				}
+				divergences = append(divergences, result...)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "verify failed")
+	}
+	for _, divergence := range divergences {
+		glog.Warningf("mirror diverged for %s in bucket %s", divergence.Key, divergence.BucketName)
+		m.metrics.MirrorDivergenceInc(divergence.BucketName)
+	}
+	return divergences, nil
+}
+
+// verifyBucketNames returns the given names or the buckets of both DBs
+// without the buckets of the mirror.
+func (m *mirrorDB) verifyBucketNames(
+	ctx context.Context,
+	primaryTx Tx,
+	secondaryTx Tx,
+	bucketNames BucketNames,
+) (BucketNames, error) {
+	if len(bucketNames) > 0 {
+		return bucketNames, nil
+	}
+	var result BucketNames
+	for _, tx := range []Tx{primaryTx, secondaryTx} {
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+		for _, name := range names {
+			if name.Equal(m.options.ChangeLogBucketName) ||
+				name.Equal(m.options.PositionBucketName) ||
+				result.Contains(name) {
+				continue
+			}
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+func verifyMirrorBucket(
+	ctx context.Context,
+	primaryTx Tx,
+	secondaryTx Tx,
+	bucketName BucketName,
+) ([]MirrorDivergence, error) {
+	primary, err := optionalBucket(ctx, primaryTx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := optionalBucket(ctx, secondaryTx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var result []MirrorDivergence
+	err = diffBuckets(ctx, primary, secondary, func(key []byte, a []byte, b []byte) error {
+		result = append(result, MirrorDivergence{
+			BucketName: bucketName,
+			Key:        key,
+			Primary:    a,
+			Secondary:  b,
+		})
+		return nil
+	})
+	return result, err
+}
+
+// optionalBucket returns the bucket or nil if it does not exist.
+func optionalBucket(ctx context.Context, tx Tx, bucketName BucketName) (Bucket, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(ctx, err, "get bucket %s failed", bucketName)
+	}
+	return bucket, nil
+}
+
+// applyMirrorChanges writes the changes of a transaction of the primary to tx.
+func applyMirrorChanges(ctx context.Context, tx Tx, changes []mirrorChange) error {
+	for _, change := range changes {
+		switch change.Operation {
+		case mirrorOperationCreateBucket:
+			if _, err := tx.CreateBucketIfNotExists(ctx, change.BucketName); err != nil {
+				return errors.Wrapf(ctx, err, "create bucket %s failed", change.BucketName)
+			}
+		case mirrorOperationDeleteBucket:
+			err := tx.DeleteBucket(ctx, change.BucketName)
+			if err != nil && !errors.Is(err, ErrBucketNotFound) {
+				return errors.Wrapf(ctx, err, "delete bucket %s failed", change.BucketName)
+			}
+		case mirrorOperationPut:
+			bucket, err := tx.CreateBucketIfNotExists(ctx, change.BucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "create bucket %s failed", change.BucketName)
+			}
+			if err := bucket.Put(ctx, change.Key, change.Value); err != nil {
+				return errors.Wrapf(ctx, err, "put %s failed", change.Key)
+			}
+		case mirrorOperationDelete:
+			bucket, err := optionalBucket(ctx, tx, change.BucketName)
+			if err != nil {
+				return err
+			}
+			if bucket == nil {
+				continue
+			}
+			if err := bucket.Delete(ctx, change.Key); err != nil {
+				return errors.Wrapf(ctx, err, "delete %s failed", change.Key)
+			}
+		default:
+			return errors.Errorf(ctx, "unknown operation %s", change.Operation)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+)
+
+// ErrMirrorFailed is returned by Update of a synchronous MirrorDB if the change was
+// committed to the primary but could not be applied to the secondary yet.
+// The change is applied by the next CatchUp.
+var ErrMirrorFailed = stderrors.New("mirror to secondary failed")
+
+// MirrorMode defines when a MirrorDB applies changes to the secondary.
+type MirrorMode int
+
+const (
+	// MirrorModeSync applies each change to the secondary before Update returns.
+	// This is the default.
+	MirrorModeSync MirrorMode = iota
+	// MirrorModeAsync applies changes to the secondary in Run.
+	MirrorModeAsync
+)
+
+// String returns the name of the mode.
+func (m MirrorMode) String() string {
+	switch m {
+	case MirrorModeSync:
+		return "Sync"
+	case MirrorModeAsync:
+		return "Async"
+	default:
+		return fmt.Sprintf("MirrorMode(%d)", int(m))
+	}
+}
+
+// MirrorOptions configures NewMirrorDB.
+// Unset values are replaced by the defaults of DefaultMirrorOptions.
+type MirrorOptions struct {
+	Mode MirrorMode
+	// ReadCompare also reads Get of View from the secondary and counts mismatches.
+	// In MirrorModeAsync mismatches of changes not applied yet are expected.
+	ReadCompare bool
+	// Interval between two catch-ups of Run.
+	Interval time.Duration
+	// ChunkSize is the maximum number of changes applied per transaction.
+	ChunkSize int
+	// ChangeLogBucketName is the bucket of the primary storing changes not applied yet.
+	ChangeLogBucketName BucketName
+	// PositionBucketName is the bucket storing the last sequence of the primary
+	// and the last applied sequence of the secondary.
+	PositionBucketName BucketName
+}
+
+// DefaultMirrorOptions returns the default MirrorOptions.
+func DefaultMirrorOptions() MirrorOptions {
+	return MirrorOptions{
+		Mode:                MirrorModeSync,
+		Interval:            10 * time.Second,
+		ChunkSize:           100,
+		ChangeLogBucketName: NewBucketName("mirror_changelog"),
+		PositionBucketName:  NewBucketName("mirror_position"),
+	}
+}
+
+// MirrorDivergence is a key with different values in primary and secondary.
+// A nil value means the key is missing.
+type MirrorDivergence struct {
+	BucketName BucketName
+	Key        []byte
+	Primary    []byte
+	Secondary  []byte
+}
+
+//counterfeiter:generate -o mocks/mirror-db.go --fake-name MirrorDB . MirrorDB
+
+// MirrorDB is a DB applying all committed writes to a secondary DB as well.
+type MirrorDB interface {
+	DB
+	// CatchUp applies all changes of the change log not applied to the secondary
+	// and returns their number.
+	CatchUp(ctx context.Context) (int, error)
+	// Position returns the sequence of the last change applied to the secondary.
+	Position(ctx context.Context) (uint64, error)
+	// Verify catches up and compares the given buckets, or all buckets if none is given,
+	// of primary and secondary. It fails if the primary changes while verifying.
+	Verify(ctx context.Context, bucketNames ...BucketName) ([]MirrorDivergence, error)
+	// Run catches up every Interval and after each Update in MirrorModeAsync
+	// until the context is canceled. It can be used as run.Func.
+	Run(ctx context.Context) error
+}
+
+// mirrorOperation is the kind of a recorded change.
+type mirrorOperation string
+
+const (
+	mirrorOperationPut          mirrorOperation = "put"
+	mirrorOperationDelete       mirrorOperation = "delete"
+	mirrorOperationCreateBucket mirrorOperation = "create_bucket"
+	mirrorOperationDeleteBucket mirrorOperation = "delete_bucket"
+)
+
+// mirrorChange is a single write of a transaction stored in the change log.
+type mirrorChange struct {
+	Operation  mirrorOperation `json:"op"`
+	BucketName BucketName      `json:"bucket"`
+	Key        []byte          `json:"key,omitempty"`
+	Value      []byte          `json:"value,omitempty"`
+}
@@ -0,0 +1,605 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bborbe/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// ModelTestSuite runs CheckModel with the random seed of Ginkgo,
+// so a failure can be reproduced with ginkgo --seed.
+func ModelTestSuite(provider Provider) {
+	GinkgoHelper()
+	Context("Model", func() {
+		It("matches the reference model", func() {
+			options := DefaultModelOptions()
+			options.Seed = GinkgoRandomSeed()
+			Expect(CheckModel(context.Background(), provider, options)).To(Succeed())
+		})
+	})
+}
+
+// modelBucketPrefix is the prefix of all buckets used by CheckModel,
+// other buckets of the DB are ignored.
+const modelBucketPrefix = "model_"
+
+var errModelRollback = stderrors.New("model rollback")
+
+var errModelMismatch = stderrors.New("model mismatch")
+
+// ModelOptions configures CheckModel.
+type ModelOptions struct {
+	// Seed of the generated sequences.
+	Seed int64
+	// Sequences is the number of generated sequences.
+	Sequences int
+	// Operations is the number of operations per sequence.
+	Operations int
+	// MaxShrinkRuns limits the runs to shrink a failing sequence.
+	MaxShrinkRuns int
+}
+
+// DefaultModelOptions returns the default ModelOptions.
+func DefaultModelOptions() ModelOptions {
+	return ModelOptions{
+		Sequences:     50,
+		Operations:    40,
+		MaxShrinkRuns: 500,
+	}
+}
+
+// ModelOperationKind is the kind of a ModelOperation.
+type ModelOperationKind string
+
+const (
+	// ModelCreateBucket creates the bucket, it fails if the bucket exists.
+	ModelCreateBucket ModelOperationKind = "create_bucket"
+	// ModelCreateBucketIfNotExists creates the bucket if it does not exist.
+	ModelCreateBucketIfNotExists ModelOperationKind = "create_bucket_if_not_exists"
+	// ModelDeleteBucket deletes the bucket, it fails if the bucket does not exist.
+	ModelDeleteBucket ModelOperationKind = "delete_bucket"
+	// ModelListBucketNames compares the names of all model buckets.
+	ModelListBucketNames ModelOperationKind = "list_bucket_names"
+	// ModelPut writes Value, which can be empty, to Key.
+	ModelPut ModelOperationKind = "put"
+	// ModelGet compares the value of Key.
+	ModelGet ModelOperationKind = "get"
+	// ModelDelete deletes Key.
+	ModelDelete ModelOperationKind = "delete"
+	// ModelSeek reads all items of an iterator after Seek of Key, or Rewind if Key is nil.
+	ModelSeek ModelOperationKind = "seek"
+	// ModelSeekReverse is ModelSeek with a reverse iterator.
+	ModelSeekReverse ModelOperationKind = "seek_reverse"
+	// ModelCommit ends the current transaction successfully, the next operation starts a new one.
+	ModelCommit ModelOperationKind = "commit"
+	// ModelRollback ends the current transaction with an error.
+	ModelRollback ModelOperationKind = "rollback"
+)
+
+// ModelOperation is a single generated operation of CheckModel.
+type ModelOperation struct {
+	Kind       ModelOperationKind
+	BucketName BucketName
+	Key        []byte
+	Value      []byte
+}
+
+// String returns the operation in a readable form.
+func (m ModelOperation) String() string {
+	parts := []string{string(m.Kind)}
+	if m.BucketName != nil {
+		parts = append(parts, m.BucketName.String())
+	}
+	if m.Key != nil {
+		parts = append(parts, string(m.Key))
+	}
+	if m.Value != nil {
+		parts = append(parts, fmt.Sprintf("%q", m.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ModelFailure is returned by CheckModel for a sequence with results different from
+// the reference model. Operations is the shrunk sequence reproducing the failure.
+type ModelFailure struct {
+	Seed       int64
+	Sequence   int
+	Operations []ModelOperation
+	// Index of the failed operation, len(Operations) for the final check of all buckets.
+	Index   int
+	Message string
+	// kind is the kind of the failed operation and the format of Message,
+	// shrinking keeps only sequences failing with the same kind.
+	kind string
+}
+
+func newModelFailure(
+	operations []ModelOperation,
+	index int,
+	mismatch *modelMismatch,
+) *ModelFailure {
+	kind := "check"
+	if index < len(operations) {
+		kind = string(operations[index].Kind)
+	}
+	return &ModelFailure{
+		Operations: operations,
+		Index:      index,
+		Message:    mismatch.String(),
+		kind:       kind + ": " + mismatch.format,
+	}
+}
+
+// Error returns the failure with the sequence of operations.
+func (m *ModelFailure) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(
+		&builder,
+		"model check with seed %d failed in sequence %d at operation %d: %s",
+		m.Seed,
+		m.Sequence,
+		m.Index,
+		m.Message,
+	)
+	for i, operation := range m.Operations {
+		marker := " "
+		if i == m.Index {
+			marker = ">"
+		}
+		fmt.Fprintf(&builder, "\n%s %3d %s", marker, i, operation)
+	}
+	return builder.String()
+}
+
+// CheckModel generates random sequences of bucket and key operations in transactions,
+// runs each against a fresh DB of the provider and a reference model and compares
+// all results. Each transaction is committed or rolled back, after each sequence all
+// buckets are compared. A failing sequence is shrunk to the minimal sequence still
+// failing and returned as ModelFailure. Only buckets with the prefix model_ are used.
+func CheckModel(ctx context.Context, provider Provider, options ModelOptions) error {
+	defaults := DefaultModelOptions()
+	if options.Sequences <= 0 {
+		options.Sequences = defaults.Sequences
+	}
+	if options.Operations <= 0 {
+		options.Operations = defaults.Operations
+	}
+	if options.MaxShrinkRuns <= 0 {
+		options.MaxShrinkRuns = defaults.MaxShrinkRuns
+	}
+	for sequence := 0; sequence < options.Sequences; sequence++ {
+		random := rand.New(rand.NewPCG(uint64(options.Seed), uint64(sequence)))
+		operations := generateModelOperations(random, options.Operations)
+		failure, err := runModel(ctx, provider, operations)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "run sequence %d failed", sequence)
+		}
+		if failure == nil {
+			continue
+		}
+		failure = shrinkModel(ctx, provider, failure, options.MaxShrinkRuns)
+		failure.Seed = options.Seed
+		failure.Sequence = sequence
+		return failure
+	}
+	return nil
+}
+
+func generateModelOperations(random *rand.Rand, count int) []ModelOperation {
+	bucketNames := []string{"a", "b", "c"}
+	keys := []string{"k0", "k1", "k1a", "k2", "k3", "k5"}
+	seekKeys := append([]string{"k", "k2a", "k4", "z"}, keys...)
+	weights := []struct {
+		kind   ModelOperationKind
+		weight int
+	}{
+		{ModelPut, 30},
+		{ModelGet, 12},
+		{ModelDelete, 10},
+		{ModelSeek, 10},
+		{ModelSeekReverse, 6},
+		{ModelCreateBucketIfNotExists, 8},
+		{ModelCreateBucket, 4},
+		{ModelDeleteBucket, 3},
+		{ModelListBucketNames, 3},
+		{ModelCommit, 9},
+		{ModelRollback, 5},
+	}
+	total := 0
+	for _, weight := range weights {
+		total += weight.weight
+	}
+	result := make([]ModelOperation, 0, count)
+	for i := 0; i < count; i++ {
+		n := random.IntN(total)
+		var operation ModelOperation
+		for _, weight := range weights {
+			if n < weight.weight {
+				operation.Kind = weight.kind
+				break
+			}
+			n -= weight.weight
+		}
+		switch operation.Kind {
+		case ModelCommit, ModelRollback, ModelListBucketNames:
+		default:
+			bucketName := bucketNames[random.IntN(len(bucketNames))]
+			operation.BucketName = NewBucketName(modelBucketPrefix + bucketName)
+		}
+		switch operation.Kind {
+		case ModelPut:
+			// empty values exist for iterators, but not for Item.Exists
+			operation.Value = []byte{}
+			if random.IntN(10) > 0 {
+				operation.Value = []byte(fmt.Sprintf("v%d", random.IntN(100)))
+			}
+			fallthrough
+		case ModelGet, ModelDelete:
+			operation.Key = []byte(keys[random.IntN(len(keys))])
+		case ModelSeek, ModelSeekReverse:
+			if random.IntN(4) > 0 {
+				operation.Key = []byte(seekKeys[random.IntN(len(seekKeys))])
+			}
+		}
+		result = append(result, operation)
+	}
+	return result
+}
+
+// shrinkModel removes chunks of operations from the failing sequence, as long as the
+// sequence still fails with the same kind of failure, starting with large chunks.
+func shrinkModel(
+	ctx context.Context,
+	provider Provider,
+	failure *ModelFailure,
+	maxRuns int,
+) *ModelFailure {
+	runs := 0
+	chunk := len(failure.Operations) / 2
+	for chunk >= 1 && runs < maxRuns {
+		removed := false
+		for start := 0; start < len(failure.Operations) && runs < maxRuns; {
+			end := min(start+chunk, len(failure.Operations))
+			candidate := append(
+				append([]ModelOperation{}, failure.Operations[:start]...),
+				failure.Operations[end:]...,
+			)
+			runs++
+			candidateFailure, err := runModel(ctx, provider, candidate)
+			if err != nil {
+				return failure
+			}
+			if candidateFailure != nil && candidateFailure.kind == failure.kind {
+				failure = candidateFailure
+				removed = true
+				continue
+			}
+			start += chunk
+		}
+		if !removed {
+			chunk /= 2
+		}
+		chunk = min(chunk, len(failure.Operations)/2)
+	}
+	return failure
+}
+
+// modelMismatch is a result of the DB different from the model.
+type modelMismatch struct {
+	format string
+	args   []any
+}
+
+func newModelMismatch(format string, args ...any) *modelMismatch {
+	return &modelMismatch{
+		format: format,
+		args:   args,
+	}
+}
+
+func (m *modelMismatch) String() string {
+	return fmt.Sprintf(m.format, m.args...)
+}
+
+// modelState maps bucket names to keys and values.
+type modelState map[string]map[string][]byte
+
+func (m modelState) clone() modelState {
+	result := make(modelState, len(m))
+	for name, data := range m {
+		copied := make(map[string][]byte, len(data))
+		for key, value := range data {
+			copied[key] = value
+		}
+		result[name] = copied
+	}
+	return result
+}
+
+// runModel runs the operations against a DB of the provider and returns
+// a ModelFailure if a result differs from the model.
+func runModel(
+	ctx context.Context,
+	provider Provider,
+	operations []ModelOperation,
+) (*ModelFailure, error) {
+	db, err := provider.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get db failed")
+	}
+	defer db.Close()
+	if err := removeModelBuckets(ctx, db); err != nil {
+		return nil, err
+	}
+	committed := modelState{}
+	for pos := 0; pos < len(operations); {
+		start := pos
+		var working modelState
+		var mismatch *modelMismatch
+		err := db.Update(ctx, func(ctx context.Context, tx Tx) error {
+			working = committed.clone()
+			for pos = start; pos < len(operations); pos++ {
+				switch operations[pos].Kind {
+				case ModelCommit:
+					pos++
+					return nil
+				case ModelRollback:
+					pos++
+					return errModelRollback
+				}
+				mismatch = applyModelOperation(ctx, tx, working, operations[pos])
+				if mismatch != nil {
+					return errModelMismatch
+				}
+			}
+			return nil
+		})
+		if mismatch != nil {
+			return newModelFailure(operations, pos, mismatch), nil
+		}
+		if errors.Is(err, errModelRollback) {
+			continue
+		}
+		if err != nil {
+			return newModelFailure(
+				operations,
+				max(pos-1, start),
+				newModelMismatch("update failed: %v", err),
+			), nil
+		}
+		committed = working
+	}
+	if mismatch := checkModelState(ctx, db, committed); mismatch != nil {
+		return newModelFailure(operations, len(operations), mismatch), nil
+	}
+	return nil, nil
+}
+
+func removeModelBuckets(ctx context.Context, db DB) error {
+	err := db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		names, err := listModelBucketNames(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := tx.DeleteBucket(ctx, NewBucketName(name)); err != nil {
+				return errors.Wrapf(ctx, err, "delete bucket %s failed", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "remove model buckets failed")
+	}
+	return nil
+}
+
+// checkModelState compares all model buckets of the DB with the model.
+func checkModelState(ctx context.Context, db DB, model modelState) *modelMismatch {
+	var mismatch *modelMismatch
+	err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+		names, err := listModelBucketNames(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if expected := sortedBucketChangeNames(model); !slices.Equal(names, expected) {
+			mismatch = newModelMismatch("expected buckets %q but got %q", expected, names)
+			return nil
+		}
+		for _, name := range names {
+			bucket, err := tx.Bucket(ctx, NewBucketName(name))
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", name)
+			}
+			actual, err := readModelIterator(bucket.Iterator(), nil)
+			if err != nil {
+				return err
+			}
+			if expected := expectedModelSeek(model[name], nil, false); expected != actual {
+				mismatch = newModelMismatch(
+					"expected bucket %s with %s but got %s",
+					name,
+					expected,
+					actual,
+				)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return newModelMismatch("view failed: %v", err)
+	}
+	return mismatch
+}
+
+// applyModelOperation runs the operation against tx and the model
+// and returns a message if the results differ.
+func applyModelOperation(
+	ctx context.Context,
+	tx Tx,
+	model modelState,
+	operation ModelOperation,
+) *modelMismatch {
+	name := operation.BucketName.String()
+	data, exists := model[name]
+	switch operation.Kind {
+	case ModelCreateBucket:
+		_, err := tx.CreateBucket(ctx, operation.BucketName)
+		if exists {
+			return expectModelError(err, ErrBucketAlreadyExists)
+		}
+		model[name] = map[string][]byte{}
+		return expectModelError(err, nil)
+	case ModelCreateBucketIfNotExists:
+		_, err := tx.CreateBucketIfNotExists(ctx, operation.BucketName)
+		if !exists {
+			model[name] = map[string][]byte{}
+		}
+		return expectModelError(err, nil)
+	case ModelDeleteBucket:
+		err := tx.DeleteBucket(ctx, operation.BucketName)
+		if !exists {
+			return expectModelError(err, ErrBucketNotFound)
+		}
+		delete(model, name)
+		return expectModelError(err, nil)
+	case ModelListBucketNames:
+		names, err := listModelBucketNames(ctx, tx)
+		if err != nil {
+			return expectModelError(err, nil)
+		}
+		if expected := sortedBucketChangeNames(model); !slices.Equal(names, expected) {
+			return newModelMismatch("expected buckets %q but got %q", expected, names)
+		}
+		return nil
+	}
+	bucket, err := tx.Bucket(ctx, operation.BucketName)
+	if !exists {
+		return expectModelError(err, ErrBucketNotFound)
+	}
+	if err != nil {
+		return expectModelError(err, nil)
+	}
+	switch operation.Kind {
+	case ModelPut:
+		data[string(operation.Key)] = operation.Value
+		return expectModelError(bucket.Put(ctx, operation.Key, operation.Value), nil)
+	case ModelDelete:
+		delete(data, string(operation.Key))
+		return expectModelError(bucket.Delete(ctx, operation.Key), nil)
+	case ModelGet:
+		item, err := bucket.Get(ctx, operation.Key)
+		if err != nil {
+			return expectModelError(err, nil)
+		}
+		value, err := itemValue(item)
+		if err != nil {
+			return expectModelError(err, nil)
+		}
+		expected := data[string(operation.Key)]
+		if !bytes.Equal(expected, value) {
+			return newModelMismatch("expected value %q but got %q", expected, value)
+		}
+		if exists := len(expected) > 0; item.Exists() != exists {
+			return newModelMismatch("expected exists %v but got %v", exists, item.Exists())
+		}
+		return nil
+	case ModelSeek, ModelSeekReverse:
+		reverse := operation.Kind == ModelSeekReverse
+		iterator := bucket.Iterator()
+		if reverse {
+			iterator = bucket.IteratorReverse()
+		}
+		actual, err := readModelIterator(iterator, operation.Key)
+		if err != nil {
+			return expectModelError(err, nil)
+		}
+		if expected := expectedModelSeek(data, operation.Key, reverse); expected != actual {
+			return newModelMismatch("expected items %s but got %s", expected, actual)
+		}
+		return nil
+	default:
+		return newModelMismatch("unknown operation %s", operation.Kind)
+	}
+}
+
+// expectModelError returns a message if err is not the expected error.
+func expectModelError(err error, expected error) *modelMismatch {
+	if expected == nil {
+		if err != nil {
+			return newModelMismatch("expected no error but got %v", err)
+		}
+		return nil
+	}
+	if !errors.Is(err, expected) {
+		return newModelMismatch("expected error "+expected.Error()+" but got %v", err)
+	}
+	return nil
+}
+
+func listModelBucketNames(ctx context.Context, tx Tx) ([]string, error) {
+	names, err := tx.ListBucketNames(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "list bucket names failed")
+	}
+	result := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name.String(), modelBucketPrefix) {
+			result = append(result, name.String())
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// readModelIterator returns all items after Seek of key, or Rewind if key is nil.
+func readModelIterator(iterator Iterator, key []byte) (string, error) {
+	defer iterator.Close()
+	if key == nil {
+		iterator.Rewind()
+	} else {
+		iterator.Seek(key)
+	}
+	var items []string
+	for ; iterator.Valid(); iterator.Next() {
+		value, err := itemValue(iterator.Item())
+		if err != nil {
+			return "", err
+		}
+		items = append(items, fmt.Sprintf("%s=%s", iterator.Item().Key(), value))
+	}
+	return fmt.Sprintf("%q", items), nil
+}
+
+// expectedModelSeek returns the items of the model readModelIterator must return.
+func expectedModelSeek(data map[string][]byte, key []byte, reverse bool) string {
+	keys := sortedBucketChangeNames(data)
+	if reverse {
+		for l, r := 0, len(keys)-1; l < r; l, r = l+1, r-1 {
+			keys[l], keys[r] = keys[r], keys[l]
+		}
+	}
+	var items []string
+	for _, k := range keys {
+		compare := bytes.Compare([]byte(k), key)
+		if key != nil && ((!reverse && compare < 0) || (reverse && compare > 0)) {
+			continue
+		}
+		items = append(items, fmt.Sprintf("%s=%s", k, data[k]))
+	}
+	return fmt.Sprintf("%q", items)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+// commitOnErrorDB commits the writes of fn even if fn fails.
+type commitOnErrorDB struct {
+	kv.DB
+}
+
+func (c commitOnErrorDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	var fnErr error
+	err := c.DB.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+		fnErr = fn(ctx, tx)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// viewFailsEarlyDB fails View until the DB saw three transactions of Update.
+type viewFailsEarlyDB struct {
+	kv.DB
+	updates int
+}
+
+func (v *viewFailsEarlyDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	v.updates++
+	return v.DB.Update(ctx, fn)
+}
+
+func (v *viewFailsEarlyDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	if v.updates < 3 {
+		return errors.New("banana")
+	}
+	return v.DB.View(ctx, fn)
+}
+
+// dropEmptyDB ignores Put of empty values.
+type dropEmptyDB struct {
+	kv.DB
+}
+
+func (d dropEmptyDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	return d.DB.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+		return fn(ctx, dropEmptyTx{Tx: tx})
+	})
+}
+
+type dropEmptyTx struct {
+	kv.Tx
+}
+
+func (d dropEmptyTx) Bucket(ctx context.Context, name kv.BucketName) (kv.Bucket, error) {
+	bucket, err := d.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return dropEmptyBucket{Bucket: bucket}, nil
+}
+
+type dropEmptyBucket struct {
+	kv.Bucket
+}
+
+func (d dropEmptyBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if len(value) == 0 {
+		return nil
+	}
+	return d.Bucket.Put(ctx, key, value)
+}
+
+var _ = Describe("CheckModel", func() {
+	var ctx context.Context
+	var options kv.ModelOptions
+	BeforeEach(func() {
+		ctx = context.Background()
+		options = kv.DefaultModelOptions()
+		options.Seed = 42
+	})
+	It("succeeds for a correct DB", func() {
+		Expect(kv.CheckModel(ctx, newMemoryProvider(), options)).To(Succeed())
+	})
+	It("shrinks the sequence of a broken DB reproducibly", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return commitOnErrorDB{DB: newMemoryDB()}, nil
+		})
+		err := kv.CheckModel(ctx, provider, options)
+		var failure *kv.ModelFailure
+		Expect(errors.As(err, &failure)).To(BeTrue())
+		Expect(failure.Seed).To(Equal(int64(42)))
+		Expect(len(failure.Operations)).To(BeNumerically("<=", 3))
+		var kinds []kv.ModelOperationKind
+		for _, operation := range failure.Operations {
+			kinds = append(kinds, operation.Kind)
+		}
+		Expect(kinds).To(ContainElement(kv.ModelRollback))
+		Expect(err.Error()).To(ContainSubstring("rollback"))
+
+		Expect(kv.CheckModel(ctx, provider, options)).To(Equal(err))
+	})
+	It("shrinks only to sequences with the same failure", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return &viewFailsEarlyDB{DB: commitOnErrorDB{DB: newMemoryDB()}}, nil
+		})
+		err := kv.CheckModel(ctx, provider, options)
+		var failure *kv.ModelFailure
+		Expect(errors.As(err, &failure)).To(BeTrue())
+		Expect(failure.Message).NotTo(ContainSubstring("banana"))
+		var kinds []kv.ModelOperationKind
+		for _, operation := range failure.Operations {
+			kinds = append(kinds, operation.Kind)
+		}
+		Expect(kinds).To(ContainElement(kv.ModelRollback))
+	})
+	It("detects a DB ignoring empty values", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return dropEmptyDB{DB: newMemoryDB()}, nil
+		})
+		err := kv.CheckModel(ctx, provider, options)
+		var failure *kv.ModelFailure
+		Expect(errors.As(err, &failure)).To(BeTrue())
+		Expect(failure.Operations).To(ContainElement(HaveField("Value", Equal([]byte{}))))
+	})
+	Context("test suites", func() {
+		kv.ModelTestSuite(kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return kv.NewDBWithSavepoints(newMemoryDB()), nil
+		}))
+	})
+})
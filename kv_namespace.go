@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// NamespaceSeparator separates the namespace from the bucket name.
+// Namespaces must not contain it, otherwise one namespace could contain another.
+const NamespaceSeparator = ":"
+
+// ErrInvalidNamespace is returned for empty namespaces and namespaces containing
+// the NamespaceSeparator.
+var ErrInvalidNamespace = stderrors.New("invalid namespace")
+
+// Namespace isolates the buckets of a tenant, see NewNamespacedDB.
+type Namespace string
+
+// Validate returns ErrInvalidNamespace if the namespace is empty or contains
+// the NamespaceSeparator, because it would contain the buckets of other namespaces.
+func (n Namespace) Validate(ctx context.Context) error {
+	if n == "" {
+		return errors.Wrapf(ctx, ErrInvalidNamespace, "namespace is empty")
+	}
+	if strings.Contains(n.String(), NamespaceSeparator) {
+		return errors.Wrapf(
+			ctx,
+			ErrInvalidNamespace,
+			"namespace %s contains separator %s",
+			n,
+			NamespaceSeparator,
+		)
+	}
+	return nil
+}
+
+// String returns the namespace as a string.
+func (n Namespace) String() string {
+	return string(n)
+}
+
+// Prefix returns the prefix of all bucket names in the namespace.
+func (n Namespace) Prefix() []byte {
+	return []byte(string(n) + NamespaceSeparator)
+}
+
+// BucketName returns the name of the bucket in the underlying DB.
+func (n Namespace) BucketName(name BucketName) BucketName {
+	return append(n.Prefix(), name...)
+}
+
+// Contains returns true if the bucket name of the underlying DB belongs to the namespace.
+func (n Namespace) Contains(name BucketName) bool {
+	return bytes.HasPrefix(name, n.Prefix())
+}
+
+// TrimBucketName returns the bucket name without the namespace.
+func (n Namespace) TrimBucketName(name BucketName) BucketName {
+	return BucketName(bytes.TrimPrefix(name, n.Prefix()))
+}
+
+// DropNamespace deletes all buckets of the namespace in a single transaction.
+// db must be the DB without namespace.
+func DropNamespace(ctx context.Context, db DB, namespace Namespace) error {
+	if err := namespace.Validate(ctx); err != nil {
+		return err
+	}
+	var count int
+	err := db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		count = 0
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+		for _, name := range names {
+			if !namespace.Contains(name) {
+				continue
+			}
+			if err := tx.DeleteBucket(ctx, name); err != nil {
+				return errors.Wrapf(ctx, err, "delete bucket %s failed", name)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "drop namespace %s failed", namespace)
+	}
+	glog.V(2).Infof("dropped %d buckets of namespace %s", count, namespace)
+	return nil
+}
+
+// NamespaceExportEntry is a single line written by ExportNamespace.
+// The bucket name is without the namespace.
+type NamespaceExportEntry struct {
+	BucketName BucketName `json:"bucket"`
+	Key        []byte     `json:"key"`
+	Value      []byte     `json:"value"`
+}
+
+// ExportNamespace writes all keys of the namespace as JSON lines of NamespaceExportEntry
+// within a single read transaction. db must be the DB without namespace.
+func ExportNamespace(ctx context.Context, db DB, namespace Namespace, writer io.Writer) error {
+	if err := namespace.Validate(ctx); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(writer)
+	err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+		names, err := tx.ListBucketNames(ctx)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "list bucket names failed")
+		}
+		for _, name := range names {
+			if !namespace.Contains(name) {
+				continue
+			}
+			bucket, err := tx.Bucket(ctx, name)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", name)
+			}
+			err = ForEach(ctx, bucket, func(item Item) error {
+				return item.Value(func(val []byte) error {
+					return encoder.Encode(NamespaceExportEntry{
+						BucketName: namespace.TrimBucketName(name),
+						Key:        item.Key(),
+						Value:      val,
+					})
+				})
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "export bucket %s failed", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "export namespace %s failed", namespace)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// OutboxDispatcherOptions configures the OutboxDispatcher.
+// Zero values are replaced by the defaults of DefaultOutboxDispatcherOptions.
+type OutboxDispatcherOptions struct {
+	// PollInterval is the wait time between two dispatch runs.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of messages read per dispatch run.
+	BatchSize int
+	// MaxAttempts is the number of publish attempts per message and run.
+	MaxAttempts int
+	// RetryDelay is the initial delay between two attempts, doubled after each attempt.
+	RetryDelay time.Duration
+	// MaxBackoff caps the wait time of Run after failed dispatch runs,
+	// which starts at PollInterval and doubles with each failure.
+	MaxBackoff time.Duration
+}
+
+// DefaultOutboxDispatcherOptions returns the default OutboxDispatcherOptions.
+func DefaultOutboxDispatcherOptions() OutboxDispatcherOptions {
+	return OutboxDispatcherOptions{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  3,
+		RetryDelay:   100 * time.Millisecond,
+		MaxBackoff:   time.Minute,
+	}
+}
+
+//counterfeiter:generate -o mocks/outbox-dispatcher.go --fake-name OutboxDispatcher . OutboxDispatcher
+
+// OutboxDispatcher delivers committed outbox messages to an OutboxPublisher.
+// Delivery is at-least-once: a message is deleted after the publisher acknowledged it,
+// so a crash between publish and delete results in a redelivery.
+// Messages with the same partition key are published in enqueue order. If a message
+// can not be published, all later messages with the same key wait for the next run.
+type OutboxDispatcher interface {
+	// Run dispatches pending messages every PollInterval until the context is canceled.
+	// Failed dispatch runs, including messages that could not be published, are logged
+	// and retried with backoff up to MaxBackoff.
+	// It can be used as run.Func.
+	Run(ctx context.Context) error
+	// Dispatch publishes all currently pending messages once. Messages that can not be
+	// published stay pending and Dispatch returns an error after all other messages.
+	Dispatch(ctx context.Context) error
+}
+
+// NewOutboxDispatcher returns an OutboxDispatcher for messages written by NewOutboxTx to the given bucket.
+func NewOutboxDispatcher(
+	db DB,
+	bucketName BucketName,
+	publisher OutboxPublisher,
+	options OutboxDispatcherOptions,
+) OutboxDispatcher {
+	defaults := DefaultOutboxDispatcherOptions()
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaults.BatchSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.RetryDelay < 0 {
+		options.RetryDelay = defaults.RetryDelay
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	return &outboxDispatcher{
+		db:         db,
+		bucketName: bucketName,
+		publisher:  publisher,
+		options:    options,
+	}
+}
+
+type outboxDispatcher struct {
+	db         DB
+	bucketName BucketName
+	publisher  OutboxPublisher
+	options    OutboxDispatcherOptions
+}
+
+type outboxEntry struct {
+	key     []byte
+	message OutboxMessage
+}
+
+func (o *outboxDispatcher) Run(ctx context.Context) error {
+	backoff := o.options.PollInterval
+	for {
+		wait := o.options.PollInterval
+		if err := o.Dispatch(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			glog.Warningf(
+				"dispatch outbox %s failed, retry in %v: %v",
+				o.bucketName,
+				backoff,
+				err,
+			)
+			wait = backoff
+			backoff = min(backoff*2, max(o.options.MaxBackoff, o.options.PollInterval))
+		} else {
+			backoff = o.options.PollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (o *outboxDispatcher) Dispatch(ctx context.Context) error {
+	failedPartitions := map[string]struct{}{}
+	var after []byte
+	for {
+		entries, err := o.readPending(ctx, after)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "read pending failed")
+		}
+		if len(entries) == 0 {
+			break
+		}
+		acked := o.publishAll(ctx, entries, failedPartitions)
+		if err := o.delete(ctx, acked); err != nil {
+			return errors.Wrapf(ctx, err, "delete acked messages failed")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(entries) < o.options.BatchSize {
+			break
+		}
+		// the next page starts behind this one, so messages of failed partitions
+		// do not hide the messages of other partitions
+		after = entries[len(entries)-1].key
+	}
+	if len(failedPartitions) > 0 {
+		return errors.Errorf(ctx, "publish messages of %d keys failed", len(failedPartitions))
+	}
+	return nil
+}
+
+// publishAll publishes the entries in order and returns the keys of all acknowledged
+// messages. Entries of failedPartitions are skipped, partitions of messages that
+// could not be published are added to it.
+func (o *outboxDispatcher) publishAll(
+	ctx context.Context,
+	entries []outboxEntry,
+	failedPartitions map[string]struct{},
+) [][]byte {
+	var acked [][]byte
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return acked
+		}
+		partition := string(entry.message.Key)
+		if _, ok := failedPartitions[partition]; ok {
+			continue
+		}
+		if err := o.publish(ctx, entry.message); err != nil {
+			glog.Warningf(
+				"publish outbox message %x with key %s failed: %v",
+				entry.key,
+				partition,
+				err,
+			)
+			failedPartitions[partition] = struct{}{}
+			continue
+		}
+		acked = append(acked, entry.key)
+	}
+	return acked
+}
+
+func (o *outboxDispatcher) publish(ctx context.Context, message OutboxMessage) error {
+	delay := o.options.RetryDelay
+	var err error
+	for attempt := 1; attempt <= o.options.MaxAttempts; attempt++ {
+		if err = o.publisher.Publish(ctx, message); err == nil {
+			return nil
+		}
+		if attempt == o.options.MaxAttempts {
+			break
+		}
+		glog.V(3).Infof("publish attempt %d failed: %v", attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return errors.Wrapf(ctx, err, "publish failed after %d attempts", o.options.MaxAttempts)
+}
+
+// readPending reads up to BatchSize messages with keys after the given key.
+func (o *outboxDispatcher) readPending(ctx context.Context, after []byte) ([]outboxEntry, error) {
+	var entries []outboxEntry
+	err := o.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, o.bucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		it := bucket.Iterator()
+		defer it.Close()
+		for diffSeek(it, after); it.Valid() && len(entries) < o.options.BatchSize; it.Next() {
+			item := it.Item()
+			entry := outboxEntry{
+				key: append([]byte{}, item.Key()...),
+			}
+			err := item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry.message)
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "unmarshal message %x failed", entry.key)
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "view failed")
+	}
+	return entries, nil
+}
+
+func (o *outboxDispatcher) delete(ctx context.Context, keys [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	err := o.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, o.bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(ctx, key); err != nil {
+				return errors.Wrapf(ctx, err, "delete message %x failed", key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "update failed")
+	}
+	glog.V(3).Infof("deleted %d published outbox messages", len(keys))
+	return nil
+}
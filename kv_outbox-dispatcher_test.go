@@ -0,0 +1,219 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("OutboxDispatcher", func() {
+	var ctx context.Context
+	var err error
+	var db *memoryDB
+	var bucketName kv.BucketName
+	var publisher *mocks.OutboxPublisher
+	var memoryPublisher *kv.OutboxMemoryPublisher
+	var dispatcher kv.OutboxDispatcher
+	var options kv.OutboxDispatcherOptions
+	enqueue := func(key string, value string) {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			return kv.NewOutboxTx(bucketName).Enqueue(ctx, tx, kv.OutboxMessage{
+				Topic: "topic",
+				Key:   []byte(key),
+				Value: []byte(value),
+			})
+		})).To(Succeed())
+	}
+	pending := func() []string {
+		var result []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return kv.ForEach(ctx, bucket, func(item kv.Item) error {
+				var message kv.OutboxMessage
+				Expect(item.Value(func(val []byte) error {
+					return json.Unmarshal(val, &message)
+				})).To(Succeed())
+				result = append(result, string(message.Value))
+				return nil
+			})
+		})).To(Succeed())
+		return result
+	}
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = newMemoryDB()
+		bucketName = kv.NewBucketName("outbox")
+		memoryPublisher = kv.NewOutboxMemoryPublisher()
+		publisher = &mocks.OutboxPublisher{}
+		publisher.PublishStub = memoryPublisher.Publish
+		options = kv.OutboxDispatcherOptions{
+			BatchSize:   2,
+			MaxAttempts: 3,
+			RetryDelay:  time.Millisecond,
+		}
+	})
+	JustBeforeEach(func() {
+		dispatcher = kv.NewOutboxDispatcher(db, bucketName, publisher, options)
+		err = dispatcher.Dispatch(ctx)
+	})
+	Context("without bucket", func() {
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("publishes nothing", func() {
+			Expect(publisher.PublishCallCount()).To(Equal(0))
+		})
+	})
+	Context("with pending messages", func() {
+		BeforeEach(func() {
+			enqueue("k1", "a")
+			enqueue("k2", "b")
+			enqueue("k1", "c")
+			enqueue("k2", "d")
+			enqueue("k1", "e")
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("publishes all messages in order", func() {
+			Expect(memoryPublisher.Messages()).To(HaveLen(5))
+			var values []string
+			for _, message := range memoryPublisher.Messages() {
+				values = append(values, string(message.Value))
+				Expect(message.Topic).To(Equal("topic"))
+			}
+			Expect(values).To(Equal([]string{"a", "b", "c", "d", "e"}))
+		})
+		It("deletes published messages", func() {
+			Expect(pending()).To(BeEmpty())
+		})
+	})
+	Context("publish fails temporarily", func() {
+		BeforeEach(func() {
+			enqueue("k1", "a")
+			publisher.PublishStub = func(ctx context.Context, message kv.OutboxMessage) error {
+				if publisher.PublishCallCount() == 1 {
+					return errors.New("banana")
+				}
+				return memoryPublisher.Publish(ctx, message)
+			}
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("retries", func() {
+			Expect(publisher.PublishCallCount()).To(Equal(2))
+			Expect(memoryPublisher.Messages()).To(HaveLen(1))
+		})
+		It("deletes the message", func() {
+			Expect(pending()).To(BeEmpty())
+		})
+	})
+	Context("publish fails for one partition", func() {
+		BeforeEach(func() {
+			enqueue("k1", "a")
+			enqueue("k2", "b")
+			enqueue("k1", "c")
+			publisher.PublishStub = func(ctx context.Context, message kv.OutboxMessage) error {
+				if string(message.Value) == "a" {
+					return errors.New("banana")
+				}
+				return memoryPublisher.Publish(ctx, message)
+			}
+		})
+		It("returns an error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("retries up to max attempts", func() {
+			Expect(publisher.PublishCallCount()).To(Equal(4))
+		})
+		It("publishes other partitions", func() {
+			Expect(memoryPublisher.Messages()).To(HaveLen(1))
+			Expect(memoryPublisher.Messages()[0].Value).To(Equal([]byte("b")))
+		})
+		It("keeps blocked partition pending in order", func() {
+			Expect(pending()).To(Equal([]string{"a", "c"}))
+		})
+	})
+	Context("publish fails for a partition filling the batch", func() {
+		BeforeEach(func() {
+			enqueue("k1", "a")
+			enqueue("k1", "b")
+			enqueue("k2", "c")
+			publisher.PublishStub = func(ctx context.Context, message kv.OutboxMessage) error {
+				if string(message.Key) == "k1" {
+					return errors.New("banana")
+				}
+				return memoryPublisher.Publish(ctx, message)
+			}
+		})
+		It("returns an error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("publishes messages of other partitions behind the batch", func() {
+			Expect(memoryPublisher.Messages()).To(HaveLen(1))
+			Expect(memoryPublisher.Messages()[0].Value).To(Equal([]byte("c")))
+			Expect(pending()).To(Equal([]string{"a", "b"}))
+		})
+	})
+	Context("Run", func() {
+		BeforeEach(func() {
+			enqueue("k1", "a")
+			options.PollInterval = time.Millisecond
+		})
+		It("dispatches until canceled", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() {
+				done <- dispatcher.Run(ctx)
+			}()
+			enqueue("k1", "b")
+			Eventually(memoryPublisher.Messages).Should(HaveLen(2))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+		It("retries failed dispatch runs", func() {
+			var healthy atomic.Bool
+			failing := &mocks.DB{}
+			failing.ViewStub = func(
+				ctx context.Context,
+				fn func(ctx context.Context, tx kv.Tx) error,
+			) error {
+				if !healthy.Load() {
+					return errors.New("banana")
+				}
+				return db.View(ctx, fn)
+			}
+			failing.UpdateStub = db.Update
+			options.MaxBackoff = 4 * time.Millisecond
+			dispatcher = kv.NewOutboxDispatcher(failing, bucketName, publisher, options)
+			ctx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() {
+				done <- dispatcher.Run(ctx)
+			}()
+			enqueue("k1", "b")
+			Eventually(failing.ViewCallCount).Should(BeNumerically(">=", 3))
+			Consistently(done).ShouldNot(Receive())
+			healthy.Store(true)
+			Eventually(memoryPublisher.Messages).Should(HaveLen(2))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"sync"
+)
+
+// OutboxMessage is a message written to the outbox inside a business transaction
+// and delivered by the OutboxDispatcher after the transaction committed.
+type OutboxMessage struct {
+	// Topic the message should be published to.
+	Topic string `json:"topic,omitempty"`
+	// Key is the partition key. Messages with the same key are delivered in enqueue order.
+	Key []byte `json:"key,omitempty"`
+	// Value is the message payload.
+	Value []byte `json:"value,omitempty"`
+	// Headers are optional message headers.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+//counterfeiter:generate -o mocks/outbox-publisher.go --fake-name OutboxPublisher . OutboxPublisher
+
+// OutboxPublisher delivers outbox messages to the downstream system (e.g. Kafka).
+// A message counts as acknowledged once Publish returned nil.
+type OutboxPublisher interface {
+	Publish(ctx context.Context, message OutboxMessage) error
+}
+
+// OutboxPublisherFunc is a function type that implements the OutboxPublisher interface.
+type OutboxPublisherFunc func(ctx context.Context, message OutboxMessage) error
+
+// Publish implements the OutboxPublisher interface for OutboxPublisherFunc.
+func (o OutboxPublisherFunc) Publish(ctx context.Context, message OutboxMessage) error {
+	return o(ctx, message)
+}
+
+// NewOutboxMemoryPublisher returns an OutboxPublisher that collects all published messages in memory.
+// It is intended for tests.
+func NewOutboxMemoryPublisher() *OutboxMemoryPublisher {
+	return &OutboxMemoryPublisher{}
+}
+
+// OutboxMemoryPublisher collects published messages in memory.
+type OutboxMemoryPublisher struct {
+	mux      sync.Mutex
+	messages []OutboxMessage
+}
+
+// Publish stores the message.
+func (o *OutboxMemoryPublisher) Publish(ctx context.Context, message OutboxMessage) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// Messages returns a copy of all published messages in publish order.
+func (o *OutboxMemoryPublisher) Messages() []OutboxMessage {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	result := make([]OutboxMessage, len(o.messages))
+	copy(result, o.messages)
+	return result
+}
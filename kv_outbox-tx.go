@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/bborbe/errors"
+)
+
+// outboxSequenceKey holds the last used sequence in the bucket of OutboxMetaBucketName.
+var outboxSequenceKey = []byte("sequence")
+
+// OutboxMetaBucketName returns the name of the bucket storing the last sequence of an outbox.
+func OutboxMetaBucketName(bucketName BucketName) BucketName {
+	return BucketFromStrings(bucketName.String(), "meta")
+}
+
+//counterfeiter:generate -o mocks/outbox-tx.go --fake-name OutboxTx . OutboxTx
+
+// OutboxTx writes messages into the outbox bucket as part of an existing write transaction.
+// The message becomes visible to the OutboxDispatcher only if the transaction commits.
+type OutboxTx interface {
+	Enqueue(ctx context.Context, tx Tx, message OutboxMessage) error
+}
+
+// NewOutboxTx returns an OutboxTx storing messages in the given bucket.
+// The last sequence is kept in the bucket of OutboxMetaBucketName, so sequences
+// are never reused once the dispatcher deleted all messages.
+func NewOutboxTx(bucketName BucketName) OutboxTx {
+	return &outboxTx{
+		bucketName: bucketName,
+	}
+}
+
+type outboxTx struct {
+	bucketName BucketName
+}
+
+func (o *outboxTx) Enqueue(ctx context.Context, tx Tx, message OutboxMessage) error {
+	bucket, err := tx.CreateBucketIfNotExists(ctx, o.bucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	metaBucket, err := tx.CreateBucketIfNotExists(ctx, OutboxMetaBucketName(o.bucketName))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get meta bucket failed")
+	}
+	sequence, err := storedOutboxSequence(ctx, metaBucket)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get stored sequence failed")
+	}
+	// outboxes written before the meta bucket existed continue after their last message
+	last, err := lastOutboxSequence(ctx, bucket)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get last sequence failed")
+	}
+	sequence = max(sequence, last) + 1
+	value, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	if err := bucket.Put(ctx, outboxKey(sequence), value); err != nil {
+		return errors.Wrapf(ctx, err, "put message failed")
+	}
+	if err := metaBucket.Put(ctx, outboxSequenceKey, outboxKey(sequence)); err != nil {
+		return errors.Wrapf(ctx, err, "put sequence failed")
+	}
+	return nil
+}
+
+// storedOutboxSequence returns the sequence stored in the meta bucket or 0 if none is stored.
+func storedOutboxSequence(ctx context.Context, metaBucket Bucket) (uint64, error) {
+	item, err := metaBucket.Get(ctx, outboxSequenceKey)
+	if err != nil {
+		return 0, err
+	}
+	if !item.Exists() {
+		return 0, nil
+	}
+	var sequence uint64
+	err = item.Value(func(val []byte) error {
+		var parseErr error
+		sequence, parseErr = parseOutboxKey(ctx, val)
+		return parseErr
+	})
+	return sequence, err
+}
+
+// lastOutboxSequence returns the highest sequence in the bucket or 0 if it is empty.
+func lastOutboxSequence(ctx context.Context, bucket Bucket) (uint64, error) {
+	it := bucket.IteratorReverse()
+	defer it.Close()
+	it.Rewind()
+	if !it.Valid() {
+		return 0, nil
+	}
+	return parseOutboxKey(ctx, it.Item().Key())
+}
+
+// outboxKey encodes the sequence big endian so the bucket iterates in enqueue order.
+func outboxKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
+
+func parseOutboxKey(ctx context.Context, key []byte) (uint64, error) {
+	if len(key) != 8 {
+		return 0, errors.Errorf(ctx, "invalid outbox key length %d", len(key))
+	}
+	return binary.BigEndian.Uint64(key), nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("OutboxTx", func() {
+	var ctx context.Context
+	var db *memoryDB
+	var bucketName kv.BucketName
+	var outboxTx kv.OutboxTx
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = newMemoryDB()
+		bucketName = kv.NewBucketName("outbox")
+		outboxTx = kv.NewOutboxTx(bucketName)
+	})
+	countMessages := func() int64 {
+		var count int64
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			count, err = kv.Count(ctx, bucket)
+			return err
+		})).To(Succeed())
+		return count
+	}
+	It("stores messages on commit", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			for _, value := range []string{"a", "b", "c"} {
+				message := kv.OutboxMessage{Value: []byte(value)}
+				if err := outboxTx.Enqueue(ctx, tx, message); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+		Expect(countMessages()).To(Equal(int64(3)))
+	})
+	It("stores nothing on rollback", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := tx.CreateBucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			return nil
+		})).To(Succeed())
+		err := db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			Expect(outboxTx.Enqueue(ctx, tx, kv.OutboxMessage{Value: []byte("a")})).To(Succeed())
+			return errors.New("banana")
+		})
+		Expect(err).NotTo(BeNil())
+		Expect(countMessages()).To(Equal(int64(0)))
+	})
+	It("continues the sequence of pending messages", func() {
+		for _, value := range []string{"a", "b"} {
+			Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return outboxTx.Enqueue(ctx, tx, kv.OutboxMessage{Value: []byte(value)})
+			})).To(Succeed())
+		}
+		publisher := kv.NewOutboxMemoryPublisher()
+		dispatcher := kv.NewOutboxDispatcher(
+			db,
+			bucketName,
+			publisher,
+			kv.OutboxDispatcherOptions{},
+		)
+		Expect(dispatcher.Dispatch(ctx)).To(Succeed())
+		Expect(publisher.Messages()).To(HaveLen(2))
+		Expect(publisher.Messages()[0].Value).To(Equal([]byte("a")))
+		Expect(publisher.Messages()[1].Value).To(Equal([]byte("b")))
+	})
+	It("does not reuse sequences after all messages are dispatched", func() {
+		enqueue := func(value string) {
+			Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+				return outboxTx.Enqueue(ctx, tx, kv.OutboxMessage{Value: []byte(value)})
+			})).To(Succeed())
+		}
+		enqueue("a")
+		dispatcher := kv.NewOutboxDispatcher(
+			db,
+			bucketName,
+			kv.NewOutboxMemoryPublisher(),
+			kv.OutboxDispatcherOptions{},
+		)
+		Expect(dispatcher.Dispatch(ctx)).To(Succeed())
+		Expect(countMessages()).To(Equal(int64(0)))
+		enqueue("b")
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			Expect(collectKeys(bucket.Iterator(), nil)).To(Equal([]string{
+				string([]byte{0, 0, 0, 0, 0, 0, 0, 2}),
+			}))
+			return nil
+		})).To(Succeed())
+	})
+})
@@ -0,0 +1,301 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+
+	"github.com/bborbe/errors"
+)
+
+// errReplayRollback rolls back a replayed transaction that failed in the recording.
+var errReplayRollback = stderrors.New("replay rollback")
+
+// Replay re-executes the recording of NewRecordingDB against db, one transaction
+// after the other in the recorded sequence order, and returns the number of replayed
+// transactions.
+// Each result is compared with the recording: values, keys, bucket names and errors,
+// where errors must be the same sentinel like ErrBucketNotFound or both be other errors.
+// The first difference fails with ErrReplayMismatch. Transactions that failed in the
+// recording are rolled back. Timings are not replayed.
+func Replay(ctx context.Context, reader io.Reader, db DB) (int, error) {
+	decoder := json.NewDecoder(reader)
+	count := 0
+	for {
+		operations, err := readRecordedTransaction(ctx, decoder)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			return count, errors.Wrapf(ctx, err, "read transaction %d failed", count+1)
+		}
+		if err := replayTransaction(ctx, db, operations); err != nil {
+			return count, errors.Wrapf(ctx, err, "replay tx %d failed", operations[0].Tx)
+		}
+		count++
+	}
+}
+
+// readRecordedTransaction returns all operations from begin to end of the next transaction.
+// It returns io.EOF if the recording ends before the next transaction.
+func readRecordedTransaction(
+	ctx context.Context,
+	decoder *json.Decoder,
+) ([]RecordedOperation, error) {
+	var operations []RecordedOperation
+	for {
+		var operation RecordedOperation
+		if err := decoder.Decode(&operation); err != nil {
+			if errors.Is(err, io.EOF) && len(operations) > 0 {
+				return nil, errors.Errorf(ctx, "recording ends within transaction")
+			}
+			return nil, err
+		}
+		if len(operations) > 0 && operation.Tx != operations[0].Tx {
+			return nil, errors.Errorf(
+				ctx,
+				"operation of tx %d within tx %d",
+				operation.Tx,
+				operations[0].Tx,
+			)
+		}
+		operations = append(operations, operation)
+		if operation.Operation == RecordEnd {
+			return operations, nil
+		}
+	}
+}
+
+func replayTransaction(ctx context.Context, db DB, operations []RecordedOperation) error {
+	if len(operations) < 2 {
+		return errors.Errorf(ctx, "transaction without begin and end")
+	}
+	begin := operations[0]
+	end := operations[len(operations)-1]
+	fn := func(ctx context.Context, tx Tx) error {
+		replayer := &txReplayer{
+			tx:        tx,
+			buckets:   map[string]Bucket{},
+			iterators: map[int]Iterator{},
+		}
+		defer replayer.close()
+		for _, operation := range operations[1 : len(operations)-1] {
+			if err := replayer.replay(ctx, operation); err != nil {
+				return err
+			}
+		}
+		if end.Error != "" {
+			return errReplayRollback
+		}
+		return nil
+	}
+	var err error
+	switch begin.Operation {
+	case RecordBeginUpdate:
+		err = db.Update(ctx, fn)
+	case RecordBeginView:
+		err = db.View(ctx, fn)
+	default:
+		return errors.Errorf(ctx, "transaction begins with %s", begin.Operation)
+	}
+	if errors.Is(err, ErrReplayMismatch) {
+		return err
+	}
+	if end.Error == "" && err != nil {
+		return errors.Wrapf(ctx, ErrReplayMismatch, "expected commit but got %v", err)
+	}
+	if end.Error != "" && err == nil {
+		return errors.Wrapf(
+			ctx,
+			ErrReplayMismatch,
+			"expected error %s but commit succeeded",
+			end.Error,
+		)
+	}
+	return nil
+}
+
+// txReplayer holds the buckets and iterators opened by a replayed transaction.
+type txReplayer struct {
+	tx        Tx
+	buckets   map[string]Bucket
+	iterators map[int]Iterator
+}
+
+func (t *txReplayer) close() {
+	for _, iterator := range t.iterators {
+		iterator.Close()
+	}
+}
+
+func (t *txReplayer) replay(ctx context.Context, operation RecordedOperation) error {
+	switch operation.Operation {
+	case RecordBucket:
+		return t.openBucket(ctx, operation, t.tx.Bucket)
+	case RecordCreateBucket:
+		return t.openBucket(ctx, operation, t.tx.CreateBucket)
+	case RecordCreateBucketIfNotExists:
+		return t.openBucket(ctx, operation, t.tx.CreateBucketIfNotExists)
+	case RecordDeleteBucket:
+		err := t.tx.DeleteBucket(ctx, operation.BucketName)
+		delete(t.buckets, operation.BucketName.String())
+		return compareReplay(ctx, operation, err, nil, nil)
+	case RecordListBucketNames:
+		names, err := t.tx.ListBucketNames(ctx)
+		if err == nil && !equalBucketNames(names, operation.BucketNames) {
+			return replayMismatch(ctx, operation, "bucket names %v", names)
+		}
+		return compareReplay(ctx, operation, err, nil, nil)
+	case RecordGet:
+		bucket, err := t.bucket(ctx, operation)
+		if err != nil {
+			return err
+		}
+		item, err := bucket.Get(ctx, operation.Key)
+		var value []byte
+		if err == nil {
+			value, err = itemValue(item)
+		}
+		return compareReplay(ctx, operation, err, nil, value)
+	case RecordPut:
+		bucket, err := t.bucket(ctx, operation)
+		if err != nil {
+			return err
+		}
+		err = bucket.Put(ctx, operation.Key, operation.Value)
+		return compareReplay(ctx, operation, err, nil, operation.Value)
+	case RecordDelete:
+		bucket, err := t.bucket(ctx, operation)
+		if err != nil {
+			return err
+		}
+		return compareReplay(ctx, operation, bucket.Delete(ctx, operation.Key), nil, nil)
+	case RecordIterator, RecordIteratorReverse:
+		bucket, err := t.bucket(ctx, operation)
+		if err != nil {
+			return err
+		}
+		if operation.Operation == RecordIterator {
+			t.iterators[operation.Iterator] = bucket.Iterator()
+		} else {
+			t.iterators[operation.Iterator] = bucket.IteratorReverse()
+		}
+		return nil
+	case RecordRewind, RecordSeek, RecordNext, RecordClose, RecordValue:
+		return t.move(ctx, operation)
+	default:
+		return errors.Errorf(ctx, "unknown operation %s", operation.Operation)
+	}
+}
+
+func (t *txReplayer) openBucket(
+	ctx context.Context,
+	operation RecordedOperation,
+	fn func(ctx context.Context, name BucketName) (Bucket, error),
+) error {
+	bucket, err := fn(ctx, operation.BucketName)
+	if err == nil {
+		t.buckets[operation.BucketName.String()] = bucket
+	}
+	return compareReplay(ctx, operation, err, nil, nil)
+}
+
+func (t *txReplayer) bucket(ctx context.Context, operation RecordedOperation) (Bucket, error) {
+	bucket, ok := t.buckets[operation.BucketName.String()]
+	if !ok {
+		return nil, errors.Errorf(
+			ctx,
+			"%s on bucket %s not opened",
+			operation.Operation,
+			operation.BucketName,
+		)
+	}
+	return bucket, nil
+}
+
+func (t *txReplayer) move(ctx context.Context, operation RecordedOperation) error {
+	iterator, ok := t.iterators[operation.Iterator]
+	if !ok {
+		return errors.Errorf(
+			ctx,
+			"%s on iterator %d not opened",
+			operation.Operation,
+			operation.Iterator,
+		)
+	}
+	switch operation.Operation {
+	case RecordRewind:
+		iterator.Rewind()
+	case RecordSeek:
+		iterator.Seek(operation.Key)
+	case RecordNext:
+		iterator.Next()
+	case RecordClose:
+		iterator.Close()
+		delete(t.iterators, operation.Iterator)
+		return nil
+	case RecordValue:
+		if !iterator.Valid() {
+			return replayMismatch(ctx, operation, "invalid iterator")
+		}
+		item := iterator.Item()
+		value, err := itemValue(item)
+		return compareReplay(ctx, operation, err, item.Key(), value)
+	}
+	return compareReplay(ctx, operation, nil, currentIteratorKey(iterator), nil)
+}
+
+// compareReplay compares the error, the result key and the value with the recording.
+func compareReplay(
+	ctx context.Context,
+	operation RecordedOperation,
+	err error,
+	result []byte,
+	value []byte,
+) error {
+	if !sameRecordError(operation.Error, err) {
+		return replayMismatch(ctx, operation, "error %v", err)
+	}
+	if err != nil {
+		return nil
+	}
+	if !bytes.Equal(operation.Result, result) {
+		return replayMismatch(ctx, operation, "key %q", result)
+	}
+	if !bytes.Equal(operation.Value, value) {
+		return replayMismatch(ctx, operation, "value %q", value)
+	}
+	return nil
+}
+
+func replayMismatch(
+	ctx context.Context,
+	operation RecordedOperation,
+	format string,
+	args ...any,
+) error {
+	return errors.Wrapf(
+		ctx,
+		ErrReplayMismatch,
+		"%s of bucket %s key %q got "+format,
+		append([]any{operation.Operation, operation.BucketName, operation.Key}, args...)...,
+	)
+}
+
+func equalBucketNames(a BucketNames, b BucketNames) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
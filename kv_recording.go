@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrReplayMismatch is returned by Replay if a result differs from the recording.
+var ErrReplayMismatch = stderrors.New("replay result differs from recording")
+
+// RecordOperation is the kind of a RecordedOperation.
+type RecordOperation string
+
+const (
+	// RecordBeginUpdate starts a write transaction.
+	RecordBeginUpdate RecordOperation = "begin_update"
+	// RecordBeginView starts a read transaction.
+	RecordBeginView RecordOperation = "begin_view"
+	// RecordEnd ends a transaction, Error is set if it did not commit.
+	RecordEnd RecordOperation = "end"
+	// RecordBucket is Tx.Bucket.
+	RecordBucket RecordOperation = "bucket"
+	// RecordCreateBucket is Tx.CreateBucket.
+	RecordCreateBucket RecordOperation = "create_bucket"
+	// RecordCreateBucketIfNotExists is Tx.CreateBucketIfNotExists.
+	RecordCreateBucketIfNotExists RecordOperation = "create_bucket_if_not_exists"
+	// RecordDeleteBucket is Tx.DeleteBucket.
+	RecordDeleteBucket RecordOperation = "delete_bucket"
+	// RecordListBucketNames is Tx.ListBucketNames with the returned BucketNames.
+	RecordListBucketNames RecordOperation = "list_bucket_names"
+	// RecordGet is Bucket.Get with the returned Value.
+	RecordGet RecordOperation = "get"
+	// RecordPut is Bucket.Put with the written Value.
+	RecordPut RecordOperation = "put"
+	// RecordDelete is Bucket.Delete.
+	RecordDelete RecordOperation = "delete"
+	// RecordIterator opens the Iterator with the given id.
+	RecordIterator RecordOperation = "iterator"
+	// RecordIteratorReverse opens the reverse Iterator with the given id.
+	RecordIteratorReverse RecordOperation = "iterator_reverse"
+	// RecordRewind is Iterator.Rewind with the key of the current item as Result.
+	RecordRewind RecordOperation = "rewind"
+	// RecordSeek is Iterator.Seek of Key with the key of the current item as Result.
+	RecordSeek RecordOperation = "seek"
+	// RecordNext is Iterator.Next with the key of the current item as Result.
+	RecordNext RecordOperation = "next"
+	// RecordClose is Iterator.Close.
+	RecordClose RecordOperation = "close"
+	// RecordValue is Item.Value of the current item of an iterator with its key as Result.
+	RecordValue RecordOperation = "value"
+)
+
+// RecordedOperation is a single line of a recording written by NewRecordingDB.
+// All operations of a transaction are written together, between its begin and
+// end operation.
+type RecordedOperation struct {
+	// Tx is the id of the transaction.
+	Tx        uint64          `json:"tx"`
+	Operation RecordOperation `json:"op"`
+	// Start is the start time in unix nanoseconds.
+	Start    int64         `json:"t"`
+	Duration time.Duration `json:"d,omitempty"`
+	// BucketName of bucket operations.
+	BucketName BucketName `json:"b,omitempty"`
+	// Iterator is the id of the iterator within the transaction.
+	Iterator int `json:"i,omitempty"`
+	// Key of get, put, delete and seek.
+	Key []byte `json:"k,omitempty"`
+	// Value written by put, read by get, or read from the current item of an iterator.
+	Value []byte `json:"v,omitempty"`
+	// Result is the key of the current item of an iterator, nil if it is not valid.
+	Result []byte `json:"r,omitempty"`
+	// BucketNames returned by list_bucket_names.
+	BucketNames BucketNames `json:"n,omitempty"`
+	// Error of the operation, the name of the sentinel or the message of other errors.
+	Error string `json:"e,omitempty"`
+}
+
+// recordSentinels are compared by Replay, all other errors only need to exist.
+var recordSentinels = map[string]error{
+	"bucket_not_found":         ErrBucketNotFound,
+	"bucket_already_exists":    ErrBucketAlreadyExists,
+	"key_not_found":            ErrKeyNotFound,
+	"transaction_already_open": ErrTransactionAlreadyOpen,
+}
+
+// recordError returns the recorded form of err.
+func recordError(err error) string {
+	if err == nil {
+		return ""
+	}
+	for name, sentinel := range recordSentinels {
+		if errors.Is(err, sentinel) {
+			return name
+		}
+	}
+	if err.Error() == "" {
+		return "error"
+	}
+	return err.Error()
+}
+
+// sameRecordError reports whether err matches the recorded error.
+func sameRecordError(recorded string, err error) bool {
+	if recorded == "" || err == nil {
+		return recorded == "" && err == nil
+	}
+	if _, ok := recordSentinels[recorded]; ok {
+		return recordError(err) == recorded
+	}
+	_, sentinel := recordSentinels[recordError(err)]
+	return !sentinel
+}
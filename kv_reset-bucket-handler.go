@@ -10,12 +10,14 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/bborbe/errors"
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 )
 
 // NewResetBucketHandler returns a http.Handler
-// that allow delete a bucket
+// that allow delete a bucket.
+// It responds with 403 for read-only databases.
 func NewResetBucketHandler(db DB, cancel context.CancelFunc) http.Handler {
 	var lock sync.Mutex
 	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
@@ -26,6 +28,10 @@ func NewResetBucketHandler(db DB, cancel context.CancelFunc) http.Handler {
 			http.Error(resp, "parameter bucket missing", http.StatusBadRequest)
 			return
 		}
+		if IsReadOnly(db) {
+			http.Error(resp, "reset bucket not allowed, db is read only", http.StatusForbidden)
+			return
+		}
 		if !lock.TryLock() {
 			glog.V(2).Infof("reset bucket %s running", bucketName)
 			http.Error(
@@ -41,6 +47,10 @@ func NewResetBucketHandler(db DB, cancel context.CancelFunc) http.Handler {
 		err := db.Update(ctx, func(ctx context.Context, tx Tx) error {
 			return tx.DeleteBucket(ctx, bucketName)
 		})
+		if errors.Is(err, ErrReadOnly) {
+			http.Error(resp, fmt.Sprintf("remove bucket failed: %v", err), http.StatusForbidden)
+			return
+		}
 		if err != nil {
 			http.Error(
 				resp,
@@ -10,14 +10,22 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/bborbe/errors"
 	"github.com/golang/glog"
 )
 
 // NewResetHandler returns a http.Handler
-// that allow delete the complete database
+// that allow delete the complete database.
+// It responds with 403 without closing the database or calling cancel
+// for read-only databases, see IsReadOnly. If Remove fails with ErrReadOnly,
+// it also responds with 403, but calls cancel because the database is closed.
 func NewResetHandler(db DB, cancel context.CancelFunc) http.Handler {
 	var lock sync.Mutex
 	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if IsReadOnly(db) {
+			http.Error(resp, "reset db not allowed, db is read only", http.StatusForbidden)
+			return
+		}
 		if !lock.TryLock() {
 			glog.V(2).Infof("reset db already running")
 			http.Error(
@@ -30,8 +38,8 @@ func NewResetHandler(db DB, cancel context.CancelFunc) http.Handler {
 		defer lock.Unlock()
 		glog.V(2).Infof("reset db started")
 
-		defer cancel()
 		if err := db.Close(); err != nil {
+			cancel()
 			http.Error(
 				resp,
 				fmt.Sprintf("reset db failed: %v", err),
@@ -40,6 +48,12 @@ func NewResetHandler(db DB, cancel context.CancelFunc) http.Handler {
 			return
 		}
 		if err := db.Remove(); err != nil {
+			// the database is already closed, so the service must restart in any case
+			cancel()
+			if errors.Is(err, ErrReadOnly) {
+				http.Error(resp, fmt.Sprintf("remove db failed: %v", err), http.StatusForbidden)
+				return
+			}
 			http.Error(
 				resp,
 				fmt.Sprintf("remove db failed: %v", err),
@@ -47,6 +61,7 @@ func NewResetHandler(db DB, cancel context.CancelFunc) http.Handler {
 			)
 			return
 		}
+		cancel()
 		resp.WriteHeader(http.StatusOK)
 		_, _ = fmt.Fprintln(resp, "reset db successful")
 		glog.V(2).Infof("reset db successful")
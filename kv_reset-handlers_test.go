@@ -246,5 +246,67 @@ var _ = Describe("Reset Handlers", func() {
 				Expect(db.UpdateCallCount()).To(Equal(1))
 			})
 		})
+
+		Context("when db is read only", func() {
+			BeforeEach(func() {
+				handler = kv.NewResetBucketHandler(kv.NewReadOnlyDB(db), cancel)
+				request = httptest.NewRequest("DELETE", "/bucket/test-bucket", nil)
+				request = mux.SetURLVars(request, map[string]string{"BucketName": "test-bucket"})
+			})
+
+			It("returns forbidden", func() {
+				handler.ServeHTTP(recorder, request)
+
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(db.UpdateCallCount()).To(Equal(0))
+				Expect(cancelCalled).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("read only", func() {
+		It("NewResetHandler returns forbidden", func() {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/reset", nil)
+			kv.NewResetHandler(kv.NewReadOnlyDB(db), cancel).ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(db.CloseCallCount()).To(Equal(0))
+			Expect(db.RemoveCallCount()).To(Equal(0))
+			Expect(cancelCalled).To(BeFalse())
+		})
+		It("NewResetHandler detects read only below other wrappers", func() {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/reset", nil)
+			wrapped, err := kv.NewNamespacedDB(
+				kv.NewDBWithSavepoints(kv.NewReadOnlyDB(db)),
+				kv.Namespace("tenant"),
+			)
+			Expect(err).To(BeNil())
+			kv.NewResetHandler(wrapped, cancel).ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(db.CloseCallCount()).To(Equal(0))
+			Expect(cancelCalled).To(BeFalse())
+		})
+		It("NewResetHandler does not remove the base of overlays", func() {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/reset", nil)
+			kv.NewResetHandler(kv.NewOverlayDB(db), cancel).ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(db.RemoveCallCount()).To(Equal(0))
+			Expect(cancelCalled).To(BeTrue())
+		})
+		It("NewResetHandler cancels the closed db if remove fails with ErrReadOnly", func() {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest("POST", "/reset", nil)
+			db.RemoveReturns(kv.ErrReadOnly)
+			kv.NewResetHandler(db, cancel).ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(db.CloseCallCount()).To(Equal(1))
+			Expect(cancelCalled).To(BeTrue())
+		})
 	})
 })
@@ -0,0 +1,349 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// RetentionPolicy limits the content of a bucket. Keys must be ordered oldest first,
+// because MaxKeys and MaxBytes trim the bucket from its first key on.
+type RetentionPolicy struct {
+	BucketName BucketName
+	// MaxAge deletes all entries older than MaxAge, it requires Timestamp.
+	MaxAge time.Duration
+	// Timestamp returns the time of an entry, for example stored in its key or value.
+	Timestamp func(key []byte, value []byte) (time.Time, error)
+	// UnorderedTimestamps scans the whole bucket for MaxAge. Otherwise the
+	// timestamps are expected to ascend with the keys and the scan stops at the first young entry.
+	UnorderedTimestamps bool
+	// MaxKeys is the maximum number of keys.
+	MaxKeys int64
+	// MaxBytes is the maximum size of all keys and values.
+	MaxBytes int64
+}
+
+// RetentionTimestampFromUnixNanoKey reads the timestamp from keys starting
+// with the unix nanoseconds in big endian, e.g. binary.BigEndian.AppendUint64(nil,
+// uint64(t.UnixNano())).
+func RetentionTimestampFromUnixNanoKey(key []byte, value []byte) (time.Time, error) {
+	if len(key) < 8 {
+		return time.Time{}, errors.Errorf(context.Background(), "key %q too short", key)
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key))), nil
+}
+
+// RetentionOptions configures NewRetentionManager.
+// Unset values are replaced by the defaults of DefaultRetentionOptions.
+type RetentionOptions struct {
+	// Interval between two trims of Run.
+	Interval time.Duration
+	// ChunkSize is the maximum number of keys deleted per transaction.
+	ChunkSize int
+}
+
+// DefaultRetentionOptions returns the default RetentionOptions.
+func DefaultRetentionOptions() RetentionOptions {
+	return RetentionOptions{
+		Interval:  time.Hour,
+		ChunkSize: 1000,
+	}
+}
+
+// RetentionResult describes the trim of a single bucket.
+// Retained is only counted for policies with MaxKeys or MaxBytes,
+// RetainedSize only for policies with MaxBytes.
+type RetentionResult struct {
+	BucketName   BucketName
+	Retained     int64
+	Purged       int64
+	PurgedBytes  int64
+	RetainedSize int64
+}
+
+//counterfeiter:generate -o mocks/retention-manager.go --fake-name RetentionManager . RetentionManager
+
+// RetentionManager enforces RetentionPolicies by deleting the oldest entries.
+type RetentionManager interface {
+	// Trim applies all policies once.
+	Trim(ctx context.Context) ([]RetentionResult, error)
+	// Run trims every Interval until the context is canceled. It can be used as run.Func.
+	Run(ctx context.Context) error
+}
+
+// NewRetentionManager returns a RetentionManager for the given policies.
+func NewRetentionManager(
+	db DB,
+	options RetentionOptions,
+	metrics RetentionMetrics,
+	policies ...RetentionPolicy,
+) RetentionManager {
+	defaults := DefaultRetentionOptions()
+	if options.Interval <= 0 {
+		options.Interval = defaults.Interval
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = defaults.ChunkSize
+	}
+	return &retentionManager{
+		db:       db,
+		options:  options,
+		metrics:  metrics,
+		policies: policies,
+	}
+}
+
+type retentionManager struct {
+	db       DB
+	options  RetentionOptions
+	metrics  RetentionMetrics
+	policies []RetentionPolicy
+}
+
+func (r *retentionManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Trim(ctx); err != nil {
+			glog.Warningf("trim buckets failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *retentionManager) Trim(ctx context.Context) ([]RetentionResult, error) {
+	results := make([]RetentionResult, 0, len(r.policies))
+	for _, policy := range r.policies {
+		result, err := r.trim(ctx, policy)
+		if err != nil {
+			return results, errors.Wrapf(ctx, err, "trim bucket %s failed", policy.BucketName)
+		}
+		if result.Purged > 0 {
+			glog.Infof(
+				"retention deleted %d keys (%d bytes) of bucket %s, %d keys retained",
+				result.Purged,
+				result.PurgedBytes,
+				policy.BucketName,
+				result.Retained,
+			)
+		}
+		if policy.MaxKeys > 0 || policy.MaxBytes > 0 {
+			r.metrics.RetentionRetainedSet(policy.BucketName, result.Retained)
+		}
+		r.metrics.RetentionPurgedAdd(policy.BucketName, result.Purged)
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// retentionState tracks a running trim of a bucket.
+type retentionState struct {
+	policy    RetentionPolicy
+	now       time.Time
+	count     int64
+	size      int64
+	result    *RetentionResult
+	lastKey   []byte
+	completed bool
+}
+
+func (r *retentionManager) trim(
+	ctx context.Context,
+	policy RetentionPolicy,
+) (*RetentionResult, error) {
+	state := &retentionState{
+		policy: policy,
+		now:    time.Now(),
+		result: &RetentionResult{
+			BucketName: policy.BucketName,
+		},
+	}
+	if err := r.count(ctx, state); err != nil {
+		return nil, errors.Wrapf(ctx, err, "count failed")
+	}
+	for !state.completed {
+		if err := r.trimChunk(ctx, state); err != nil {
+			return nil, err
+		}
+	}
+	if policy.MaxKeys > 0 || policy.MaxBytes > 0 {
+		state.result.Retained = state.count
+	}
+	if policy.MaxBytes > 0 {
+		state.result.RetainedSize = state.size
+	}
+	return state.result, nil
+}
+
+// count counts the keys of the bucket for MaxKeys and their size for MaxBytes.
+// Values are only read for MaxBytes, a policy with only MaxAge counts nothing.
+func (r *retentionManager) count(ctx context.Context, state *retentionState) error {
+	policy := state.policy
+	if policy.MaxKeys <= 0 && policy.MaxBytes <= 0 {
+		return nil
+	}
+	return r.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, policy.BucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				state.completed = true
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		if policy.MaxBytes <= 0 {
+			state.count, err = Count(ctx, bucket)
+			return err
+		}
+		return ForEach(ctx, bucket, func(item Item) error {
+			state.count++
+			return item.Value(func(val []byte) error {
+				state.size += int64(len(item.Key()) + len(val))
+				return nil
+			})
+		})
+	})
+}
+
+// trimChunk deletes up to ChunkSize entries in one transaction.
+func (r *retentionManager) trimChunk(ctx context.Context, state *retentionState) error {
+	var chunk *retentionChunk
+	err := r.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, state.policy.BucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				chunk = &retentionChunk{completed: true}
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		chunk, err = r.chunk(ctx, bucket, state)
+		if err != nil {
+			return err
+		}
+		for _, candidate := range chunk.candidates {
+			if err := bucket.Delete(ctx, candidate.key); err != nil {
+				return errors.Wrapf(ctx, err, "delete failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// update the state after the commit, the backend might retry fn
+	for _, candidate := range chunk.candidates {
+		state.count--
+		state.size -= candidate.size
+		state.result.Purged++
+		state.result.PurgedBytes += candidate.size
+	}
+	if chunk.lastKey != nil {
+		state.lastKey = chunk.lastKey
+	}
+	state.completed = chunk.completed
+	return nil
+}
+
+type retentionChunk struct {
+	candidates []retentionCandidate
+	lastKey    []byte
+	completed  bool
+}
+
+type retentionCandidate struct {
+	key  []byte
+	size int64
+}
+
+// chunk returns up to ChunkSize entries to delete, oldest first.
+func (r *retentionManager) chunk(
+	ctx context.Context,
+	bucket Bucket,
+	state *retentionState,
+) (*retentionChunk, error) {
+	policy := state.policy
+	count := state.count
+	size := state.size
+	result := &retentionChunk{}
+	it := bucket.Iterator()
+	defer it.Close()
+	if state.lastKey == nil {
+		it.Rewind()
+	} else {
+		it.Seek(state.lastKey)
+	}
+	for ; it.Valid(); it.Next() {
+		if len(result.candidates) >= r.options.ChunkSize {
+			return result, nil
+		}
+		item := it.Item()
+		key := bytes.Clone(item.Key())
+		if state.lastKey != nil && bytes.Equal(key, state.lastKey) {
+			continue
+		}
+		var value []byte
+		if err := item.Value(func(val []byte) error {
+			value = bytes.Clone(val)
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(ctx, err, "get value failed")
+		}
+		entrySize := int64(len(key) + len(value))
+		tooMany := policy.MaxKeys > 0 && count > policy.MaxKeys
+		tooLarge := policy.MaxBytes > 0 && size > policy.MaxBytes
+		expired, err := r.expired(policy, state.now, key, value)
+		if err != nil {
+			glog.Warningf(
+				"get timestamp of key %q in bucket %s failed: %v",
+				key,
+				policy.BucketName,
+				err,
+			)
+		}
+		result.lastKey = key
+		if tooMany || tooLarge || expired {
+			result.candidates = append(result.candidates, retentionCandidate{
+				key:  key,
+				size: entrySize,
+			})
+			count--
+			size -= entrySize
+			continue
+		}
+		if policy.MaxAge <= 0 || policy.Timestamp == nil || !policy.UnorderedTimestamps {
+			result.completed = true
+			return result, nil
+		}
+	}
+	result.completed = true
+	return result, nil
+}
+
+func (r *retentionManager) expired(
+	policy RetentionPolicy,
+	now time.Time,
+	key []byte,
+	value []byte,
+) (bool, error) {
+	if policy.MaxAge <= 0 || policy.Timestamp == nil {
+		return false, nil
+	}
+	timestamp, err := policy.Timestamp(key, value)
+	if err != nil {
+		return false, err
+	}
+	return now.Sub(timestamp) > policy.MaxAge, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("RetentionManager", func() {
+	var ctx context.Context
+	var db *memoryDB
+	var metrics *mocks.RetentionMetrics
+	var bucketName kv.BucketName
+	var now time.Time
+	var results []kv.RetentionResult
+	var err error
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = newMemoryDB()
+		metrics = &mocks.RetentionMetrics{}
+		bucketName = kv.NewBucketName("events")
+		now = time.Now()
+		// ten entries, one per hour, the oldest first
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for i := 10; i > 0; i-- {
+				timestamp := now.Add(-time.Duration(i) * time.Hour)
+				key := binary.BigEndian.AppendUint64(nil, uint64(timestamp.UnixNano()))
+				if err := bucket.Put(ctx, key, []byte("0123456789")); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	})
+	count := func() int64 {
+		var result int64
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			result, err = kv.Count(ctx, bucket)
+			return err
+		})).To(Succeed())
+		return result
+	}
+	trim := func(policy kv.RetentionPolicy) {
+		policy.BucketName = bucketName
+		manager := kv.NewRetentionManager(db, kv.RetentionOptions{ChunkSize: 2}, metrics, policy)
+		results, err = manager.Trim(ctx)
+	}
+	It("trims to max keys", func() {
+		trim(kv.RetentionPolicy{MaxKeys: 3})
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Purged).To(Equal(int64(7)))
+		Expect(results[0].Retained).To(Equal(int64(3)))
+		Expect(count()).To(Equal(int64(3)))
+		Expect(metrics.RetentionPurgedAddCallCount()).To(Equal(1))
+		_, purged := metrics.RetentionPurgedAddArgsForCall(0)
+		Expect(purged).To(Equal(int64(7)))
+		_, retained := metrics.RetentionRetainedSetArgsForCall(0)
+		Expect(retained).To(Equal(int64(3)))
+	})
+	It("trims to max bytes", func() {
+		trim(kv.RetentionPolicy{MaxBytes: 5 * 18})
+		Expect(err).To(BeNil())
+		Expect(results[0].PurgedBytes).To(Equal(int64(5 * 18)))
+		Expect(count()).To(Equal(int64(5)))
+	})
+	It("trims to max age with ordered keys", func() {
+		trim(kv.RetentionPolicy{
+			MaxAge:    150 * time.Minute,
+			Timestamp: kv.RetentionTimestampFromUnixNanoKey,
+		})
+		Expect(err).To(BeNil())
+		Expect(count()).To(Equal(int64(2)))
+		Expect(results[0].Purged).To(Equal(int64(8)))
+	})
+	It("reads no values to count max keys", func() {
+		faultDB := kv.NewFaultDB(db, kv.FaultRule{Operation: kv.FaultIterate, Corrupt: true})
+		manager := kv.NewRetentionManager(
+			faultDB,
+			kv.RetentionOptions{ChunkSize: 2},
+			metrics,
+			kv.RetentionPolicy{BucketName: bucketName, MaxKeys: 3},
+		)
+		results, err = manager.Trim(ctx)
+		Expect(err).To(BeNil())
+		Expect(results[0].Purged).To(Equal(int64(7)))
+		// only the values of the purged keys and the first retained key are read
+		Expect(faultDB.Injected()).To(Equal(8))
+	})
+	It("trims to max age with unordered timestamps", func() {
+		otherBucket := kv.NewBucketName("sessions")
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, otherBucket)
+			if err != nil {
+				return err
+			}
+			for i, age := range []int{1, 5, 2, 7, 3} {
+				value := []byte(now.Add(-time.Duration(age) * time.Hour).Format(time.RFC3339Nano))
+				if err := bucket.Put(ctx, []byte(fmt.Sprintf("key%d", i)), value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+		manager := kv.NewRetentionManager(
+			db,
+			kv.RetentionOptions{ChunkSize: 1},
+			metrics,
+			kv.RetentionPolicy{
+				BucketName: otherBucket,
+				MaxAge:     150 * time.Minute,
+				Timestamp: func(key []byte, value []byte) (time.Time, error) {
+					return time.Parse(time.RFC3339Nano, string(value))
+				},
+				UnorderedTimestamps: true,
+			},
+		)
+		results, err = manager.Trim(ctx)
+		Expect(err).To(BeNil())
+		Expect(results[0].Purged).To(Equal(int64(3)))
+		Expect(results[0].Retained).To(Equal(int64(0)))
+		Expect(metrics.RetentionRetainedSetCallCount()).To(Equal(0))
+	})
+	It("ignores missing buckets", func() {
+		manager := kv.NewRetentionManager(
+			db,
+			kv.RetentionOptions{},
+			metrics,
+			kv.RetentionPolicy{BucketName: kv.NewBucketName("missing"), MaxKeys: 1},
+		)
+		results, err = manager.Trim(ctx)
+		Expect(err).To(BeNil())
+		Expect(results[0].Retained).To(Equal(int64(0)))
+	})
+})
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/retention-metrics.go --fake-name RetentionMetrics . RetentionMetrics
+
+// RetentionMetrics provides monitoring of retention policies using Prometheus.
+type RetentionMetrics interface {
+	RetentionRetainedSet(bucketName BucketName, count int64)
+	RetentionPurgedAdd(bucketName BucketName, count int64)
+}
+
+// NewRetentionMetrics creates a new RetentionMetrics instance with default Prometheus collectors.
+func NewRetentionMetrics() RetentionMetrics {
+	return &retentionMetrics{}
+}
+
+type retentionMetrics struct {
+}
+
+func (m *retentionMetrics) RetentionRetainedSet(bucketName BucketName, count int64) {
+	dbRetentionRetainedGauge.With(prometheus.Labels{
+		"bucket": bucketName.String(),
+	}).Set(float64(count))
+}
+
+func (m *retentionMetrics) RetentionPurgedAdd(bucketName BucketName, count int64) {
+	dbRetentionPurgedCounter.With(prometheus.Labels{
+		"bucket": bucketName.String(),
+	}).Add(float64(count))
+}
+
+var (
+	dbRetentionRetainedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "retention_retained",
+		Help:      "Number of keys retained in the bucket after the last trim",
+	}, []string{"bucket"})
+	dbRetentionPurgedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "retention_purged_total",
+		Help:      "Counts keys deleted by retention policies",
+	}, []string{"bucket"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbRetentionRetainedGauge,
+		dbRetentionPurgedCounter,
+	)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/retry-metrics.go --fake-name RetryMetrics . RetryMetrics
+
+// RetryMetrics provides monitoring of retried transactions using Prometheus.
+type RetryMetrics interface {
+	DbUpdateRetryInc()
+}
+
+// NewRetryMetrics creates a new RetryMetrics instance with default Prometheus counters.
+func NewRetryMetrics() RetryMetrics {
+	return &retryMetrics{}
+}
+
+type retryMetrics struct {
+}
+
+func (m *retryMetrics) DbUpdateRetryInc() {
+	dbUpdateRetryCounter.Inc()
+}
+
+var (
+	dbUpdateRetryCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kv",
+		Subsystem: "db",
+		Name:      "update_retry_total",
+		Help:      "Counts db updates retried because of a conflict",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbUpdateRetryCounter,
+	)
+}
@@ -0,0 +1,452 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/bborbe/errors"
+)
+
+// WithSavepoint runs fn in a savepoint of tx. If fn returns an error, all its
+// writes are rolled back and the error is returned, the transaction itself stays usable.
+func WithSavepoint(
+	ctx context.Context,
+	tx Tx,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	savepointTx, ok := tx.(SavepointTx)
+	if !ok {
+		return ErrSavepointsNotSupported
+	}
+	savepoint, err := savepointTx.Savepoint(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create savepoint failed")
+	}
+	if err := fn(ctx, tx); err != nil {
+		if rollbackErr := savepointTx.RollbackTo(ctx, savepoint); rollbackErr != nil {
+			return errors.Wrapf(ctx, rollbackErr, "rollback to savepoint failed")
+		}
+		if releaseErr := savepointTx.Release(ctx, savepoint); releaseErr != nil {
+			return errors.Wrapf(ctx, releaseErr, "release savepoint failed")
+		}
+		return err
+	}
+	if err := savepointTx.Release(ctx, savepoint); err != nil {
+		return errors.Wrapf(ctx, err, "release savepoint failed")
+	}
+	return nil
+}
+
+// NewSavepointTx wraps the given Tx with savepoint support.
+func NewSavepointTx(tx Tx) SavepointTx {
+	return &savepointTx{
+		tx:      tx,
+		buckets: map[string]*savepointBucket{},
+	}
+}
+
+type savepointTx struct {
+	tx      Tx
+	layers  []*savepointLayer
+	nextID  Savepoint
+	buckets map[string]*savepointBucket
+}
+
+// savepointLayer holds all bucket changes made after its savepoint.
+type savepointLayer struct {
+	id      Savepoint
+	buckets map[string]*savepointBucketChange
+}
+
+// savepointBucketChange describes the changes of a bucket within one layer.
+type savepointBucketChange struct {
+	// deleted is true if the bucket was deleted, this hides all content of lower layers.
+	deleted bool
+	// exists is true if the bucket was created or written in the layer.
+	exists bool
+	writes *writeBuffer
+}
+
+func newSavepointLayer(id Savepoint) *savepointLayer {
+	return &savepointLayer{
+		id:      id,
+		buckets: map[string]*savepointBucketChange{},
+	}
+}
+
+func (s *savepointLayer) change(name BucketName) *savepointBucketChange {
+	change, ok := s.buckets[name.String()]
+	if !ok {
+		change = &savepointBucketChange{
+			writes: newWriteBuffer(),
+		}
+		s.buckets[name.String()] = change
+	}
+	return change
+}
+
+// merge applies the changes of upper on top of s.
+func (s *savepointLayer) merge(upper *savepointLayer) {
+	for name, upperChange := range upper.buckets {
+		change, ok := s.buckets[name]
+		if !ok || upperChange.deleted {
+			s.buckets[name] = &savepointBucketChange{
+				deleted: upperChange.deleted || (ok && change.deleted),
+				exists:  upperChange.exists,
+				writes:  upperChange.writes.Clone(),
+			}
+			continue
+		}
+		change.exists = change.exists || upperChange.exists
+		change.writes.Merge(upperChange.writes)
+	}
+}
+
+// with returns a new layer with the changes of upper on top of s, s is not modified.
+// Buckets without changes in upper are shared with s, upper must not be used afterwards.
+func (s *savepointLayer) with(upper *savepointLayer) *savepointLayer {
+	result := &savepointLayer{
+		id:      s.id,
+		buckets: make(map[string]*savepointBucketChange, len(s.buckets)+len(upper.buckets)),
+	}
+	for name, change := range s.buckets {
+		result.buckets[name] = change
+	}
+	for name, upperChange := range upper.buckets {
+		change, ok := s.buckets[name]
+		if !ok || upperChange.deleted {
+			result.buckets[name] = &savepointBucketChange{
+				deleted: upperChange.deleted || (ok && change.deleted),
+				exists:  upperChange.exists,
+				writes:  upperChange.writes,
+			}
+			continue
+		}
+		writes := change.writes.Clone()
+		writes.Merge(upperChange.writes)
+		result.buckets[name] = &savepointBucketChange{
+			deleted: change.deleted,
+			exists:  change.exists || upperChange.exists,
+			writes:  writes,
+		}
+	}
+	return result
+}
+
+func (s *savepointTx) Savepoint(ctx context.Context) (Savepoint, error) {
+	s.nextID++
+	s.layers = append(s.layers, newSavepointLayer(s.nextID))
+	return s.nextID, nil
+}
+
+func (s *savepointTx) RollbackTo(ctx context.Context, savepoint Savepoint) error {
+	pos, err := s.layerPosition(ctx, savepoint)
+	if err != nil {
+		return err
+	}
+	s.layers = append(s.layers[:pos], newSavepointLayer(savepoint))
+	return nil
+}
+
+func (s *savepointTx) Release(ctx context.Context, savepoint Savepoint) error {
+	pos, err := s.layerPosition(ctx, savepoint)
+	if err != nil {
+		return err
+	}
+	released := newSavepointLayer(savepoint)
+	for _, layer := range s.layers[pos:] {
+		released.merge(layer)
+	}
+	s.layers = s.layers[:pos]
+	if pos > 0 {
+		s.layers[pos-1].merge(released)
+		return nil
+	}
+	if err := s.apply(ctx, released); err != nil {
+		return errors.Wrapf(ctx, err, "apply savepoint %d failed", savepoint)
+	}
+	return nil
+}
+
+func (s *savepointTx) layerPosition(ctx context.Context, savepoint Savepoint) (int, error) {
+	for i, layer := range s.layers {
+		if layer.id == savepoint {
+			return i, nil
+		}
+	}
+	return -1, errors.Wrapf(ctx, ErrSavepointNotFound, "savepoint %d", savepoint)
+}
+
+// apply writes the layer to the underlying transaction.
+func (s *savepointTx) apply(ctx context.Context, layer *savepointLayer) error {
+	for _, name := range sortedBucketChangeNames(layer.buckets) {
+		change := layer.buckets[name]
+		bucketName := NewBucketName(name)
+		if change.deleted {
+			if err := s.tx.DeleteBucket(ctx, bucketName); err != nil &&
+				!errors.Is(err, ErrBucketNotFound) {
+				return errors.Wrapf(ctx, err, "delete bucket %s failed", name)
+			}
+		}
+		if !change.exists {
+			continue
+		}
+		bucket, err := s.tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket %s failed", name)
+		}
+		if err := change.writes.Apply(ctx, bucket); err != nil {
+			return errors.Wrapf(ctx, err, "write bucket %s failed", name)
+		}
+	}
+	return nil
+}
+
+// exists reports whether the bucket exists considering all layers.
+func (s *savepointTx) exists(ctx context.Context, name BucketName) (bool, error) {
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		change, ok := s.layers[i].buckets[name.String()]
+		if !ok {
+			continue
+		}
+		if change.exists {
+			return true, nil
+		}
+		if change.deleted {
+			return false, nil
+		}
+	}
+	_, err := s.tx.Bucket(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *savepointTx) bucket(name BucketName) *savepointBucket {
+	bucket, ok := s.buckets[name.String()]
+	if !ok {
+		bucket = &savepointBucket{
+			tx:   s,
+			name: name,
+		}
+		s.buckets[name.String()] = bucket
+	}
+	return bucket
+}
+
+func (s *savepointTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	if len(s.layers) == 0 {
+		if _, err := s.tx.Bucket(ctx, name); err != nil {
+			return nil, err
+		}
+		return s.bucket(name), nil
+	}
+	exists, err := s.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrBucketNotFound
+	}
+	return s.bucket(name), nil
+}
+
+func (s *savepointTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	if len(s.layers) == 0 {
+		if _, err := s.tx.CreateBucket(ctx, name); err != nil {
+			return nil, err
+		}
+		return s.bucket(name), nil
+	}
+	exists, err := s.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrBucketAlreadyExists
+	}
+	s.top().change(name).exists = true
+	return s.bucket(name), nil
+}
+
+func (s *savepointTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	if len(s.layers) == 0 {
+		if _, err := s.tx.CreateBucketIfNotExists(ctx, name); err != nil {
+			return nil, err
+		}
+		return s.bucket(name), nil
+	}
+	exists, err := s.exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		s.top().change(name).exists = true
+	}
+	return s.bucket(name), nil
+}
+
+func (s *savepointTx) DeleteBucket(ctx context.Context, name BucketName) error {
+	if len(s.layers) == 0 {
+		return s.tx.DeleteBucket(ctx, name)
+	}
+	exists, err := s.exists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrBucketNotFound
+	}
+	s.top().buckets[name.String()] = &savepointBucketChange{
+		deleted: true,
+		writes:  newWriteBuffer(),
+	}
+	return nil
+}
+
+func (s *savepointTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	names, err := s.tx.ListBucketNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, name := range names {
+		existing[name.String()] = true
+	}
+	for _, layer := range s.layers {
+		for name, change := range layer.buckets {
+			if change.deleted {
+				existing[name] = false
+			}
+			if change.exists {
+				existing[name] = true
+			}
+		}
+	}
+	result := BucketNames{}
+	for _, name := range sortedBucketChangeNames(existing) {
+		if existing[name] {
+			result = append(result, NewBucketName(name))
+		}
+	}
+	return result, nil
+}
+
+func (s *savepointTx) top() *savepointLayer {
+	return s.layers[len(s.layers)-1]
+}
+
+type savepointBucket struct {
+	tx   *savepointTx
+	name BucketName
+}
+
+func (s *savepointBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if len(s.tx.layers) == 0 {
+		bucket, err := s.tx.tx.Bucket(ctx, s.name)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(ctx, key, value)
+	}
+	change := s.tx.top().change(s.name)
+	change.exists = true
+	change.writes.Put(key, value)
+	return nil
+}
+
+func (s *savepointBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	for i := len(s.tx.layers) - 1; i >= 0; i-- {
+		change, ok := s.tx.layers[i].buckets[s.name.String()]
+		if !ok {
+			continue
+		}
+		if entry, found := change.writes.Get(key); found {
+			if entry.deleted {
+				return NewByteItem(key, nil), nil
+			}
+			return NewByteItem(key, entry.value), nil
+		}
+		if change.deleted {
+			return NewByteItem(key, nil), nil
+		}
+	}
+	bucket, err := s.tx.tx.Bucket(ctx, s.name)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return NewByteItem(key, nil), nil
+		}
+		return nil, err
+	}
+	return bucket.Get(ctx, key)
+}
+
+func (s *savepointBucket) Delete(ctx context.Context, key []byte) error {
+	if len(s.tx.layers) == 0 {
+		bucket, err := s.tx.tx.Bucket(ctx, s.name)
+		if err != nil {
+			return err
+		}
+		return bucket.Delete(ctx, key)
+	}
+	change := s.tx.top().change(s.name)
+	change.exists = true
+	change.writes.Delete(key)
+	return nil
+}
+
+func (s *savepointBucket) Iterator() Iterator {
+	return s.iterator(false)
+}
+
+func (s *savepointBucket) IteratorReverse() Iterator {
+	return s.iterator(true)
+}
+
+// iterator merges the write buffers of all layers, highest layer first, with
+// the underlying bucket unless a layer deleted the bucket.
+func (s *savepointBucket) iterator(reverse bool) Iterator {
+	var iterators []Iterator
+	for i := len(s.tx.layers) - 1; i >= 0; i-- {
+		change, ok := s.tx.layers[i].buckets[s.name.String()]
+		if !ok {
+			continue
+		}
+		iterators = append(iterators, change.writes.Iterator(reverse))
+		if change.deleted {
+			return newMergeIterator(reverse, iterators...)
+		}
+	}
+	bucket, err := s.tx.tx.Bucket(context.Background(), s.name)
+	if err == nil {
+		if reverse {
+			iterators = append(iterators, bucket.IteratorReverse())
+		} else {
+			iterators = append(iterators, bucket.Iterator())
+		}
+	}
+	return newMergeIterator(reverse, iterators...)
+}
+
+func sortedBucketChangeNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return bytes.Compare([]byte(names[i]), []byte(names[j])) < 0
+	})
+	return names
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+// openSavepointDB opens a savepoint at the start of each Update,
+// so all writes of the reused test suites go through the write buffer.
+type openSavepointDB struct {
+	kv.DB
+}
+
+func (o openSavepointDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx kv.Tx) error,
+) error {
+	return o.DB.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+		if _, err := tx.(kv.SavepointTx).Savepoint(ctx); err != nil {
+			return err
+		}
+		return fn(ctx, tx)
+	})
+}
+
+func collectKeys(it kv.Iterator, seek []byte) []string {
+	defer it.Close()
+	var keys []string
+	if seek == nil {
+		it.Rewind()
+	} else {
+		it.Seek(seek)
+	}
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, string(it.Item().Key()))
+	}
+	return keys
+}
+
+var _ = Describe("SavepointTx", func() {
+	var ctx context.Context
+	var err error
+	var backend *memoryDB
+	var db kv.DB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		db = kv.NewDBWithSavepoints(backend)
+		bucketName = kv.NewBucketName("bucket")
+		Expect(backend.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.CreateBucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			for _, key := range []string{"a", "c", "e"} {
+				if err := bucket.Put(ctx, []byte(key), []byte(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})).To(Succeed())
+	})
+	get := func(ctx context.Context, bucket kv.Bucket, key string) string {
+		item, err := bucket.Get(ctx, []byte(key))
+		Expect(err).To(BeNil())
+		var result string
+		Expect(item.Value(func(val []byte) error {
+			result = string(val)
+			return nil
+		})).To(Succeed())
+		return result
+	}
+	committedKeys := func() []string {
+		var keys []string
+		Expect(backend.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			keys = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return keys
+	}
+	It("reads buffered writes and merges iterators", func() {
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			_, err = tx.(kv.SavepointTx).Savepoint(ctx)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("b"), []byte("b"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("c"), []byte("C"))).To(Succeed())
+			Expect(bucket.Delete(ctx, []byte("e"))).To(Succeed())
+			Expect(bucket.Put(ctx, []byte("f"), []byte("f"))).To(Succeed())
+
+			Expect(get(ctx, bucket, "b")).To(Equal("b"))
+			Expect(get(ctx, bucket, "c")).To(Equal("C"))
+			Expect(get(ctx, bucket, "e")).To(Equal(""))
+			Expect(get(ctx, bucket, "a")).To(Equal("a"))
+
+			Expect(collectKeys(bucket.Iterator(), nil)).
+				To(Equal([]string{"a", "b", "c", "f"}))
+			Expect(collectKeys(bucket.IteratorReverse(), nil)).
+				To(Equal([]string{"f", "c", "b", "a"}))
+			Expect(collectKeys(bucket.Iterator(), []byte("bb"))).
+				To(Equal([]string{"c", "f"}))
+			Expect(collectKeys(bucket.IteratorReverse(), []byte("e"))).
+				To(Equal([]string{"c", "b", "a"}))
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(committedKeys()).To(Equal([]string{"a", "b", "c", "f"}))
+	})
+	It("rolls back to savepoint", func() {
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			savepointTx := tx.(kv.SavepointTx)
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("b"), []byte("b"))).To(Succeed())
+
+			savepoint, err := savepointTx.Savepoint(ctx)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("d"), []byte("d"))).To(Succeed())
+			Expect(bucket.Delete(ctx, []byte("a"))).To(Succeed())
+			Expect(savepointTx.RollbackTo(ctx, savepoint)).To(Succeed())
+
+			Expect(get(ctx, bucket, "d")).To(Equal(""))
+			Expect(get(ctx, bucket, "a")).To(Equal("a"))
+			Expect(bucket.Put(ctx, []byte("g"), []byte("g"))).To(Succeed())
+			return savepointTx.Release(ctx, savepoint)
+		})
+		Expect(err).To(BeNil())
+		Expect(committedKeys()).To(Equal([]string{"a", "b", "c", "e", "g"}))
+	})
+	It("releases nested savepoints into the outer one", func() {
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			savepointTx := tx.(kv.SavepointTx)
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			outer, err := savepointTx.Savepoint(ctx)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("b"), []byte("b"))).To(Succeed())
+			inner, err := savepointTx.Savepoint(ctx)
+			Expect(err).To(BeNil())
+			Expect(bucket.Put(ctx, []byte("d"), []byte("d"))).To(Succeed())
+			Expect(savepointTx.Release(ctx, inner)).To(Succeed())
+			err = savepointTx.RollbackTo(ctx, inner)
+			Expect(errors.Is(err, kv.ErrSavepointNotFound)).To(BeTrue())
+			Expect(savepointTx.RollbackTo(ctx, outer)).To(Succeed())
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(committedKeys()).To(Equal([]string{"a", "c", "e"}))
+	})
+	It("restores deleted buckets on rollback", func() {
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			err := kv.WithSavepoint(ctx, tx, func(ctx context.Context, tx kv.Tx) error {
+				Expect(tx.DeleteBucket(ctx, bucketName)).To(Succeed())
+				_, err := tx.Bucket(ctx, bucketName)
+				Expect(errors.Is(err, kv.ErrBucketNotFound)).To(BeTrue())
+				bucket, err := tx.CreateBucket(ctx, bucketName)
+				Expect(err).To(BeNil())
+				Expect(collectKeys(bucket.Iterator(), nil)).To(BeEmpty())
+				names, err := tx.ListBucketNames(ctx)
+				Expect(err).To(BeNil())
+				Expect(names).To(HaveLen(1))
+				return errors.New("banana")
+			})
+			Expect(err).NotTo(BeNil())
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			Expect(collectKeys(bucket.Iterator(), nil)).To(Equal([]string{"a", "c", "e"}))
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(committedKeys()).To(Equal([]string{"a", "c", "e"}))
+	})
+	It("applies deleted and recreated buckets", func() {
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			return kv.WithSavepoint(ctx, tx, func(ctx context.Context, tx kv.Tx) error {
+				if err := tx.DeleteBucket(ctx, bucketName); err != nil {
+					return err
+				}
+				bucket, err := tx.CreateBucket(ctx, bucketName)
+				if err != nil {
+					return err
+				}
+				return bucket.Put(ctx, []byte("z"), []byte("z"))
+			})
+		})
+		Expect(err).To(BeNil())
+		Expect(committedKeys()).To(Equal([]string{"z"}))
+	})
+	It("returns error for tx without savepoint support", func() {
+		err = kv.WithSavepoint(ctx, &mocks.Tx{}, func(ctx context.Context, tx kv.Tx) error {
+			return nil
+		})
+		Expect(errors.Is(err, kv.ErrSavepointsNotSupported)).To(BeTrue())
+	})
+	Context("test suites", func() {
+		provider := kv.ProviderFunc(func(ctx context.Context) (kv.DB, error) {
+			return openSavepointDB{DB: kv.NewDBWithSavepoints(newMemoryDB())}, nil
+		})
+		kv.BasicTestSuite(provider)
+		kv.BucketTestSuite(provider)
+		kv.IteratorTestSuite(provider)
+		kv.RelationStoreTestSuite(provider)
+	})
+})
@@ -0,0 +1,37 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSavepointNotFound is returned if a savepoint was already released or rolled back.
+var ErrSavepointNotFound = errors.New("savepoint not found")
+
+// ErrSavepointsNotSupported is returned by WithSavepoint if the Tx is not a SavepointTx.
+var ErrSavepointsNotSupported = errors.New("savepoints not supported by tx")
+
+// Savepoint identifies a savepoint created with SavepointTx.Savepoint.
+type Savepoint uint64
+
+//counterfeiter:generate -o mocks/savepoint-tx.go --fake-name SavepointTx . SavepointTx
+
+// SavepointTx is a Tx that supports undoing a part of its writes.
+// All writes after Savepoint are buffered in memory and visible to reads and
+// iterators of the same transaction. RollbackTo discards them, Release keeps
+// them. Once the outermost savepoint is released, the writes are applied to
+// the underlying Tx.
+type SavepointTx interface {
+	Tx
+	// Savepoint starts a new savepoint on top of the current ones.
+	Savepoint(ctx context.Context) (Savepoint, error)
+	// RollbackTo discards all writes after the given savepoint and all savepoints created after it.
+	// The savepoint itself stays active.
+	RollbackTo(ctx context.Context, savepoint Savepoint) error
+	// Release keeps all writes after the given savepoint and removes it and all savepoints created after it.
+	Release(ctx context.Context, savepoint Savepoint) error
+}
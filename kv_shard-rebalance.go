@@ -0,0 +1,217 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// RebalanceShards moves all keys stored on another shard than their owner to the owner,
+// in chunks of at most chunkSize keys. The shards must be the shards of the NewShardedDB.
+// A key already written to its owner is not overwritten, the copy is only deleted.
+// Each chunk is copied to the owners before it is deleted from its shard,
+// so a delete of a key running concurrently to the move of the key may be lost.
+// It returns the number of moved keys.
+func RebalanceShards(ctx context.Context, chunkSize int, shards ...Shard) (int, error) {
+	if err := validateShards(ctx, shards); err != nil {
+		return 0, errors.Wrapf(ctx, err, "rebalance shards failed")
+	}
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	ring := newShardRing(shards)
+	var total int
+	for index, shard := range shards {
+		var bucketNames BucketNames
+		err := shard.DB.View(ctx, func(ctx context.Context, tx Tx) error {
+			var err error
+			bucketNames, err = tx.ListBucketNames(ctx)
+			return err
+		})
+		if err != nil {
+			return total, errors.Wrapf(ctx, err, "list bucket names of shard %s failed", shard.Name)
+		}
+		for _, bucketName := range bucketNames {
+			count, err := rebalanceBucket(ctx, ring, shards, index, bucketName, chunkSize)
+			total += count
+			if err != nil {
+				return total, errors.Wrapf(
+					ctx,
+					err,
+					"rebalance bucket %s of shard %s failed",
+					bucketName,
+					shard.Name,
+				)
+			}
+		}
+	}
+	glog.V(2).Infof("rebalanced %d keys over %d shards", total, len(shards))
+	return total, nil
+}
+
+// rebalanceBucket moves the keys of the bucket on shard index not owned by it.
+func rebalanceBucket(
+	ctx context.Context,
+	ring *shardRing,
+	shards []Shard,
+	index int,
+	bucketName BucketName,
+	chunkSize int,
+) (int, error) {
+	var total int
+	var lastKey []byte
+	for {
+		moves, chunkLastKey, completed, err := rebalanceChunk(
+			ctx,
+			ring,
+			shards[index].DB,
+			index,
+			bucketName,
+			lastKey,
+			chunkSize,
+		)
+		if err != nil {
+			return total, err
+		}
+		if err := rebalanceMove(ctx, shards, index, bucketName, moves); err != nil {
+			return total, err
+		}
+		for _, entries := range moves {
+			total += len(entries)
+		}
+		lastKey = chunkLastKey
+		if completed {
+			return total, nil
+		}
+	}
+}
+
+// rebalanceMove copies the entries to their owners and deletes them from shard index.
+func rebalanceMove(
+	ctx context.Context,
+	shards []Shard,
+	index int,
+	bucketName BucketName,
+	moves map[int][]writeBufferEntry,
+) error {
+	if len(moves) == 0 {
+		return nil
+	}
+	for owner, entries := range moves {
+		if err := rebalanceCopy(ctx, shards[owner].DB, bucketName, entries); err != nil {
+			return errors.Wrapf(ctx, err, "copy to shard %s failed", shards[owner].Name)
+		}
+	}
+	return shards[index].DB.Update(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		for _, entries := range moves {
+			for _, entry := range entries {
+				if err := bucket.Delete(ctx, entry.key); err != nil {
+					return errors.Wrapf(ctx, err, "delete failed")
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// rebalanceChunk returns up to chunkSize entries after lastKey not owned by shard index,
+// grouped by their owner.
+func rebalanceChunk(
+	ctx context.Context,
+	ring *shardRing,
+	db DB,
+	index int,
+	bucketName BucketName,
+	lastKey []byte,
+	chunkSize int,
+) (map[int][]writeBufferEntry, []byte, bool, error) {
+	moves := map[int][]writeBufferEntry{}
+	var count int
+	completed := true
+	err := db.View(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		it := bucket.Iterator()
+		defer it.Close()
+		if lastKey == nil {
+			it.Rewind()
+		} else {
+			it.Seek(lastKey)
+		}
+		for ; it.Valid(); it.Next() {
+			if count >= chunkSize {
+				completed = false
+				return nil
+			}
+			item := it.Item()
+			key := bytes.Clone(item.Key())
+			if bytes.Equal(key, lastKey) {
+				continue
+			}
+			lastKey = key
+			owner := ring.owner(bucketName, key)
+			if owner == index {
+				continue
+			}
+			err := item.Value(func(val []byte) error {
+				moves[owner] = append(moves[owner], writeBufferEntry{
+					key:   key,
+					value: bytes.Clone(val),
+				})
+				return nil
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get value of %s failed", key)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return moves, lastKey, completed, nil
+}
+
+// rebalanceCopy writes the entries to db, if it has no value for them yet.
+func rebalanceCopy(
+	ctx context.Context,
+	db DB,
+	bucketName BucketName,
+	entries []writeBufferEntry,
+) error {
+	return db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create bucket failed")
+		}
+		for _, entry := range entries {
+			item, err := bucket.Get(ctx, entry.key)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get %s failed", entry.key)
+			}
+			if item.Exists() {
+				continue
+			}
+			if err := bucket.Put(ctx, entry.key, entry.value); err != nil {
+				return errors.Wrapf(ctx, err, "put %s failed", entry.key)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/bborbe/errors"
+)
+
+// shardVirtualNodes is the number of points of each shard on the ring.
+const shardVirtualNodes = 128
+
+// Shard is a named DB of NewShardedDB. The name places the shard on the
+// consistent hash ring, so it must stay the same when shards are added.
+type Shard struct {
+	Name string
+	DB   DB
+}
+
+// validateShards returns an error if no shard is given, or a shard has no DB
+// or a name that is empty or used twice, because the owner of keys would be ambiguous.
+func validateShards(ctx context.Context, shards []Shard) error {
+	if len(shards) == 0 {
+		return errors.Errorf(ctx, "no shards given")
+	}
+	names := map[string]bool{}
+	for _, shard := range shards {
+		if shard.Name == "" {
+			return errors.Errorf(ctx, "shard without name")
+		}
+		if names[shard.Name] {
+			return errors.Errorf(ctx, "duplicate shard name %s", shard.Name)
+		}
+		if shard.DB == nil {
+			return errors.Errorf(ctx, "shard %s without db", shard.Name)
+		}
+		names[shard.Name] = true
+	}
+	return nil
+}
+
+// newShardRing returns a consistent hash ring of the given shards, see validateShards.
+func newShardRing(shards []Shard) *shardRing {
+	ring := &shardRing{}
+	for index, shard := range shards {
+		for i := 0; i < shardVirtualNodes; i++ {
+			ring.points = append(ring.points, shardRingPoint{
+				hash:  shardHash([]byte(shard.Name + "#" + strconv.Itoa(i))),
+				index: index,
+			})
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool {
+		return ring.points[i].hash < ring.points[j].hash
+	})
+	return ring
+}
+
+type shardRing struct {
+	points []shardRingPoint
+}
+
+type shardRingPoint struct {
+	hash  uint64
+	index int
+}
+
+// owner returns the index of the shard owning the key of the bucket.
+func (s *shardRing) owner(bucketName BucketName, key []byte) int {
+	value := binary.AppendUvarint(nil, uint64(len(bucketName)))
+	value = append(value, bucketName...)
+	hash := shardHash(append(value, key...))
+	i := sort.Search(len(s.points), func(i int) bool {
+		return s.points[i].hash >= hash
+	})
+	if i == len(s.points) {
+		i = 0
+	}
+	return s.points[i].index
+}
+
+// shardHash returns FNV-1a of value, finalized like murmur3 to spread similar values.
+func shardHash(value []byte) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(value)
+	result := hash.Sum64()
+	result ^= result >> 33
+	result *= 0xff51afd7ed558ccd
+	result ^= result >> 33
+	result *= 0xc4ceb9fe1a85ec53
+	result ^= result >> 33
+	return result
+}
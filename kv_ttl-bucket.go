@@ -0,0 +1,254 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+const ttlHeaderLength = 8
+
+// NewTTLBucket returns the TTLBucket of the given name.
+// It returns ErrBucketNotFound if the bucket does not exist.
+func NewTTLBucket(ctx context.Context, tx Tx, bucketName BucketName) (TTLBucket, error) {
+	bucket, err := tx.Bucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return newTTLBucket(tx, bucketName, bucket), nil
+}
+
+// CreateTTLBucketIfNotExists returns the TTLBucket of the given name and creates it if needed.
+func CreateTTLBucketIfNotExists(
+	ctx context.Context,
+	tx Tx,
+	bucketName BucketName,
+) (TTLBucket, error) {
+	bucket, err := tx.CreateBucketIfNotExists(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return newTTLBucket(tx, bucketName, bucket), nil
+}
+
+func newTTLBucket(tx Tx, bucketName BucketName, bucket Bucket) TTLBucket {
+	return &ttlBucket{
+		tx:         tx,
+		bucketName: bucketName,
+		bucket:     bucket,
+	}
+}
+
+type ttlBucket struct {
+	tx         Tx
+	bucketName BucketName
+	bucket     Bucket
+}
+
+func (t *ttlBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	return t.put(ctx, key, value, time.Time{})
+}
+
+func (t *ttlBucket) PutWithTTL(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	ttl time.Duration,
+) error {
+	return t.put(ctx, key, value, time.Now().Add(ttl))
+}
+
+func (t *ttlBucket) put(ctx context.Context, key []byte, value []byte, expires time.Time) error {
+	if err := t.removeIndex(ctx, key); err != nil {
+		return err
+	}
+	if !expires.IsZero() {
+		index, err := t.tx.CreateBucketIfNotExists(ctx, TTLIndexBucketName(t.bucketName))
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get ttl index bucket failed")
+		}
+		if err := index.Put(ctx, ttlIndexKey(expires, key), key); err != nil {
+			return errors.Wrapf(ctx, err, "put ttl index failed")
+		}
+	}
+	if err := t.bucket.Put(ctx, key, encodeTTLValue(expires, value)); err != nil {
+		return errors.Wrapf(ctx, err, "put failed")
+	}
+	return nil
+}
+
+func (t *ttlBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	expires, value, err := t.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if isTTLExpired(expires, time.Now()) {
+		return NewByteItem(key, nil), nil
+	}
+	return NewByteItem(key, value), nil
+}
+
+// get returns the expiry and value of key, value is nil if the key does not exist.
+func (t *ttlBucket) get(ctx context.Context, key []byte) (time.Time, []byte, error) {
+	item, err := t.bucket.Get(ctx, key)
+	if err != nil {
+		return time.Time{}, nil, errors.Wrapf(ctx, err, "get failed")
+	}
+	if !item.Exists() {
+		return time.Time{}, nil, nil
+	}
+	var expires time.Time
+	var value []byte
+	err = item.Value(func(val []byte) error {
+		var ok bool
+		expires, value, ok = decodeTTLValue(val)
+		if !ok {
+			return errors.Wrapf(ctx, ErrInvalidTTLValue, "key %s", key)
+		}
+		value = bytes.Clone(value)
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return expires, value, nil
+}
+
+func (t *ttlBucket) Delete(ctx context.Context, key []byte) error {
+	if err := t.removeIndex(ctx, key); err != nil {
+		return err
+	}
+	return t.bucket.Delete(ctx, key)
+}
+
+// removeIndex removes the index entry of the current value of key.
+func (t *ttlBucket) removeIndex(ctx context.Context, key []byte) error {
+	expires, value, err := t.get(ctx, key)
+	if err != nil && !errors.Is(err, ErrInvalidTTLValue) {
+		return err
+	}
+	if value == nil || expires.IsZero() {
+		return nil
+	}
+	index, err := t.tx.Bucket(ctx, TTLIndexBucketName(t.bucketName))
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) {
+			return nil
+		}
+		return errors.Wrapf(ctx, err, "get ttl index bucket failed")
+	}
+	if err := index.Delete(ctx, ttlIndexKey(expires, key)); err != nil {
+		return errors.Wrapf(ctx, err, "delete ttl index failed")
+	}
+	return nil
+}
+
+func (t *ttlBucket) Iterator() Iterator {
+	return newTTLIterator(t.bucket.Iterator())
+}
+
+func (t *ttlBucket) IteratorReverse() Iterator {
+	return newTTLIterator(t.bucket.IteratorReverse())
+}
+
+// newTTLIterator skips expired items and removes the expiry header of values.
+func newTTLIterator(it Iterator) Iterator {
+	return &ttlIterator{
+		it:  it,
+		now: time.Now(),
+	}
+}
+
+type ttlIterator struct {
+	it   Iterator
+	now  time.Time
+	item Item
+}
+
+func (t *ttlIterator) Close() {
+	t.it.Close()
+}
+
+func (t *ttlIterator) Item() Item {
+	return t.item
+}
+
+func (t *ttlIterator) Next() {
+	t.it.Next()
+	t.skip()
+}
+
+func (t *ttlIterator) Valid() bool {
+	return t.it.Valid()
+}
+
+func (t *ttlIterator) Rewind() {
+	t.it.Rewind()
+	t.skip()
+}
+
+func (t *ttlIterator) Seek(key []byte) {
+	t.it.Seek(key)
+	t.skip()
+}
+
+func (t *ttlIterator) skip() {
+	for ; t.it.Valid(); t.it.Next() {
+		item := t.it.Item()
+		var expires time.Time
+		var value []byte
+		var ok bool
+		_ = item.Value(func(val []byte) error {
+			expires, value, ok = decodeTTLValue(val)
+			value = bytes.Clone(value)
+			return nil
+		})
+		if !ok {
+			glog.Warningf("skip key %s with invalid ttl value", item.Key())
+			continue
+		}
+		if isTTLExpired(expires, t.now) {
+			continue
+		}
+		t.item = NewByteItem(bytes.Clone(item.Key()), value)
+		return
+	}
+}
+
+func isTTLExpired(expires time.Time, now time.Time) bool {
+	return !expires.IsZero() && !now.Before(expires)
+}
+
+// encodeTTLValue prefixes value with the expiry in unix nanoseconds, zero if it never expires.
+func encodeTTLValue(expires time.Time, value []byte) []byte {
+	result := make([]byte, ttlHeaderLength, ttlHeaderLength+len(value))
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(result, uint64(expires.UnixNano()))
+	}
+	return append(result, value...)
+}
+
+func decodeTTLValue(value []byte) (time.Time, []byte, bool) {
+	if len(value) < ttlHeaderLength {
+		return time.Time{}, nil, false
+	}
+	var expires time.Time
+	if nanos := binary.BigEndian.Uint64(value); nanos != 0 {
+		expires = time.Unix(0, int64(nanos))
+	}
+	return expires, value[ttlHeaderLength:], true
+}
+
+// ttlIndexKey orders the index by expiry.
+func ttlIndexKey(expires time.Time, key []byte) []byte {
+	result := binary.BigEndian.AppendUint64(nil, uint64(expires.UnixNano()))
+	return append(result, key...)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("TTLBucket", func() {
+	var ctx context.Context
+	var db *memoryDB
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		db = newMemoryDB()
+		bucketName = kv.NewBucketName("sessions")
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := kv.CreateTTLBucketIfNotExists(ctx, tx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("forever"), []byte("forever")); err != nil {
+				return err
+			}
+			err = bucket.PutWithTTL(ctx, []byte("expired"), []byte("x"), time.Nanosecond)
+			if err != nil {
+				return err
+			}
+			return bucket.PutWithTTL(ctx, []byte("valid"), []byte("valid"), time.Hour)
+		})).To(Succeed())
+	})
+	view := func(fn func(bucket kv.TTLBucket)) {
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := kv.NewTTLBucket(ctx, tx, bucketName)
+			Expect(err).To(BeNil())
+			fn(bucket)
+			return nil
+		})).To(Succeed())
+	}
+	rawKeys := func(bucketName kv.BucketName) []string {
+		var keys []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			keys = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return keys
+	}
+	It("hides expired keys", func() {
+		view(func(bucket kv.TTLBucket) {
+			item, err := bucket.Get(ctx, []byte("expired"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeFalse())
+
+			item, err = bucket.Get(ctx, []byte("valid"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeTrue())
+			Expect(item.Value(func(val []byte) error {
+				Expect(string(val)).To(Equal("valid"))
+				return nil
+			})).To(Succeed())
+
+			Expect(collectKeys(bucket.Iterator(), nil)).To(Equal([]string{"forever", "valid"}))
+			Expect(collectKeys(bucket.IteratorReverse(), nil)).
+				To(Equal([]string{"valid", "forever"}))
+			Expect(collectKeys(bucket.Iterator(), []byte("e"))).
+				To(Equal([]string{"forever", "valid"}))
+		})
+	})
+	It("returns error for bucket not found", func() {
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			_, err := kv.NewTTLBucket(ctx, tx, kv.NewBucketName("missing"))
+			Expect(errors.Is(err, kv.ErrBucketNotFound)).To(BeTrue())
+			return nil
+		})).To(Succeed())
+	})
+	It("sweeps expired keys", func() {
+		sweeper := kv.NewTTLSweeper(db, kv.TTLSweeperOptions{ChunkSize: 1}, bucketName)
+		count, err := sweeper.Sweep(ctx)
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(1))
+		Expect(rawKeys(bucketName)).To(Equal([]string{"forever", "valid"}))
+		Expect(rawKeys(kv.TTLIndexBucketName(bucketName))).To(HaveLen(1))
+	})
+	It("updates the index on overwrite and delete", func() {
+		Expect(db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := kv.NewTTLBucket(ctx, tx, bucketName)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(ctx, []byte("expired"), []byte("again")); err != nil {
+				return err
+			}
+			return bucket.Delete(ctx, []byte("valid"))
+		})).To(Succeed())
+		Expect(rawKeys(kv.TTLIndexBucketName(bucketName))).To(BeEmpty())
+
+		count, err := kv.NewTTLSweeper(db, kv.TTLSweeperOptions{}, bucketName).Sweep(ctx)
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(0))
+		Expect(rawKeys(bucketName)).To(Equal([]string{"expired", "forever"}))
+	})
+	It("sweeps in run until canceled", func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- kv.NewTTLSweeper(db, kv.TTLSweeperOptions{}, bucketName).Run(runCtx)
+		}()
+		Eventually(func() []string { return rawKeys(bucketName) }).
+			Should(Equal([]string{"forever", "valid"}))
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
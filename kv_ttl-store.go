@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// TTLStoreTx is a StoreTx whose objects can expire, see TTLBucket.
+type TTLStoreTx[KEY ~[]byte | ~string, OBJECT any] interface {
+	StoreTx[KEY, OBJECT]
+	// AddWithTTL adds object expiring after ttl. Add adds an object that never expires.
+	AddWithTTL(ctx context.Context, tx Tx, key KEY, object OBJECT, ttl time.Duration) error
+}
+
+// NewTTLStoreTx returns a TTLStoreTx for the given bucket.
+func NewTTLStoreTx[KEY ~[]byte | ~string, OBJECT any](
+	bucketName BucketName,
+) TTLStoreTx[KEY, OBJECT] {
+	return &ttlStoreTx[KEY, OBJECT]{
+		bucketName: bucketName,
+		storeTx:    NewStoreTx[KEY, OBJECT](bucketName),
+	}
+}
+
+type ttlStoreTx[KEY ~[]byte | ~string, OBJECT any] struct {
+	bucketName BucketName
+	storeTx    StoreTx[KEY, OBJECT]
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) wrap(tx Tx) Tx {
+	return &ttlTx{
+		Tx:         tx,
+		bucketName: t.bucketName,
+	}
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) AddWithTTL(
+	ctx context.Context,
+	tx Tx,
+	key KEY,
+	object OBJECT,
+	ttl time.Duration,
+) error {
+	bucket, err := CreateTTLBucketIfNotExists(ctx, tx, t.bucketName)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get bucket failed")
+	}
+	value, err := json.Marshal(object)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	if err = bucket.PutWithTTL(ctx, []byte(key), value, ttl); err != nil {
+		return errors.Wrapf(ctx, err, "set failed")
+	}
+	return nil
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Add(ctx context.Context, tx Tx, key KEY, object OBJECT) error {
+	return t.storeTx.Add(ctx, t.wrap(tx), key, object)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Remove(ctx context.Context, tx Tx, key KEY) error {
+	return t.storeTx.Remove(ctx, t.wrap(tx), key)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Get(ctx context.Context, tx Tx, key KEY) (*OBJECT, error) {
+	return t.storeTx.Get(ctx, t.wrap(tx), key)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Exists(ctx context.Context, tx Tx, key KEY) (bool, error) {
+	return t.storeTx.Exists(ctx, t.wrap(tx), key)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Map(
+	ctx context.Context,
+	tx Tx,
+	fn func(ctx context.Context, key KEY, object OBJECT) error,
+) error {
+	return t.storeTx.Map(ctx, t.wrap(tx), fn)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) Stream(ctx context.Context, tx Tx, ch chan<- OBJECT) error {
+	return t.storeTx.Stream(ctx, t.wrap(tx), ch)
+}
+
+func (t *ttlStoreTx[KEY, OBJECT]) List(ctx context.Context, tx Tx) ([]OBJECT, error) {
+	return t.storeTx.List(ctx, t.wrap(tx))
+}
+
+// ttlTx returns the bucket of bucketName as TTLBucket.
+type ttlTx struct {
+	Tx
+	bucketName BucketName
+}
+
+func (t *ttlTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	if !name.Equal(t.bucketName) {
+		return t.Tx.Bucket(ctx, name)
+	}
+	return NewTTLBucket(ctx, t.Tx, name)
+}
+
+func (t *ttlTx) CreateBucketIfNotExists(ctx context.Context, name BucketName) (Bucket, error) {
+	if !name.Equal(t.bucketName) {
+		return t.Tx.CreateBucketIfNotExists(ctx, name)
+	}
+	return CreateTTLBucketIfNotExists(ctx, t.Tx, name)
+}
+
+// TTLStore is a Store whose objects can expire, see TTLBucket.
+type TTLStore[KEY ~[]byte | ~string, OBJECT any] interface {
+	Store[KEY, OBJECT]
+	// AddWithTTL adds object expiring after ttl. Add adds an object that never expires.
+	AddWithTTL(ctx context.Context, key KEY, object OBJECT, ttl time.Duration) error
+}
+
+// NewTTLStore returns a TTLStore for the given bucket.
+func NewTTLStore[KEY ~[]byte | ~string, OBJECT any](
+	db DB,
+	bucketName BucketName,
+) TTLStore[KEY, OBJECT] {
+	storeTx := NewTTLStoreTx[KEY, OBJECT](bucketName)
+	return &ttlStore[KEY, OBJECT]{
+		Store:   NewStoreFromTx[KEY, OBJECT](db, storeTx),
+		db:      db,
+		storeTx: storeTx,
+	}
+}
+
+type ttlStore[KEY ~[]byte | ~string, OBJECT any] struct {
+	Store[KEY, OBJECT]
+	db      DB
+	storeTx TTLStoreTx[KEY, OBJECT]
+}
+
+func (t *ttlStore[KEY, OBJECT]) AddWithTTL(
+	ctx context.Context,
+	key KEY,
+	object OBJECT,
+	ttl time.Duration,
+) error {
+	return t.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return t.storeTx.AddWithTTL(ctx, tx, key, object, ttl)
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+)
+
+var _ = Describe("TTLStore", func() {
+	var ctx context.Context
+	var store kv.TTLStore[string, TestObject]
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = kv.NewTTLStore[string, TestObject](newMemoryDB(), kv.NewBucketName("tokens"))
+		Expect(store.Add(ctx, "john", TestObject{Name: "John"})).To(Succeed())
+		Expect(store.AddWithTTL(ctx, "jane", TestObject{Name: "Jane"}, time.Hour)).To(Succeed())
+		Expect(store.AddWithTTL(ctx, "old", TestObject{Name: "Old"}, time.Nanosecond)).
+			To(Succeed())
+	})
+	It("gets objects not expired", func() {
+		object, err := store.Get(ctx, "jane")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("Jane"))
+		object, err = store.Get(ctx, "john")
+		Expect(err).To(BeNil())
+		Expect(object.Name).To(Equal("John"))
+	})
+	It("hides expired objects", func() {
+		_, err := store.Get(ctx, "old")
+		Expect(errors.Is(err, kv.KeyNotFoundError)).To(BeTrue())
+		exists, err := store.Exists(ctx, "old")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeFalse())
+		objects, err := store.List(ctx)
+		Expect(err).To(BeNil())
+		Expect(objects).To(Equal([]TestObject{{Name: "Jane"}, {Name: "John"}}))
+	})
+	It("removes objects", func() {
+		Expect(store.Remove(ctx, "jane")).To(Succeed())
+		exists, err := store.Exists(ctx, "jane")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeFalse())
+	})
+})
@@ -0,0 +1,163 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// TTLSweeperOptions configures NewTTLSweeper.
+// Unset values are replaced by the defaults of DefaultTTLSweeperOptions.
+type TTLSweeperOptions struct {
+	// Interval between two sweeps of Run.
+	Interval time.Duration
+	// ChunkSize is the maximum number of keys deleted per transaction.
+	ChunkSize int
+}
+
+// DefaultTTLSweeperOptions returns the default TTLSweeperOptions.
+func DefaultTTLSweeperOptions() TTLSweeperOptions {
+	return TTLSweeperOptions{
+		Interval:  time.Minute,
+		ChunkSize: 1000,
+	}
+}
+
+//counterfeiter:generate -o mocks/ttl-sweeper.go --fake-name TTLSweeper . TTLSweeper
+
+// TTLSweeper deletes expired keys of TTLBuckets.
+type TTLSweeper interface {
+	// Sweep deletes all keys expired until now and returns their number.
+	Sweep(ctx context.Context) (int, error)
+	// Run sweeps every Interval until the context is canceled. It can be used as run.Func.
+	Run(ctx context.Context) error
+}
+
+// NewTTLSweeper returns a TTLSweeper for the given TTLBuckets.
+func NewTTLSweeper(
+	db DB,
+	options TTLSweeperOptions,
+	bucketNames ...BucketName,
+) TTLSweeper {
+	defaults := DefaultTTLSweeperOptions()
+	if options.Interval <= 0 {
+		options.Interval = defaults.Interval
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = defaults.ChunkSize
+	}
+	return &ttlSweeper{
+		db:          db,
+		options:     options,
+		bucketNames: bucketNames,
+	}
+}
+
+type ttlSweeper struct {
+	db          DB
+	options     TTLSweeperOptions
+	bucketNames BucketNames
+}
+
+func (t *ttlSweeper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.options.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := t.Sweep(ctx); err != nil {
+			glog.Warningf("sweep expired keys failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *ttlSweeper) Sweep(ctx context.Context) (int, error) {
+	now := time.Now()
+	var total int
+	for _, bucketName := range t.bucketNames {
+		for {
+			count, err := t.sweepChunk(ctx, bucketName, now)
+			if err != nil {
+				return total, errors.Wrapf(ctx, err, "sweep bucket %s failed", bucketName)
+			}
+			total += count
+			if count < t.options.ChunkSize {
+				break
+			}
+		}
+	}
+	if total > 0 {
+		glog.V(3).Infof("swept %d expired keys", total)
+	}
+	return total, nil
+}
+
+// sweepChunk deletes up to ChunkSize keys expired before now in one transaction.
+func (t *ttlSweeper) sweepChunk(
+	ctx context.Context,
+	bucketName BucketName,
+	now time.Time,
+) (int, error) {
+	var count int
+	err := t.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		count = 0
+		index, err := tx.Bucket(ctx, TTLIndexBucketName(bucketName))
+		if err != nil {
+			if errors.Is(err, ErrBucketNotFound) {
+				return nil
+			}
+			return errors.Wrapf(ctx, err, "get ttl index bucket failed")
+		}
+		indexKeys := t.expiredIndexKeys(index, now)
+		bucket, err := tx.Bucket(ctx, bucketName)
+		if err != nil && !errors.Is(err, ErrBucketNotFound) {
+			return errors.Wrapf(ctx, err, "get bucket failed")
+		}
+		for _, indexKey := range indexKeys {
+			if bucket != nil {
+				if err := bucket.Delete(ctx, indexKey[ttlHeaderLength:]); err != nil {
+					return errors.Wrapf(ctx, err, "delete failed")
+				}
+			}
+			if err := index.Delete(ctx, indexKey); err != nil {
+				return errors.Wrapf(ctx, err, "delete ttl index failed")
+			}
+		}
+		count = len(indexKeys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// expiredIndexKeys returns up to ChunkSize index keys expired before now.
+// The index only contains the current expiry of each key, see ttlBucket.removeIndex.
+func (t *ttlSweeper) expiredIndexKeys(index Bucket, now time.Time) [][]byte {
+	end := ttlIndexKey(now, nil)
+	var result [][]byte
+	it := index.Iterator()
+	defer it.Close()
+	for it.Rewind(); it.Valid() && len(result) < t.options.ChunkSize; it.Next() {
+		key := it.Item().Key()
+		if len(key) < ttlHeaderLength {
+			continue
+		}
+		if bytes.Compare(key[:ttlHeaderLength], end) > 0 {
+			break
+		}
+		result = append(result, bytes.Clone(key))
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidTTLValue is returned if a value of a TTLBucket has no expiry header.
+var ErrInvalidTTLValue = errors.New("invalid ttl value")
+
+// TTLIndexBucketName returns the name of the expiry index bucket of a TTLBucket.
+func TTLIndexBucketName(bucketName BucketName) BucketName {
+	return BucketFromStrings(bucketName.String(), "ttl")
+}
+
+// TTLBucket is a Bucket whose keys can expire. Expired keys are hidden from Get and
+// iterators until the TTLSweeper deletes them.
+// All values of the bucket must be written through a TTLBucket, each value is stored
+// with its expiry and the expiry index is kept in the bucket of TTLIndexBucketName.
+type TTLBucket interface {
+	Bucket
+	// PutWithTTL writes key expiring after ttl. Put writes a key that never expires.
+	PutWithTTL(ctx context.Context, key []byte, value []byte, ttl time.Duration) error
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/bborbe/errors"
+)
+
+// valueTransform converts values between their stored and their plain form.
+// It is shared by the wrappers that change how values are stored, like encryption.
+// decode must not return memory of value, it is only valid within Item.Value.
+type valueTransform interface {
+	encode(ctx context.Context, bucketName BucketName, key []byte, value []byte) ([]byte, error)
+	decode(ctx context.Context, bucketName BucketName, key []byte, value []byte) ([]byte, error)
+}
+
+// newDBWithValueTransform returns a DB storing the values of the given buckets,
+// or of all buckets if none is given, in the form of transform.
+func newDBWithValueTransform(
+	db DB,
+	transform valueTransform,
+	bucketNames BucketNames,
+) DB {
+	return &dbWithValueTransform{
+		db:          db,
+		transform:   transform,
+		bucketNames: bucketNames,
+	}
+}
+
+type dbWithValueTransform struct {
+	db          DB
+	transform   valueTransform
+	bucketNames BucketNames
+}
+
+func (d *dbWithValueTransform) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, d.wrap(tx))
+	})
+}
+
+func (d *dbWithValueTransform) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	return d.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, d.wrap(tx))
+	})
+}
+
+func (d *dbWithValueTransform) wrap(tx Tx) Tx {
+	return &valueTransformTx{
+		Tx: tx,
+		db: d,
+	}
+}
+
+func (d *dbWithValueTransform) applies(bucketName BucketName) bool {
+	return len(d.bucketNames) == 0 || d.bucketNames.Contains(bucketName)
+}
+
+func (d *dbWithValueTransform) Sync() error {
+	return d.db.Sync()
+}
+
+func (d *dbWithValueTransform) Close() error {
+	return d.db.Close()
+}
+
+func (d *dbWithValueTransform) Remove() error {
+	return d.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (d *dbWithValueTransform) ReadOnly() bool {
+	return IsReadOnly(d.db)
+}
+
+func (d *dbWithValueTransform) Stats(ctx context.Context) (*Stats, error) {
+	return d.db.Stats(ctx)
+}
+
+func (d *dbWithValueTransform) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return d.db.StatsDetailed(ctx)
+}
+
+type valueTransformTx struct {
+	Tx
+	db *dbWithValueTransform
+}
+
+func (v *valueTransformTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := v.Tx.Bucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.wrap(name, bucket), nil
+}
+
+func (v *valueTransformTx) CreateBucket(ctx context.Context, name BucketName) (Bucket, error) {
+	bucket, err := v.Tx.CreateBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.wrap(name, bucket), nil
+}
+
+func (v *valueTransformTx) CreateBucketIfNotExists(
+	ctx context.Context,
+	name BucketName,
+) (Bucket, error) {
+	bucket, err := v.Tx.CreateBucketIfNotExists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.wrap(name, bucket), nil
+}
+
+func (v *valueTransformTx) wrap(name BucketName, bucket Bucket) Bucket {
+	if !v.db.applies(name) {
+		return bucket
+	}
+	return newValueTransformBucket(bucket, name, v.db.transform)
+}
+
+// newValueTransformBucket returns a Bucket storing values in the form of transform.
+// Empty values are stored as they are, so Item.Exists keeps its meaning.
+func newValueTransformBucket(
+	bucket Bucket,
+	bucketName BucketName,
+	transform valueTransform,
+) Bucket {
+	return &valueTransformBucket{
+		bucket:     bucket,
+		bucketName: bucketName,
+		transform:  transform,
+	}
+}
+
+type valueTransformBucket struct {
+	bucket     Bucket
+	bucketName BucketName
+	transform  valueTransform
+}
+
+func (v *valueTransformBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if len(value) == 0 {
+		return v.bucket.Put(ctx, key, value)
+	}
+	encoded, err := v.transform.encode(ctx, v.bucketName, key, value)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "encode value of %s failed", key)
+	}
+	return v.bucket.Put(ctx, key, encoded)
+}
+
+func (v *valueTransformBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	item, err := v.bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Exists() {
+		return item, nil
+	}
+	var decoded []byte
+	err = item.Value(func(val []byte) error {
+		decoded, err = v.transform.decode(ctx, v.bucketName, key, val)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "decode value of %s failed", key)
+	}
+	return NewByteItem(key, decoded), nil
+}
+
+func (v *valueTransformBucket) Delete(ctx context.Context, key []byte) error {
+	return v.bucket.Delete(ctx, key)
+}
+
+func (v *valueTransformBucket) Iterator() Iterator {
+	return &valueTransformIterator{
+		Iterator: v.bucket.Iterator(),
+		bucket:   v,
+	}
+}
+
+func (v *valueTransformBucket) IteratorReverse() Iterator {
+	return &valueTransformIterator{
+		Iterator: v.bucket.IteratorReverse(),
+		bucket:   v,
+	}
+}
+
+type valueTransformIterator struct {
+	Iterator
+	bucket *valueTransformBucket
+}
+
+func (v *valueTransformIterator) Item() Item {
+	return &valueTransformItem{
+		Item:   v.Iterator.Item(),
+		bucket: v.bucket,
+	}
+}
+
+// valueTransformItem decodes the value on access, errors are returned by Value.
+type valueTransformItem struct {
+	Item
+	bucket *valueTransformBucket
+}
+
+func (v *valueTransformItem) Value(fn func(val []byte) error) error {
+	return v.Item.Value(func(val []byte) error {
+		if len(val) == 0 {
+			return fn(val)
+		}
+		ctx := context.Background()
+		decoded, err := v.bucket.transform.decode(ctx, v.bucket.bucketName, v.Key(), val)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "decode value of %s failed", v.Key())
+		}
+		return fn(decoded)
+	})
+}
@@ -0,0 +1,424 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ErrWriteBehindClosed is returned by WriteBehindDB.Put and Delete after Close.
+var ErrWriteBehindClosed = stderrors.New("write behind db closed")
+
+// WriteBehindOptions configures NewWriteBehindDB.
+// Unset values are replaced by the defaults of DefaultWriteBehindOptions.
+type WriteBehindOptions struct {
+	// FlushSize triggers a flush by Run once this many keys are buffered.
+	FlushSize int
+	// FlushInterval is the maximum time Run keeps writes in the buffer.
+	FlushInterval time.Duration
+	// MaxPending bounds the buffer. Put and Delete flush synchronously if it is reached.
+	MaxPending int
+	// ChunkSize is the maximum number of writes per flush transaction.
+	ChunkSize int
+}
+
+// DefaultWriteBehindOptions returns the default WriteBehindOptions.
+func DefaultWriteBehindOptions() WriteBehindOptions {
+	return WriteBehindOptions{
+		FlushSize:     1000,
+		FlushInterval: 500 * time.Millisecond,
+		MaxPending:    10000,
+		ChunkSize:     1000,
+	}
+}
+
+//counterfeiter:generate -o mocks/write-behind-db.go --fake-name WriteBehindDB . WriteBehindDB
+
+// WriteBehindDB buffers Put and Delete in memory and writes them asynchronously
+// in chunked transactions. Buffered writes are visible to View of the same
+// WriteBehindDB, but lost if the process dies before they are flushed.
+// Update flushes the buffer before it runs, so it always sees all writes.
+type WriteBehindDB interface {
+	DB
+	// Put buffers a write of key in the given bucket. The bucket is created on flush.
+	Put(ctx context.Context, bucketName BucketName, key []byte, value []byte) error
+	// Delete buffers the deletion of key in the given bucket.
+	Delete(ctx context.Context, bucketName BucketName, key []byte) error
+	// Flush writes all buffered writes to the underlying DB.
+	Flush(ctx context.Context) error
+	// Run flushes the buffer every FlushInterval or once FlushSize is reached
+	// until the context is canceled. It can be used as run.Func.
+	Run(ctx context.Context) error
+}
+
+// NewWriteBehindDB returns a WriteBehindDB writing to the given DB.
+func NewWriteBehindDB(db DB, options WriteBehindOptions) WriteBehindDB {
+	defaults := DefaultWriteBehindOptions()
+	if options.FlushSize <= 0 {
+		options.FlushSize = defaults.FlushSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaults.FlushInterval
+	}
+	if options.MaxPending <= 0 {
+		options.MaxPending = defaults.MaxPending
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = defaults.ChunkSize
+	}
+	return &writeBehindDB{
+		db:      db,
+		options: options,
+		pending: map[string]*writeBuffer{},
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+type writeBehindDB struct {
+	db      DB
+	options WriteBehindOptions
+	trigger chan struct{}
+
+	// flushMux serializes flushes
+	flushMux sync.Mutex
+
+	mux sync.Mutex
+	// pending receives new writes
+	pending map[string]*writeBuffer
+	// shared is set once pending is read by View, the next write freezes it
+	shared bool
+	// frozen holds older writes, oldest first. The layers are not modified.
+	frozen       []map[string]*writeBuffer
+	pendingCount int
+	// flushing holds the writes of the running flush, it is not modified
+	flushing map[string]*writeBuffer
+	closed   bool
+}
+
+func (w *writeBehindDB) Put(
+	ctx context.Context,
+	bucketName BucketName,
+	key []byte,
+	value []byte,
+) error {
+	return w.write(ctx, bucketName, func(buffer *writeBuffer) {
+		buffer.Put(key, value)
+	})
+}
+
+func (w *writeBehindDB) Delete(ctx context.Context, bucketName BucketName, key []byte) error {
+	return w.write(ctx, bucketName, func(buffer *writeBuffer) {
+		buffer.Delete(key)
+	})
+}
+
+func (w *writeBehindDB) write(
+	ctx context.Context,
+	bucketName BucketName,
+	fn func(buffer *writeBuffer),
+) error {
+	w.mux.Lock()
+	if w.closed {
+		w.mux.Unlock()
+		return ErrWriteBehindClosed
+	}
+	full := w.pendingCount >= w.options.MaxPending
+	w.mux.Unlock()
+	if full {
+		if err := w.Flush(ctx); err != nil {
+			return errors.Wrapf(ctx, err, "flush full buffer failed")
+		}
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.shared {
+		w.freeze()
+	}
+	buffer, ok := w.pending[bucketName.String()]
+	if !ok {
+		buffer = newWriteBuffer()
+		w.pending[bucketName.String()] = buffer
+	}
+	before := buffer.Len()
+	fn(buffer)
+	w.pendingCount += buffer.Len() - before
+	if w.pendingCount >= w.options.FlushSize {
+		select {
+		case w.trigger <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (w *writeBehindDB) Flush(ctx context.Context) error {
+	w.flushMux.Lock()
+	defer w.flushMux.Unlock()
+
+	w.mux.Lock()
+	flushing := w.pending
+	for i := len(w.frozen) - 1; i >= 0; i-- {
+		flushing = mergeWriteBufferLayers(w.frozen[i], flushing)
+	}
+	w.flushing = flushing
+	w.pending = map[string]*writeBuffer{}
+	w.shared = false
+	w.frozen = nil
+	w.pendingCount = 0
+	w.mux.Unlock()
+
+	if err := w.flush(ctx, flushing); err != nil {
+		// keep the writes as oldest layer, newer pending writes win
+		w.mux.Lock()
+		w.frozen = append([]map[string]*writeBuffer{flushing}, w.frozen...)
+		w.pendingCount = countWriteBuffers(w.pending)
+		for _, layer := range w.frozen {
+			w.pendingCount += countWriteBuffers(layer)
+		}
+		w.flushing = nil
+		w.mux.Unlock()
+		return errors.Wrapf(ctx, err, "flush failed")
+	}
+
+	w.mux.Lock()
+	w.flushing = nil
+	w.mux.Unlock()
+	return nil
+}
+
+// flush writes the buffers in transactions of at most ChunkSize writes.
+func (w *writeBehindDB) flush(ctx context.Context, buffers map[string]*writeBuffer) error {
+	var chunk []writeBehindEntry
+	for _, name := range sortedBucketChangeNames(buffers) {
+		for _, entry := range buffers[name].entries {
+			chunk = append(chunk, writeBehindEntry{bucketName: NewBucketName(name), entry: entry})
+			if len(chunk) >= w.options.ChunkSize {
+				if err := w.writeChunk(ctx, chunk); err != nil {
+					return err
+				}
+				chunk = nil
+			}
+		}
+	}
+	if len(chunk) == 0 {
+		return nil
+	}
+	return w.writeChunk(ctx, chunk)
+}
+
+type writeBehindEntry struct {
+	bucketName BucketName
+	entry      writeBufferEntry
+}
+
+func (w *writeBehindDB) writeChunk(ctx context.Context, chunk []writeBehindEntry) error {
+	err := w.db.Update(ctx, func(ctx context.Context, tx Tx) error {
+		for _, e := range chunk {
+			bucket, err := tx.CreateBucketIfNotExists(ctx, e.bucketName)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get bucket %s failed", e.bucketName)
+			}
+			if e.entry.deleted {
+				if err := bucket.Delete(ctx, e.entry.key); err != nil {
+					return errors.Wrapf(ctx, err, "delete failed")
+				}
+				continue
+			}
+			if err := bucket.Put(ctx, e.entry.key, e.entry.value); err != nil {
+				return errors.Wrapf(ctx, err, "put failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "write chunk of %d failed", len(chunk))
+	}
+	glog.V(3).Infof("wrote chunk of %d buffered writes", len(chunk))
+	return nil
+}
+
+func (w *writeBehindDB) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-w.trigger:
+		}
+		if err := w.Flush(ctx); err != nil {
+			glog.Warningf("flush buffered writes failed: %v", err)
+		}
+	}
+}
+
+// Update flushes the buffer and runs fn in a write transaction.
+func (w *writeBehindDB) Update(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	if err := w.Flush(ctx); err != nil {
+		return errors.Wrapf(ctx, err, "flush before update failed")
+	}
+	return w.db.Update(ctx, fn)
+}
+
+// View runs fn in a read transaction that includes all buffered writes.
+func (w *writeBehindDB) View(
+	ctx context.Context,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	w.mux.Lock()
+	// pending is not copied, the next write freezes it instead
+	layers := make([]map[string]*writeBuffer, 0, len(w.frozen)+2)
+	if len(w.pending) > 0 {
+		layers = append(layers, w.pending)
+		w.shared = true
+	}
+	for i := len(w.frozen) - 1; i >= 0; i-- {
+		layers = append(layers, w.frozen[i])
+	}
+	if w.flushing != nil {
+		layers = append(layers, w.flushing)
+	}
+	w.mux.Unlock()
+
+	return w.db.View(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &writeBehindTx{
+			Tx:     tx,
+			layers: layers,
+		})
+	})
+}
+
+func (w *writeBehindDB) Sync() error {
+	return w.db.Sync()
+}
+
+// Close flushes all buffered writes and closes the underlying DB.
+func (w *writeBehindDB) Close() error {
+	w.mux.Lock()
+	w.closed = true
+	w.mux.Unlock()
+	if err := w.Flush(context.Background()); err != nil {
+		return err
+	}
+	return w.db.Close()
+}
+
+func (w *writeBehindDB) Remove() error {
+	return w.db.Remove()
+}
+
+// ReadOnly forwards IsReadOnly of the wrapped DB.
+func (w *writeBehindDB) ReadOnly() bool {
+	return IsReadOnly(w.db)
+}
+
+func (w *writeBehindDB) Stats(ctx context.Context) (*Stats, error) {
+	return w.db.Stats(ctx)
+}
+
+func (w *writeBehindDB) StatsDetailed(ctx context.Context) (*Stats, error) {
+	return w.db.StatsDetailed(ctx)
+}
+
+// writeBehindTx is a read transaction with buffered writes on top.
+type writeBehindTx struct {
+	Tx
+	layers []map[string]*writeBuffer
+}
+
+func (w *writeBehindTx) Bucket(ctx context.Context, name BucketName) (Bucket, error) {
+	buffers := make([]*writeBuffer, 0, len(w.layers))
+	for _, layer := range w.layers {
+		if buffer, ok := layer[name.String()]; ok {
+			buffers = append(buffers, buffer)
+		}
+	}
+	bucket, err := w.Tx.Bucket(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrBucketNotFound) && len(buffers) > 0 {
+			return newBufferedBucket(nil, buffers...), nil
+		}
+		return nil, err
+	}
+	return newBufferedBucket(bucket, buffers...), nil
+}
+
+func (w *writeBehindTx) ListBucketNames(ctx context.Context) (BucketNames, error) {
+	names, err := w.Tx.ListBucketNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, name := range names {
+		existing[name.String()] = true
+	}
+	for _, layer := range w.layers {
+		for name := range layer {
+			existing[name] = true
+		}
+	}
+	result := BucketNames{}
+	for _, name := range sortedBucketChangeNames(existing) {
+		result = append(result, NewBucketName(name))
+	}
+	return result, nil
+}
+
+// freeze moves pending to the frozen layers. Layers are merged while the newest is
+// at least as large as the one before, so a key is copied O(log n) times until flush
+// and View reads O(log n) layers.
+func (w *writeBehindDB) freeze() {
+	w.frozen = append(w.frozen, w.pending)
+	w.pending = map[string]*writeBuffer{}
+	w.shared = false
+	for len(w.frozen) >= 2 {
+		last := w.frozen[len(w.frozen)-1]
+		before := w.frozen[len(w.frozen)-2]
+		if countWriteBuffers(last) < countWriteBuffers(before) {
+			return
+		}
+		w.frozen = append(w.frozen[:len(w.frozen)-2], mergeWriteBufferLayers(before, last))
+	}
+}
+
+// mergeWriteBufferLayers returns a new layer with the writes of older and newer,
+// newer writes win. Both layers are not modified, buffers of buckets written
+// in only one layer are shared.
+func mergeWriteBufferLayers(older, newer map[string]*writeBuffer) map[string]*writeBuffer {
+	merged := make(map[string]*writeBuffer, len(older)+len(newer))
+	for name, buffer := range older {
+		merged[name] = buffer
+	}
+	for name, buffer := range newer {
+		existing, ok := merged[name]
+		if !ok {
+			merged[name] = buffer
+			continue
+		}
+		clone := existing.Clone()
+		clone.Merge(buffer)
+		merged[name] = clone
+	}
+	return merged
+}
+
+func countWriteBuffers(buffers map[string]*writeBuffer) int {
+	var count int
+	for _, buffer := range buffers {
+		count += buffer.Len()
+	}
+	return count
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/kv"
+	"github.com/bborbe/kv/mocks"
+)
+
+var _ = Describe("WriteBehindDB", func() {
+	var ctx context.Context
+	var err error
+	var backend *memoryDB
+	var db kv.WriteBehindDB
+	var options kv.WriteBehindOptions
+	var bucketName kv.BucketName
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newMemoryDB()
+		options = kv.WriteBehindOptions{
+			FlushSize:     100,
+			FlushInterval: time.Hour,
+			MaxPending:    1000,
+			ChunkSize:     2,
+		}
+		bucketName = kv.NewBucketName("bucket")
+	})
+	JustBeforeEach(func() {
+		db = kv.NewWriteBehindDB(backend, options)
+	})
+	keysOf := func(db kv.DB) []string {
+		var keys []string
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				if errors.Is(err, kv.ErrBucketNotFound) {
+					return nil
+				}
+				return err
+			}
+			keys = collectKeys(bucket.Iterator(), nil)
+			return nil
+		})).To(Succeed())
+		return keys
+	}
+	It("makes buffered writes visible before flush", func() {
+		Expect(db.Put(ctx, bucketName, []byte("b"), []byte("b"))).To(Succeed())
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(keysOf(db)).To(Equal([]string{"a", "b"}))
+		Expect(keysOf(backend)).To(BeEmpty())
+
+		err = db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			names, err := tx.ListBucketNames(ctx)
+			Expect(err).To(BeNil())
+			Expect(names).To(Equal(kv.BucketNames{bucketName}))
+			bucket, err := tx.Bucket(ctx, bucketName)
+			Expect(err).To(BeNil())
+			item, err := bucket.Get(ctx, []byte("a"))
+			Expect(err).To(BeNil())
+			Expect(item.Exists()).To(BeTrue())
+			return nil
+		})
+		Expect(err).To(BeNil())
+	})
+	It("flushes in chunks", func() {
+		for _, key := range []string{"a", "b", "c", "d", "e"} {
+			Expect(db.Put(ctx, bucketName, []byte(key), []byte(key))).To(Succeed())
+		}
+		Expect(db.Delete(ctx, bucketName, []byte("c"))).To(Succeed())
+		Expect(db.Flush(ctx)).To(Succeed())
+		Expect(keysOf(backend)).To(Equal([]string{"a", "b", "d", "e"}))
+		Expect(keysOf(db)).To(Equal([]string{"a", "b", "d", "e"}))
+	})
+	It("hides deleted keys of the underlying db", func() {
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.Flush(ctx)).To(Succeed())
+		Expect(db.Delete(ctx, bucketName, []byte("a"))).To(Succeed())
+		Expect(keysOf(db)).To(BeEmpty())
+		Expect(keysOf(backend)).To(Equal([]string{"a"}))
+	})
+	It("flushes before update", func() {
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		err = db.Update(ctx, func(ctx context.Context, tx kv.Tx) error {
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(ctx, []byte("b"), []byte("b"))
+		})
+		Expect(err).To(BeNil())
+		Expect(keysOf(backend)).To(Equal([]string{"a", "b"}))
+	})
+	It("keeps writes if flush fails", func() {
+		failing := &mocks.DB{}
+		failing.ViewStub = backend.View
+		failing.UpdateReturns(errors.New("banana"))
+		db = kv.NewWriteBehindDB(failing, options)
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.Flush(ctx)).NotTo(Succeed())
+		Expect(keysOf(db)).To(Equal([]string{"a"}))
+
+		failing.UpdateStub = backend.Update
+		Expect(db.Flush(ctx)).To(Succeed())
+		Expect(keysOf(backend)).To(Equal([]string{"a"}))
+	})
+	It("does not modify writes seen by View if flush fails", func() {
+		failing := &mocks.DB{}
+		failing.ViewStub = backend.View
+		db = kv.NewWriteBehindDB(failing, options)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan []string)
+		failing.UpdateStub = func(
+			ctx context.Context,
+			fn func(ctx context.Context, tx kv.Tx) error,
+		) error {
+			go func() {
+				defer GinkgoRecover()
+				Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+					close(started)
+					<-release
+					bucket, err := tx.Bucket(ctx, bucketName)
+					if err != nil {
+						return err
+					}
+					done <- collectKeys(bucket.Iterator(), nil)
+					return nil
+				})).To(Succeed())
+			}()
+			<-started
+			Expect(db.Put(ctx, bucketName, []byte("b"), []byte("b"))).To(Succeed())
+			return errors.New("banana")
+		}
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.Flush(ctx)).NotTo(Succeed())
+		close(release)
+		Expect(<-done).To(Equal([]string{"a"}))
+		Expect(keysOf(db)).To(Equal([]string{"a", "b"}))
+	})
+	It("does not modify writes seen by a running View", func() {
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.View(ctx, func(ctx context.Context, tx kv.Tx) error {
+			Expect(db.Put(ctx, bucketName, []byte("b"), []byte("b"))).To(Succeed())
+			Expect(db.Delete(ctx, bucketName, []byte("a"))).To(Succeed())
+			bucket, err := tx.Bucket(ctx, bucketName)
+			if err != nil {
+				return err
+			}
+			Expect(collectKeys(bucket.Iterator(), nil)).To(Equal([]string{"a"}))
+			return nil
+		})).To(Succeed())
+		Expect(keysOf(db)).To(Equal([]string{"b"}))
+	})
+	It("keeps all writes interleaved with views", func() {
+		var expected []string
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprintf("%02d", i)
+			Expect(db.Put(ctx, bucketName, []byte(key), []byte(key))).To(Succeed())
+			if i%3 == 0 {
+				Expect(db.Delete(ctx, bucketName, []byte(key))).To(Succeed())
+			} else {
+				expected = append(expected, key)
+			}
+			Expect(keysOf(db)).To(Equal(expected))
+		}
+		Expect(db.Flush(ctx)).To(Succeed())
+		Expect(keysOf(backend)).To(Equal(expected))
+	})
+	It("flushes synchronously if max pending is reached", func() {
+		db = kv.NewWriteBehindDB(backend, kv.WriteBehindOptions{
+			FlushInterval: time.Hour,
+			MaxPending:    2,
+		})
+		for _, key := range []string{"a", "b", "c"} {
+			Expect(db.Put(ctx, bucketName, []byte(key), []byte(key))).To(Succeed())
+		}
+		Expect(keysOf(backend)).To(Equal([]string{"a", "b"}))
+	})
+	It("flushes once flush size is reached", func() {
+		options.FlushSize = 2
+		db = kv.NewWriteBehindDB(backend, options)
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- db.Run(runCtx)
+		}()
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.Put(ctx, bucketName, []byte("b"), []byte("b"))).To(Succeed())
+		Eventually(func() []string { return keysOf(backend) }).
+			Should(Equal([]string{"a", "b"}))
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+	It("drains on close", func() {
+		Expect(db.Put(ctx, bucketName, []byte("a"), []byte("a"))).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+		Expect(keysOf(backend)).To(Equal([]string{"a"}))
+		err = db.Put(ctx, bucketName, []byte("b"), []byte("b"))
+		Expect(errors.Is(err, kv.ErrWriteBehindClosed)).To(BeTrue())
+	})
+})
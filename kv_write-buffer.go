@@ -0,0 +1,249 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+)
+
+// writeBuffer keeps puts and deletes of a single bucket in key order.
+// Deletes are stored as tombstones so they can hide keys of an underlying bucket.
+type writeBuffer struct {
+	entries []writeBufferEntry
+}
+
+type writeBufferEntry struct {
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+func newWriteBuffer() *writeBuffer {
+	return &writeBuffer{}
+}
+
+func (w *writeBuffer) Len() int {
+	return len(w.entries)
+}
+
+func (w *writeBuffer) search(key []byte) (int, bool) {
+	pos := sort.Search(len(w.entries), func(i int) bool {
+		return bytes.Compare(w.entries[i].key, key) >= 0
+	})
+	return pos, pos < len(w.entries) && bytes.Equal(w.entries[pos].key, key)
+}
+
+func (w *writeBuffer) set(entry writeBufferEntry) {
+	pos, found := w.search(entry.key)
+	if found {
+		w.entries[pos] = entry
+		return
+	}
+	w.entries = append(w.entries, writeBufferEntry{})
+	copy(w.entries[pos+1:], w.entries[pos:])
+	w.entries[pos] = entry
+}
+
+// Put stores a copy of key and value.
+func (w *writeBuffer) Put(key []byte, value []byte) {
+	w.set(writeBufferEntry{
+		key:   bytes.Clone(key),
+		value: bytes.Clone(value),
+	})
+}
+
+// Delete stores a tombstone for key.
+func (w *writeBuffer) Delete(key []byte) {
+	w.set(writeBufferEntry{
+		key:     bytes.Clone(key),
+		deleted: true,
+	})
+}
+
+// Get returns the buffered entry for key. found is false if the key was not written.
+func (w *writeBuffer) Get(key []byte) (entry writeBufferEntry, found bool) {
+	pos, found := w.search(key)
+	if !found {
+		return writeBufferEntry{}, false
+	}
+	return w.entries[pos], true
+}
+
+// Merge applies all entries of other on top of w.
+func (w *writeBuffer) Merge(other *writeBuffer) {
+	for _, entry := range other.entries {
+		w.set(entry)
+	}
+}
+
+// Clone returns an independent copy of w.
+func (w *writeBuffer) Clone() *writeBuffer {
+	return &writeBuffer{
+		entries: append([]writeBufferEntry{}, w.entries...),
+	}
+}
+
+// Apply writes all entries to the given bucket.
+func (w *writeBuffer) Apply(ctx context.Context, bucket Bucket) error {
+	for _, entry := range w.entries {
+		if entry.deleted {
+			if err := bucket.Delete(ctx, entry.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := bucket.Put(ctx, entry.key, entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Iterator returns an iterator over all entries including tombstones.
+func (w *writeBuffer) Iterator(reverse bool) Iterator {
+	return &writeBufferIterator{
+		buffer:  w,
+		reverse: reverse,
+	}
+}
+
+type writeBufferIterator struct {
+	buffer  *writeBuffer
+	reverse bool
+	entries []writeBufferEntry
+	pos     int
+}
+
+func (w *writeBufferIterator) Close() {}
+
+func (w *writeBufferIterator) Item() Item {
+	entry := w.entries[w.pos]
+	return &writeBufferItem{
+		byteItem: byteItem{key: entry.key, value: entry.value},
+		deleted:  entry.deleted,
+	}
+}
+
+func (w *writeBufferIterator) Next() {
+	w.pos++
+}
+
+func (w *writeBufferIterator) Valid() bool {
+	return w.pos < len(w.entries)
+}
+
+func (w *writeBufferIterator) Rewind() {
+	w.load()
+	w.pos = 0
+}
+
+func (w *writeBufferIterator) Seek(key []byte) {
+	w.load()
+	w.pos = sort.Search(len(w.entries), func(i int) bool {
+		if w.reverse {
+			return bytes.Compare(w.entries[i].key, key) <= 0
+		}
+		return bytes.Compare(w.entries[i].key, key) >= 0
+	})
+}
+
+// load takes a snapshot of the buffer, so writes during the iteration do not move the position.
+func (w *writeBufferIterator) load() {
+	w.entries = append([]writeBufferEntry{}, w.buffer.entries...)
+	if w.reverse {
+		for l, r := 0, len(w.entries)-1; l < r; l, r = l+1, r-1 {
+			w.entries[l], w.entries[r] = w.entries[r], w.entries[l]
+		}
+	}
+}
+
+// writeBufferItem is returned by writeBufferIterator and marks tombstones.
+type writeBufferItem struct {
+	byteItem
+	deleted bool
+}
+
+func (w *writeBufferItem) isDeleted() bool {
+	return w.deleted
+}
+
+// deletedItem is implemented by items that can represent a tombstone.
+type deletedItem interface {
+	isDeleted() bool
+}
+
+func isDeletedItem(item Item) bool {
+	deleted, ok := item.(deletedItem)
+	return ok && deleted.isDeleted()
+}
+
+// newBufferedBucket returns a read view of bucket with the given buffers on top,
+// the first buffer has the highest priority. bucket can be nil if it does not exist.
+// Put and Delete are passed to bucket.
+func newBufferedBucket(bucket Bucket, buffers ...*writeBuffer) Bucket {
+	return &bufferedBucket{
+		bucket:  bucket,
+		buffers: buffers,
+	}
+}
+
+type bufferedBucket struct {
+	bucket  Bucket
+	buffers []*writeBuffer
+}
+
+func (b *bufferedBucket) Put(ctx context.Context, key []byte, value []byte) error {
+	if b.bucket == nil {
+		return ErrBucketNotFound
+	}
+	return b.bucket.Put(ctx, key, value)
+}
+
+func (b *bufferedBucket) Get(ctx context.Context, key []byte) (Item, error) {
+	for _, buffer := range b.buffers {
+		if entry, found := buffer.Get(key); found {
+			if entry.deleted {
+				return NewByteItem(key, nil), nil
+			}
+			return NewByteItem(key, entry.value), nil
+		}
+	}
+	if b.bucket == nil {
+		return NewByteItem(key, nil), nil
+	}
+	return b.bucket.Get(ctx, key)
+}
+
+func (b *bufferedBucket) Delete(ctx context.Context, key []byte) error {
+	if b.bucket == nil {
+		return ErrBucketNotFound
+	}
+	return b.bucket.Delete(ctx, key)
+}
+
+func (b *bufferedBucket) Iterator() Iterator {
+	return b.iterator(false)
+}
+
+func (b *bufferedBucket) IteratorReverse() Iterator {
+	return b.iterator(true)
+}
+
+func (b *bufferedBucket) iterator(reverse bool) Iterator {
+	iterators := make([]Iterator, 0, len(b.buffers)+1)
+	for _, buffer := range b.buffers {
+		iterators = append(iterators, buffer.Iterator(reverse))
+	}
+	if b.bucket != nil {
+		if reverse {
+			iterators = append(iterators, b.bucket.IteratorReverse())
+		} else {
+			iterators = append(iterators, b.bucket.Iterator())
+		}
+	}
+	return newMergeIterator(reverse, iterators...)
+}
@@ -0,0 +1,603 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type BatchDB struct {
+	BatchStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	batchMutex       sync.RWMutex
+	batchArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	batchReturns struct {
+		result1 error
+	}
+	batchReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveStub        func() error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+	}
+	removeReturns struct {
+		result1 error
+	}
+	removeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StatsStub        func(context.Context) (*kv.Stats, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	StatsDetailedStub        func(context.Context) (*kv.Stats, error)
+	statsDetailedMutex       sync.RWMutex
+	statsDetailedArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsDetailedReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsDetailedReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	SyncStub        func() error
+	syncMutex       sync.RWMutex
+	syncArgsForCall []struct {
+	}
+	syncReturns struct {
+		result1 error
+	}
+	syncReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ViewStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	viewMutex       sync.RWMutex
+	viewArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	viewReturns struct {
+		result1 error
+	}
+	viewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *BatchDB) Batch(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.batchMutex.Lock()
+	ret, specificReturn := fake.batchReturnsOnCall[len(fake.batchArgsForCall)]
+	fake.batchArgsForCall = append(fake.batchArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.BatchStub
+	fakeReturns := fake.batchReturns
+	fake.recordInvocation("Batch", []interface{}{arg1, arg2})
+	fake.batchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) BatchCallCount() int {
+	fake.batchMutex.RLock()
+	defer fake.batchMutex.RUnlock()
+	return len(fake.batchArgsForCall)
+}
+
+func (fake *BatchDB) BatchCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.batchMutex.Lock()
+	defer fake.batchMutex.Unlock()
+	fake.BatchStub = stub
+}
+
+func (fake *BatchDB) BatchArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.batchMutex.RLock()
+	defer fake.batchMutex.RUnlock()
+	argsForCall := fake.batchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *BatchDB) BatchReturns(result1 error) {
+	fake.batchMutex.Lock()
+	defer fake.batchMutex.Unlock()
+	fake.BatchStub = nil
+	fake.batchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) BatchReturnsOnCall(i int, result1 error) {
+	fake.batchMutex.Lock()
+	defer fake.batchMutex.Unlock()
+	fake.BatchStub = nil
+	if fake.batchReturnsOnCall == nil {
+		fake.batchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.batchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *BatchDB) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *BatchDB) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) Remove() error {
+	fake.removeMutex.Lock()
+	ret, specificReturn := fake.removeReturnsOnCall[len(fake.removeArgsForCall)]
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+	}{})
+	stub := fake.RemoveStub
+	fakeReturns := fake.removeReturns
+	fake.recordInvocation("Remove", []interface{}{})
+	fake.removeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *BatchDB) RemoveCalls(stub func() error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = stub
+}
+
+func (fake *BatchDB) RemoveReturns(result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) RemoveReturnsOnCall(i int, result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	if fake.removeReturnsOnCall == nil {
+		fake.removeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) Stats(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsStub
+	fakeReturns := fake.statsReturns
+	fake.recordInvocation("Stats", []interface{}{arg1})
+	fake.statsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *BatchDB) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *BatchDB) StatsCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = stub
+}
+
+func (fake *BatchDB) StatsArgsForCall(i int) context.Context {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	argsForCall := fake.statsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *BatchDB) StatsReturns(result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BatchDB) StatsReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BatchDB) StatsDetailed(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsDetailedMutex.Lock()
+	ret, specificReturn := fake.statsDetailedReturnsOnCall[len(fake.statsDetailedArgsForCall)]
+	fake.statsDetailedArgsForCall = append(fake.statsDetailedArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsDetailedStub
+	fakeReturns := fake.statsDetailedReturns
+	fake.recordInvocation("StatsDetailed", []interface{}{arg1})
+	fake.statsDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *BatchDB) StatsDetailedCallCount() int {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	return len(fake.statsDetailedArgsForCall)
+}
+
+func (fake *BatchDB) StatsDetailedCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = stub
+}
+
+func (fake *BatchDB) StatsDetailedArgsForCall(i int) context.Context {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	argsForCall := fake.statsDetailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *BatchDB) StatsDetailedReturns(result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	fake.statsDetailedReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BatchDB) StatsDetailedReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	if fake.statsDetailedReturnsOnCall == nil {
+		fake.statsDetailedReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsDetailedReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BatchDB) Sync() error {
+	fake.syncMutex.Lock()
+	ret, specificReturn := fake.syncReturnsOnCall[len(fake.syncArgsForCall)]
+	fake.syncArgsForCall = append(fake.syncArgsForCall, struct {
+	}{})
+	stub := fake.SyncStub
+	fakeReturns := fake.syncReturns
+	fake.recordInvocation("Sync", []interface{}{})
+	fake.syncMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) SyncCallCount() int {
+	fake.syncMutex.RLock()
+	defer fake.syncMutex.RUnlock()
+	return len(fake.syncArgsForCall)
+}
+
+func (fake *BatchDB) SyncCalls(stub func() error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = stub
+}
+
+func (fake *BatchDB) SyncReturns(result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	fake.syncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) SyncReturnsOnCall(i int, result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	if fake.syncReturnsOnCall == nil {
+		fake.syncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.syncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) Update(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *BatchDB) UpdateCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *BatchDB) UpdateArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *BatchDB) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) View(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.viewMutex.Lock()
+	ret, specificReturn := fake.viewReturnsOnCall[len(fake.viewArgsForCall)]
+	fake.viewArgsForCall = append(fake.viewArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.ViewStub
+	fakeReturns := fake.viewReturns
+	fake.recordInvocation("View", []interface{}{arg1, arg2})
+	fake.viewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BatchDB) ViewCallCount() int {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	return len(fake.viewArgsForCall)
+}
+
+func (fake *BatchDB) ViewCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = stub
+}
+
+func (fake *BatchDB) ViewArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	argsForCall := fake.viewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *BatchDB) ViewReturns(result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	fake.viewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) ViewReturnsOnCall(i int, result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	if fake.viewReturnsOnCall == nil {
+		fake.viewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.viewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BatchDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *BatchDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.BatchDB = new(BatchDB)
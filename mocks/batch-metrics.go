@@ -0,0 +1,102 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type BatchMetrics struct {
+	DbBatchFallbackIncStub        func()
+	dbBatchFallbackIncMutex       sync.RWMutex
+	dbBatchFallbackIncArgsForCall []struct {
+	}
+	DbBatchSizeObserveStub        func(int)
+	dbBatchSizeObserveMutex       sync.RWMutex
+	dbBatchSizeObserveArgsForCall []struct {
+		arg1 int
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *BatchMetrics) DbBatchFallbackInc() {
+	fake.dbBatchFallbackIncMutex.Lock()
+	fake.dbBatchFallbackIncArgsForCall = append(fake.dbBatchFallbackIncArgsForCall, struct {
+	}{})
+	stub := fake.DbBatchFallbackIncStub
+	fake.recordInvocation("DbBatchFallbackInc", []interface{}{})
+	fake.dbBatchFallbackIncMutex.Unlock()
+	if stub != nil {
+		fake.DbBatchFallbackIncStub()
+	}
+}
+
+func (fake *BatchMetrics) DbBatchFallbackIncCallCount() int {
+	fake.dbBatchFallbackIncMutex.RLock()
+	defer fake.dbBatchFallbackIncMutex.RUnlock()
+	return len(fake.dbBatchFallbackIncArgsForCall)
+}
+
+func (fake *BatchMetrics) DbBatchFallbackIncCalls(stub func()) {
+	fake.dbBatchFallbackIncMutex.Lock()
+	defer fake.dbBatchFallbackIncMutex.Unlock()
+	fake.DbBatchFallbackIncStub = stub
+}
+
+func (fake *BatchMetrics) DbBatchSizeObserve(arg1 int) {
+	fake.dbBatchSizeObserveMutex.Lock()
+	fake.dbBatchSizeObserveArgsForCall = append(fake.dbBatchSizeObserveArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.DbBatchSizeObserveStub
+	fake.recordInvocation("DbBatchSizeObserve", []interface{}{arg1})
+	fake.dbBatchSizeObserveMutex.Unlock()
+	if stub != nil {
+		fake.DbBatchSizeObserveStub(arg1)
+	}
+}
+
+func (fake *BatchMetrics) DbBatchSizeObserveCallCount() int {
+	fake.dbBatchSizeObserveMutex.RLock()
+	defer fake.dbBatchSizeObserveMutex.RUnlock()
+	return len(fake.dbBatchSizeObserveArgsForCall)
+}
+
+func (fake *BatchMetrics) DbBatchSizeObserveCalls(stub func(int)) {
+	fake.dbBatchSizeObserveMutex.Lock()
+	defer fake.dbBatchSizeObserveMutex.Unlock()
+	fake.DbBatchSizeObserveStub = stub
+}
+
+func (fake *BatchMetrics) DbBatchSizeObserveArgsForCall(i int) int {
+	fake.dbBatchSizeObserveMutex.RLock()
+	defer fake.dbBatchSizeObserveMutex.RUnlock()
+	argsForCall := fake.dbBatchSizeObserveArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *BatchMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *BatchMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.BatchMetrics = new(BatchMetrics)
@@ -0,0 +1,93 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type CacheMetrics struct {
+	DbCacheHitIncStub        func()
+	dbCacheHitIncMutex       sync.RWMutex
+	dbCacheHitIncArgsForCall []struct {
+	}
+	DbCacheMissIncStub        func()
+	dbCacheMissIncMutex       sync.RWMutex
+	dbCacheMissIncArgsForCall []struct {
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CacheMetrics) DbCacheHitInc() {
+	fake.dbCacheHitIncMutex.Lock()
+	fake.dbCacheHitIncArgsForCall = append(fake.dbCacheHitIncArgsForCall, struct {
+	}{})
+	stub := fake.DbCacheHitIncStub
+	fake.recordInvocation("DbCacheHitInc", []interface{}{})
+	fake.dbCacheHitIncMutex.Unlock()
+	if stub != nil {
+		fake.DbCacheHitIncStub()
+	}
+}
+
+func (fake *CacheMetrics) DbCacheHitIncCallCount() int {
+	fake.dbCacheHitIncMutex.RLock()
+	defer fake.dbCacheHitIncMutex.RUnlock()
+	return len(fake.dbCacheHitIncArgsForCall)
+}
+
+func (fake *CacheMetrics) DbCacheHitIncCalls(stub func()) {
+	fake.dbCacheHitIncMutex.Lock()
+	defer fake.dbCacheHitIncMutex.Unlock()
+	fake.DbCacheHitIncStub = stub
+}
+
+func (fake *CacheMetrics) DbCacheMissInc() {
+	fake.dbCacheMissIncMutex.Lock()
+	fake.dbCacheMissIncArgsForCall = append(fake.dbCacheMissIncArgsForCall, struct {
+	}{})
+	stub := fake.DbCacheMissIncStub
+	fake.recordInvocation("DbCacheMissInc", []interface{}{})
+	fake.dbCacheMissIncMutex.Unlock()
+	if stub != nil {
+		fake.DbCacheMissIncStub()
+	}
+}
+
+func (fake *CacheMetrics) DbCacheMissIncCallCount() int {
+	fake.dbCacheMissIncMutex.RLock()
+	defer fake.dbCacheMissIncMutex.RUnlock()
+	return len(fake.dbCacheMissIncArgsForCall)
+}
+
+func (fake *CacheMetrics) DbCacheMissIncCalls(stub func()) {
+	fake.dbCacheMissIncMutex.Lock()
+	defer fake.dbCacheMissIncMutex.Unlock()
+	fake.DbCacheMissIncStub = stub
+}
+
+func (fake *CacheMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CacheMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.CacheMetrics = new(CacheMetrics)
@@ -0,0 +1,194 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type ChunkedWriter struct {
+	CheckpointStub        func(context.Context) ([]byte, error)
+	checkpointMutex       sync.RWMutex
+	checkpointArgsForCall []struct {
+		arg1 context.Context
+	}
+	checkpointReturns struct {
+		result1 []byte
+		result2 error
+	}
+	checkpointReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	WriteStub        func(context.Context, <-chan kv.ChunkedWriteOperation) (*kv.ChunkedWriteSummary, error)
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		arg1 context.Context
+		arg2 <-chan kv.ChunkedWriteOperation
+	}
+	writeReturns struct {
+		result1 *kv.ChunkedWriteSummary
+		result2 error
+	}
+	writeReturnsOnCall map[int]struct {
+		result1 *kv.ChunkedWriteSummary
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ChunkedWriter) Checkpoint(arg1 context.Context) ([]byte, error) {
+	fake.checkpointMutex.Lock()
+	ret, specificReturn := fake.checkpointReturnsOnCall[len(fake.checkpointArgsForCall)]
+	fake.checkpointArgsForCall = append(fake.checkpointArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CheckpointStub
+	fakeReturns := fake.checkpointReturns
+	fake.recordInvocation("Checkpoint", []interface{}{arg1})
+	fake.checkpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChunkedWriter) CheckpointCallCount() int {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return len(fake.checkpointArgsForCall)
+}
+
+func (fake *ChunkedWriter) CheckpointCalls(stub func(context.Context) ([]byte, error)) {
+	fake.checkpointMutex.Lock()
+	defer fake.checkpointMutex.Unlock()
+	fake.CheckpointStub = stub
+}
+
+func (fake *ChunkedWriter) CheckpointArgsForCall(i int) context.Context {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	argsForCall := fake.checkpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChunkedWriter) CheckpointReturns(result1 []byte, result2 error) {
+	fake.checkpointMutex.Lock()
+	defer fake.checkpointMutex.Unlock()
+	fake.CheckpointStub = nil
+	fake.checkpointReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChunkedWriter) CheckpointReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.checkpointMutex.Lock()
+	defer fake.checkpointMutex.Unlock()
+	fake.CheckpointStub = nil
+	if fake.checkpointReturnsOnCall == nil {
+		fake.checkpointReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.checkpointReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChunkedWriter) Write(arg1 context.Context, arg2 <-chan kv.ChunkedWriteOperation) (*kv.ChunkedWriteSummary, error) {
+	fake.writeMutex.Lock()
+	ret, specificReturn := fake.writeReturnsOnCall[len(fake.writeArgsForCall)]
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		arg1 context.Context
+		arg2 <-chan kv.ChunkedWriteOperation
+	}{arg1, arg2})
+	stub := fake.WriteStub
+	fakeReturns := fake.writeReturns
+	fake.recordInvocation("Write", []interface{}{arg1, arg2})
+	fake.writeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChunkedWriter) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *ChunkedWriter) WriteCalls(stub func(context.Context, <-chan kv.ChunkedWriteOperation) (*kv.ChunkedWriteSummary, error)) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = stub
+}
+
+func (fake *ChunkedWriter) WriteArgsForCall(i int) (context.Context, <-chan kv.ChunkedWriteOperation) {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	argsForCall := fake.writeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChunkedWriter) WriteReturns(result1 *kv.ChunkedWriteSummary, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 *kv.ChunkedWriteSummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChunkedWriter) WriteReturnsOnCall(i int, result1 *kv.ChunkedWriteSummary, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	if fake.writeReturnsOnCall == nil {
+		fake.writeReturnsOnCall = make(map[int]struct {
+			result1 *kv.ChunkedWriteSummary
+			result2 error
+		})
+	}
+	fake.writeReturnsOnCall[i] = struct {
+		result1 *kv.ChunkedWriteSummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChunkedWriter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ChunkedWriter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.ChunkedWriter = new(ChunkedWriter)
@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type CompressionMetrics struct {
+	DbCompressionBytesAddStub        func(int, int)
+	dbCompressionBytesAddMutex       sync.RWMutex
+	dbCompressionBytesAddArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	DbCompressionRatioObserveStub        func(float64)
+	dbCompressionRatioObserveMutex       sync.RWMutex
+	dbCompressionRatioObserveArgsForCall []struct {
+		arg1 float64
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CompressionMetrics) DbCompressionBytesAdd(arg1 int, arg2 int) {
+	fake.dbCompressionBytesAddMutex.Lock()
+	fake.dbCompressionBytesAddArgsForCall = append(fake.dbCompressionBytesAddArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.DbCompressionBytesAddStub
+	fake.recordInvocation("DbCompressionBytesAdd", []interface{}{arg1, arg2})
+	fake.dbCompressionBytesAddMutex.Unlock()
+	if stub != nil {
+		fake.DbCompressionBytesAddStub(arg1, arg2)
+	}
+}
+
+func (fake *CompressionMetrics) DbCompressionBytesAddCallCount() int {
+	fake.dbCompressionBytesAddMutex.RLock()
+	defer fake.dbCompressionBytesAddMutex.RUnlock()
+	return len(fake.dbCompressionBytesAddArgsForCall)
+}
+
+func (fake *CompressionMetrics) DbCompressionBytesAddCalls(stub func(int, int)) {
+	fake.dbCompressionBytesAddMutex.Lock()
+	defer fake.dbCompressionBytesAddMutex.Unlock()
+	fake.DbCompressionBytesAddStub = stub
+}
+
+func (fake *CompressionMetrics) DbCompressionBytesAddArgsForCall(i int) (int, int) {
+	fake.dbCompressionBytesAddMutex.RLock()
+	defer fake.dbCompressionBytesAddMutex.RUnlock()
+	argsForCall := fake.dbCompressionBytesAddArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CompressionMetrics) DbCompressionRatioObserve(arg1 float64) {
+	fake.dbCompressionRatioObserveMutex.Lock()
+	fake.dbCompressionRatioObserveArgsForCall = append(fake.dbCompressionRatioObserveArgsForCall, struct {
+		arg1 float64
+	}{arg1})
+	stub := fake.DbCompressionRatioObserveStub
+	fake.recordInvocation("DbCompressionRatioObserve", []interface{}{arg1})
+	fake.dbCompressionRatioObserveMutex.Unlock()
+	if stub != nil {
+		fake.DbCompressionRatioObserveStub(arg1)
+	}
+}
+
+func (fake *CompressionMetrics) DbCompressionRatioObserveCallCount() int {
+	fake.dbCompressionRatioObserveMutex.RLock()
+	defer fake.dbCompressionRatioObserveMutex.RUnlock()
+	return len(fake.dbCompressionRatioObserveArgsForCall)
+}
+
+func (fake *CompressionMetrics) DbCompressionRatioObserveCalls(stub func(float64)) {
+	fake.dbCompressionRatioObserveMutex.Lock()
+	defer fake.dbCompressionRatioObserveMutex.Unlock()
+	fake.DbCompressionRatioObserveStub = stub
+}
+
+func (fake *CompressionMetrics) DbCompressionRatioObserveArgsForCall(i int) float64 {
+	fake.dbCompressionRatioObserveMutex.RLock()
+	defer fake.dbCompressionRatioObserveMutex.RUnlock()
+	argsForCall := fake.dbCompressionRatioObserveArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompressionMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CompressionMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.CompressionMetrics = new(CompressionMetrics)
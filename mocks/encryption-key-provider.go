@@ -0,0 +1,194 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type EncryptionKeyProvider struct {
+	CurrentKeyStub        func(context.Context) (*kv.EncryptionKey, error)
+	currentKeyMutex       sync.RWMutex
+	currentKeyArgsForCall []struct {
+		arg1 context.Context
+	}
+	currentKeyReturns struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}
+	currentKeyReturnsOnCall map[int]struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}
+	KeyStub        func(context.Context, string) (*kv.EncryptionKey, error)
+	keyMutex       sync.RWMutex
+	keyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	keyReturns struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}
+	keyReturnsOnCall map[int]struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *EncryptionKeyProvider) CurrentKey(arg1 context.Context) (*kv.EncryptionKey, error) {
+	fake.currentKeyMutex.Lock()
+	ret, specificReturn := fake.currentKeyReturnsOnCall[len(fake.currentKeyArgsForCall)]
+	fake.currentKeyArgsForCall = append(fake.currentKeyArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CurrentKeyStub
+	fakeReturns := fake.currentKeyReturns
+	fake.recordInvocation("CurrentKey", []interface{}{arg1})
+	fake.currentKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EncryptionKeyProvider) CurrentKeyCallCount() int {
+	fake.currentKeyMutex.RLock()
+	defer fake.currentKeyMutex.RUnlock()
+	return len(fake.currentKeyArgsForCall)
+}
+
+func (fake *EncryptionKeyProvider) CurrentKeyCalls(stub func(context.Context) (*kv.EncryptionKey, error)) {
+	fake.currentKeyMutex.Lock()
+	defer fake.currentKeyMutex.Unlock()
+	fake.CurrentKeyStub = stub
+}
+
+func (fake *EncryptionKeyProvider) CurrentKeyArgsForCall(i int) context.Context {
+	fake.currentKeyMutex.RLock()
+	defer fake.currentKeyMutex.RUnlock()
+	argsForCall := fake.currentKeyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *EncryptionKeyProvider) CurrentKeyReturns(result1 *kv.EncryptionKey, result2 error) {
+	fake.currentKeyMutex.Lock()
+	defer fake.currentKeyMutex.Unlock()
+	fake.CurrentKeyStub = nil
+	fake.currentKeyReturns = struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EncryptionKeyProvider) CurrentKeyReturnsOnCall(i int, result1 *kv.EncryptionKey, result2 error) {
+	fake.currentKeyMutex.Lock()
+	defer fake.currentKeyMutex.Unlock()
+	fake.CurrentKeyStub = nil
+	if fake.currentKeyReturnsOnCall == nil {
+		fake.currentKeyReturnsOnCall = make(map[int]struct {
+			result1 *kv.EncryptionKey
+			result2 error
+		})
+	}
+	fake.currentKeyReturnsOnCall[i] = struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EncryptionKeyProvider) Key(arg1 context.Context, arg2 string) (*kv.EncryptionKey, error) {
+	fake.keyMutex.Lock()
+	ret, specificReturn := fake.keyReturnsOnCall[len(fake.keyArgsForCall)]
+	fake.keyArgsForCall = append(fake.keyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.KeyStub
+	fakeReturns := fake.keyReturns
+	fake.recordInvocation("Key", []interface{}{arg1, arg2})
+	fake.keyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EncryptionKeyProvider) KeyCallCount() int {
+	fake.keyMutex.RLock()
+	defer fake.keyMutex.RUnlock()
+	return len(fake.keyArgsForCall)
+}
+
+func (fake *EncryptionKeyProvider) KeyCalls(stub func(context.Context, string) (*kv.EncryptionKey, error)) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = stub
+}
+
+func (fake *EncryptionKeyProvider) KeyArgsForCall(i int) (context.Context, string) {
+	fake.keyMutex.RLock()
+	defer fake.keyMutex.RUnlock()
+	argsForCall := fake.keyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *EncryptionKeyProvider) KeyReturns(result1 *kv.EncryptionKey, result2 error) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = nil
+	fake.keyReturns = struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EncryptionKeyProvider) KeyReturnsOnCall(i int, result1 *kv.EncryptionKey, result2 error) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = nil
+	if fake.keyReturnsOnCall == nil {
+		fake.keyReturnsOnCall = make(map[int]struct {
+			result1 *kv.EncryptionKey
+			result2 error
+		})
+	}
+	fake.keyReturnsOnCall[i] = struct {
+		result1 *kv.EncryptionKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EncryptionKeyProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *EncryptionKeyProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.EncryptionKeyProvider = new(EncryptionKeyProvider)
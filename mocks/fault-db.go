@@ -0,0 +1,694 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type FaultDB struct {
+	AddRuleStub        func(kv.FaultRule)
+	addRuleMutex       sync.RWMutex
+	addRuleArgsForCall []struct {
+		arg1 kv.FaultRule
+	}
+	ClearRulesStub        func()
+	clearRulesMutex       sync.RWMutex
+	clearRulesArgsForCall []struct {
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InjectedStub        func() int
+	injectedMutex       sync.RWMutex
+	injectedArgsForCall []struct {
+	}
+	injectedReturns struct {
+		result1 int
+	}
+	injectedReturnsOnCall map[int]struct {
+		result1 int
+	}
+	RemoveStub        func() error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+	}
+	removeReturns struct {
+		result1 error
+	}
+	removeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SeedStub        func(uint64)
+	seedMutex       sync.RWMutex
+	seedArgsForCall []struct {
+		arg1 uint64
+	}
+	StatsStub        func(context.Context) (*kv.Stats, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	StatsDetailedStub        func(context.Context) (*kv.Stats, error)
+	statsDetailedMutex       sync.RWMutex
+	statsDetailedArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsDetailedReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsDetailedReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	SyncStub        func() error
+	syncMutex       sync.RWMutex
+	syncArgsForCall []struct {
+	}
+	syncReturns struct {
+		result1 error
+	}
+	syncReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ViewStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	viewMutex       sync.RWMutex
+	viewArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	viewReturns struct {
+		result1 error
+	}
+	viewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FaultDB) AddRule(arg1 kv.FaultRule) {
+	fake.addRuleMutex.Lock()
+	fake.addRuleArgsForCall = append(fake.addRuleArgsForCall, struct {
+		arg1 kv.FaultRule
+	}{arg1})
+	stub := fake.AddRuleStub
+	fake.recordInvocation("AddRule", []interface{}{arg1})
+	fake.addRuleMutex.Unlock()
+	if stub != nil {
+		fake.AddRuleStub(arg1)
+	}
+}
+
+func (fake *FaultDB) AddRuleCallCount() int {
+	fake.addRuleMutex.RLock()
+	defer fake.addRuleMutex.RUnlock()
+	return len(fake.addRuleArgsForCall)
+}
+
+func (fake *FaultDB) AddRuleCalls(stub func(kv.FaultRule)) {
+	fake.addRuleMutex.Lock()
+	defer fake.addRuleMutex.Unlock()
+	fake.AddRuleStub = stub
+}
+
+func (fake *FaultDB) AddRuleArgsForCall(i int) kv.FaultRule {
+	fake.addRuleMutex.RLock()
+	defer fake.addRuleMutex.RUnlock()
+	argsForCall := fake.addRuleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FaultDB) ClearRules() {
+	fake.clearRulesMutex.Lock()
+	fake.clearRulesArgsForCall = append(fake.clearRulesArgsForCall, struct {
+	}{})
+	stub := fake.ClearRulesStub
+	fake.recordInvocation("ClearRules", []interface{}{})
+	fake.clearRulesMutex.Unlock()
+	if stub != nil {
+		fake.ClearRulesStub()
+	}
+}
+
+func (fake *FaultDB) ClearRulesCallCount() int {
+	fake.clearRulesMutex.RLock()
+	defer fake.clearRulesMutex.RUnlock()
+	return len(fake.clearRulesArgsForCall)
+}
+
+func (fake *FaultDB) ClearRulesCalls(stub func()) {
+	fake.clearRulesMutex.Lock()
+	defer fake.clearRulesMutex.Unlock()
+	fake.ClearRulesStub = stub
+}
+
+func (fake *FaultDB) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FaultDB) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FaultDB) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) Injected() int {
+	fake.injectedMutex.Lock()
+	ret, specificReturn := fake.injectedReturnsOnCall[len(fake.injectedArgsForCall)]
+	fake.injectedArgsForCall = append(fake.injectedArgsForCall, struct {
+	}{})
+	stub := fake.InjectedStub
+	fakeReturns := fake.injectedReturns
+	fake.recordInvocation("Injected", []interface{}{})
+	fake.injectedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) InjectedCallCount() int {
+	fake.injectedMutex.RLock()
+	defer fake.injectedMutex.RUnlock()
+	return len(fake.injectedArgsForCall)
+}
+
+func (fake *FaultDB) InjectedCalls(stub func() int) {
+	fake.injectedMutex.Lock()
+	defer fake.injectedMutex.Unlock()
+	fake.InjectedStub = stub
+}
+
+func (fake *FaultDB) InjectedReturns(result1 int) {
+	fake.injectedMutex.Lock()
+	defer fake.injectedMutex.Unlock()
+	fake.InjectedStub = nil
+	fake.injectedReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FaultDB) InjectedReturnsOnCall(i int, result1 int) {
+	fake.injectedMutex.Lock()
+	defer fake.injectedMutex.Unlock()
+	fake.InjectedStub = nil
+	if fake.injectedReturnsOnCall == nil {
+		fake.injectedReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.injectedReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FaultDB) Remove() error {
+	fake.removeMutex.Lock()
+	ret, specificReturn := fake.removeReturnsOnCall[len(fake.removeArgsForCall)]
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+	}{})
+	stub := fake.RemoveStub
+	fakeReturns := fake.removeReturns
+	fake.recordInvocation("Remove", []interface{}{})
+	fake.removeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *FaultDB) RemoveCalls(stub func() error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = stub
+}
+
+func (fake *FaultDB) RemoveReturns(result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) RemoveReturnsOnCall(i int, result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	if fake.removeReturnsOnCall == nil {
+		fake.removeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) Seed(arg1 uint64) {
+	fake.seedMutex.Lock()
+	fake.seedArgsForCall = append(fake.seedArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.SeedStub
+	fake.recordInvocation("Seed", []interface{}{arg1})
+	fake.seedMutex.Unlock()
+	if stub != nil {
+		fake.SeedStub(arg1)
+	}
+}
+
+func (fake *FaultDB) SeedCallCount() int {
+	fake.seedMutex.RLock()
+	defer fake.seedMutex.RUnlock()
+	return len(fake.seedArgsForCall)
+}
+
+func (fake *FaultDB) SeedCalls(stub func(uint64)) {
+	fake.seedMutex.Lock()
+	defer fake.seedMutex.Unlock()
+	fake.SeedStub = stub
+}
+
+func (fake *FaultDB) SeedArgsForCall(i int) uint64 {
+	fake.seedMutex.RLock()
+	defer fake.seedMutex.RUnlock()
+	argsForCall := fake.seedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FaultDB) Stats(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsStub
+	fakeReturns := fake.statsReturns
+	fake.recordInvocation("Stats", []interface{}{arg1})
+	fake.statsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FaultDB) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *FaultDB) StatsCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = stub
+}
+
+func (fake *FaultDB) StatsArgsForCall(i int) context.Context {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	argsForCall := fake.statsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FaultDB) StatsReturns(result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FaultDB) StatsReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FaultDB) StatsDetailed(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsDetailedMutex.Lock()
+	ret, specificReturn := fake.statsDetailedReturnsOnCall[len(fake.statsDetailedArgsForCall)]
+	fake.statsDetailedArgsForCall = append(fake.statsDetailedArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsDetailedStub
+	fakeReturns := fake.statsDetailedReturns
+	fake.recordInvocation("StatsDetailed", []interface{}{arg1})
+	fake.statsDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FaultDB) StatsDetailedCallCount() int {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	return len(fake.statsDetailedArgsForCall)
+}
+
+func (fake *FaultDB) StatsDetailedCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = stub
+}
+
+func (fake *FaultDB) StatsDetailedArgsForCall(i int) context.Context {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	argsForCall := fake.statsDetailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FaultDB) StatsDetailedReturns(result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	fake.statsDetailedReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FaultDB) StatsDetailedReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	if fake.statsDetailedReturnsOnCall == nil {
+		fake.statsDetailedReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsDetailedReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FaultDB) Sync() error {
+	fake.syncMutex.Lock()
+	ret, specificReturn := fake.syncReturnsOnCall[len(fake.syncArgsForCall)]
+	fake.syncArgsForCall = append(fake.syncArgsForCall, struct {
+	}{})
+	stub := fake.SyncStub
+	fakeReturns := fake.syncReturns
+	fake.recordInvocation("Sync", []interface{}{})
+	fake.syncMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) SyncCallCount() int {
+	fake.syncMutex.RLock()
+	defer fake.syncMutex.RUnlock()
+	return len(fake.syncArgsForCall)
+}
+
+func (fake *FaultDB) SyncCalls(stub func() error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = stub
+}
+
+func (fake *FaultDB) SyncReturns(result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	fake.syncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) SyncReturnsOnCall(i int, result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	if fake.syncReturnsOnCall == nil {
+		fake.syncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.syncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) Update(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FaultDB) UpdateCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FaultDB) UpdateArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FaultDB) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) View(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.viewMutex.Lock()
+	ret, specificReturn := fake.viewReturnsOnCall[len(fake.viewArgsForCall)]
+	fake.viewArgsForCall = append(fake.viewArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.ViewStub
+	fakeReturns := fake.viewReturns
+	fake.recordInvocation("View", []interface{}{arg1, arg2})
+	fake.viewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FaultDB) ViewCallCount() int {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	return len(fake.viewArgsForCall)
+}
+
+func (fake *FaultDB) ViewCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = stub
+}
+
+func (fake *FaultDB) ViewArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	argsForCall := fake.viewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FaultDB) ViewReturns(result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	fake.viewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) ViewReturnsOnCall(i int, result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	if fake.viewReturnsOnCall == nil {
+		fake.viewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.viewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FaultDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FaultDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.FaultDB = new(FaultDB)
@@ -0,0 +1,186 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type IntegrityChecksum struct {
+	SizeStub        func() int
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+	}
+	sizeReturns struct {
+		result1 int
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 int
+	}
+	SumStub        func(kv.BucketName, []byte, []byte) []byte
+	sumMutex       sync.RWMutex
+	sumArgsForCall []struct {
+		arg1 kv.BucketName
+		arg2 []byte
+		arg3 []byte
+	}
+	sumReturns struct {
+		result1 []byte
+	}
+	sumReturnsOnCall map[int]struct {
+		result1 []byte
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *IntegrityChecksum) Size() int {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+	}{})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *IntegrityChecksum) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *IntegrityChecksum) SizeCalls(stub func() int) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *IntegrityChecksum) SizeReturns(result1 int) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *IntegrityChecksum) SizeReturnsOnCall(i int, result1 int) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *IntegrityChecksum) Sum(arg1 kv.BucketName, arg2 []byte, arg3 []byte) []byte {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.sumMutex.Lock()
+	ret, specificReturn := fake.sumReturnsOnCall[len(fake.sumArgsForCall)]
+	fake.sumArgsForCall = append(fake.sumArgsForCall, struct {
+		arg1 kv.BucketName
+		arg2 []byte
+		arg3 []byte
+	}{arg1, arg2Copy, arg3Copy})
+	stub := fake.SumStub
+	fakeReturns := fake.sumReturns
+	fake.recordInvocation("Sum", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.sumMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *IntegrityChecksum) SumCallCount() int {
+	fake.sumMutex.RLock()
+	defer fake.sumMutex.RUnlock()
+	return len(fake.sumArgsForCall)
+}
+
+func (fake *IntegrityChecksum) SumCalls(stub func(kv.BucketName, []byte, []byte) []byte) {
+	fake.sumMutex.Lock()
+	defer fake.sumMutex.Unlock()
+	fake.SumStub = stub
+}
+
+func (fake *IntegrityChecksum) SumArgsForCall(i int) (kv.BucketName, []byte, []byte) {
+	fake.sumMutex.RLock()
+	defer fake.sumMutex.RUnlock()
+	argsForCall := fake.sumArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *IntegrityChecksum) SumReturns(result1 []byte) {
+	fake.sumMutex.Lock()
+	defer fake.sumMutex.Unlock()
+	fake.SumStub = nil
+	fake.sumReturns = struct {
+		result1 []byte
+	}{result1}
+}
+
+func (fake *IntegrityChecksum) SumReturnsOnCall(i int, result1 []byte) {
+	fake.sumMutex.Lock()
+	defer fake.sumMutex.Unlock()
+	fake.SumStub = nil
+	if fake.sumReturnsOnCall == nil {
+		fake.sumReturnsOnCall = make(map[int]struct {
+			result1 []byte
+		})
+	}
+	fake.sumReturnsOnCall[i] = struct {
+		result1 []byte
+	}{result1}
+}
+
+func (fake *IntegrityChecksum) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *IntegrityChecksum) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.IntegrityChecksum = new(IntegrityChecksum)
@@ -0,0 +1,834 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type MirrorDB struct {
+	CatchUpStub        func(context.Context) (int, error)
+	catchUpMutex       sync.RWMutex
+	catchUpArgsForCall []struct {
+		arg1 context.Context
+	}
+	catchUpReturns struct {
+		result1 int
+		result2 error
+	}
+	catchUpReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PositionStub        func(context.Context) (uint64, error)
+	positionMutex       sync.RWMutex
+	positionArgsForCall []struct {
+		arg1 context.Context
+	}
+	positionReturns struct {
+		result1 uint64
+		result2 error
+	}
+	positionReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	RemoveStub        func() error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+	}
+	removeReturns struct {
+		result1 error
+	}
+	removeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RunStub        func(context.Context) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 context.Context
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StatsStub        func(context.Context) (*kv.Stats, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	StatsDetailedStub        func(context.Context) (*kv.Stats, error)
+	statsDetailedMutex       sync.RWMutex
+	statsDetailedArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsDetailedReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsDetailedReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	SyncStub        func() error
+	syncMutex       sync.RWMutex
+	syncArgsForCall []struct {
+	}
+	syncReturns struct {
+		result1 error
+	}
+	syncReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VerifyStub        func(context.Context, ...kv.BucketName) ([]kv.MirrorDivergence, error)
+	verifyMutex       sync.RWMutex
+	verifyArgsForCall []struct {
+		arg1 context.Context
+		arg2 []kv.BucketName
+	}
+	verifyReturns struct {
+		result1 []kv.MirrorDivergence
+		result2 error
+	}
+	verifyReturnsOnCall map[int]struct {
+		result1 []kv.MirrorDivergence
+		result2 error
+	}
+	ViewStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	viewMutex       sync.RWMutex
+	viewArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	viewReturns struct {
+		result1 error
+	}
+	viewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *MirrorDB) CatchUp(arg1 context.Context) (int, error) {
+	fake.catchUpMutex.Lock()
+	ret, specificReturn := fake.catchUpReturnsOnCall[len(fake.catchUpArgsForCall)]
+	fake.catchUpArgsForCall = append(fake.catchUpArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CatchUpStub
+	fakeReturns := fake.catchUpReturns
+	fake.recordInvocation("CatchUp", []interface{}{arg1})
+	fake.catchUpMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MirrorDB) CatchUpCallCount() int {
+	fake.catchUpMutex.RLock()
+	defer fake.catchUpMutex.RUnlock()
+	return len(fake.catchUpArgsForCall)
+}
+
+func (fake *MirrorDB) CatchUpCalls(stub func(context.Context) (int, error)) {
+	fake.catchUpMutex.Lock()
+	defer fake.catchUpMutex.Unlock()
+	fake.CatchUpStub = stub
+}
+
+func (fake *MirrorDB) CatchUpArgsForCall(i int) context.Context {
+	fake.catchUpMutex.RLock()
+	defer fake.catchUpMutex.RUnlock()
+	argsForCall := fake.catchUpArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorDB) CatchUpReturns(result1 int, result2 error) {
+	fake.catchUpMutex.Lock()
+	defer fake.catchUpMutex.Unlock()
+	fake.CatchUpStub = nil
+	fake.catchUpReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) CatchUpReturnsOnCall(i int, result1 int, result2 error) {
+	fake.catchUpMutex.Lock()
+	defer fake.catchUpMutex.Unlock()
+	fake.CatchUpStub = nil
+	if fake.catchUpReturnsOnCall == nil {
+		fake.catchUpReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.catchUpReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *MirrorDB) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *MirrorDB) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Position(arg1 context.Context) (uint64, error) {
+	fake.positionMutex.Lock()
+	ret, specificReturn := fake.positionReturnsOnCall[len(fake.positionArgsForCall)]
+	fake.positionArgsForCall = append(fake.positionArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.PositionStub
+	fakeReturns := fake.positionReturns
+	fake.recordInvocation("Position", []interface{}{arg1})
+	fake.positionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MirrorDB) PositionCallCount() int {
+	fake.positionMutex.RLock()
+	defer fake.positionMutex.RUnlock()
+	return len(fake.positionArgsForCall)
+}
+
+func (fake *MirrorDB) PositionCalls(stub func(context.Context) (uint64, error)) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = stub
+}
+
+func (fake *MirrorDB) PositionArgsForCall(i int) context.Context {
+	fake.positionMutex.RLock()
+	defer fake.positionMutex.RUnlock()
+	argsForCall := fake.positionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorDB) PositionReturns(result1 uint64, result2 error) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = nil
+	fake.positionReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) PositionReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = nil
+	if fake.positionReturnsOnCall == nil {
+		fake.positionReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.positionReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) Remove() error {
+	fake.removeMutex.Lock()
+	ret, specificReturn := fake.removeReturnsOnCall[len(fake.removeArgsForCall)]
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+	}{})
+	stub := fake.RemoveStub
+	fakeReturns := fake.removeReturns
+	fake.recordInvocation("Remove", []interface{}{})
+	fake.removeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *MirrorDB) RemoveCalls(stub func() error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = stub
+}
+
+func (fake *MirrorDB) RemoveReturns(result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) RemoveReturnsOnCall(i int, result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	if fake.removeReturnsOnCall == nil {
+		fake.removeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Run(arg1 context.Context) error {
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *MirrorDB) RunCalls(stub func(context.Context) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *MirrorDB) RunArgsForCall(i int) context.Context {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorDB) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Stats(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsStub
+	fakeReturns := fake.statsReturns
+	fake.recordInvocation("Stats", []interface{}{arg1})
+	fake.statsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MirrorDB) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *MirrorDB) StatsCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = stub
+}
+
+func (fake *MirrorDB) StatsArgsForCall(i int) context.Context {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	argsForCall := fake.statsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorDB) StatsReturns(result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) StatsReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) StatsDetailed(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsDetailedMutex.Lock()
+	ret, specificReturn := fake.statsDetailedReturnsOnCall[len(fake.statsDetailedArgsForCall)]
+	fake.statsDetailedArgsForCall = append(fake.statsDetailedArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsDetailedStub
+	fakeReturns := fake.statsDetailedReturns
+	fake.recordInvocation("StatsDetailed", []interface{}{arg1})
+	fake.statsDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MirrorDB) StatsDetailedCallCount() int {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	return len(fake.statsDetailedArgsForCall)
+}
+
+func (fake *MirrorDB) StatsDetailedCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = stub
+}
+
+func (fake *MirrorDB) StatsDetailedArgsForCall(i int) context.Context {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	argsForCall := fake.statsDetailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorDB) StatsDetailedReturns(result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	fake.statsDetailedReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) StatsDetailedReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	if fake.statsDetailedReturnsOnCall == nil {
+		fake.statsDetailedReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsDetailedReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) Sync() error {
+	fake.syncMutex.Lock()
+	ret, specificReturn := fake.syncReturnsOnCall[len(fake.syncArgsForCall)]
+	fake.syncArgsForCall = append(fake.syncArgsForCall, struct {
+	}{})
+	stub := fake.SyncStub
+	fakeReturns := fake.syncReturns
+	fake.recordInvocation("Sync", []interface{}{})
+	fake.syncMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) SyncCallCount() int {
+	fake.syncMutex.RLock()
+	defer fake.syncMutex.RUnlock()
+	return len(fake.syncArgsForCall)
+}
+
+func (fake *MirrorDB) SyncCalls(stub func() error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = stub
+}
+
+func (fake *MirrorDB) SyncReturns(result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	fake.syncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) SyncReturnsOnCall(i int, result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	if fake.syncReturnsOnCall == nil {
+		fake.syncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.syncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Update(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *MirrorDB) UpdateCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *MirrorDB) UpdateArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *MirrorDB) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Verify(arg1 context.Context, arg2 ...kv.BucketName) ([]kv.MirrorDivergence, error) {
+	fake.verifyMutex.Lock()
+	ret, specificReturn := fake.verifyReturnsOnCall[len(fake.verifyArgsForCall)]
+	fake.verifyArgsForCall = append(fake.verifyArgsForCall, struct {
+		arg1 context.Context
+		arg2 []kv.BucketName
+	}{arg1, arg2})
+	stub := fake.VerifyStub
+	fakeReturns := fake.verifyReturns
+	fake.recordInvocation("Verify", []interface{}{arg1, arg2})
+	fake.verifyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MirrorDB) VerifyCallCount() int {
+	fake.verifyMutex.RLock()
+	defer fake.verifyMutex.RUnlock()
+	return len(fake.verifyArgsForCall)
+}
+
+func (fake *MirrorDB) VerifyCalls(stub func(context.Context, ...kv.BucketName) ([]kv.MirrorDivergence, error)) {
+	fake.verifyMutex.Lock()
+	defer fake.verifyMutex.Unlock()
+	fake.VerifyStub = stub
+}
+
+func (fake *MirrorDB) VerifyArgsForCall(i int) (context.Context, []kv.BucketName) {
+	fake.verifyMutex.RLock()
+	defer fake.verifyMutex.RUnlock()
+	argsForCall := fake.verifyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *MirrorDB) VerifyReturns(result1 []kv.MirrorDivergence, result2 error) {
+	fake.verifyMutex.Lock()
+	defer fake.verifyMutex.Unlock()
+	fake.VerifyStub = nil
+	fake.verifyReturns = struct {
+		result1 []kv.MirrorDivergence
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) VerifyReturnsOnCall(i int, result1 []kv.MirrorDivergence, result2 error) {
+	fake.verifyMutex.Lock()
+	defer fake.verifyMutex.Unlock()
+	fake.VerifyStub = nil
+	if fake.verifyReturnsOnCall == nil {
+		fake.verifyReturnsOnCall = make(map[int]struct {
+			result1 []kv.MirrorDivergence
+			result2 error
+		})
+	}
+	fake.verifyReturnsOnCall[i] = struct {
+		result1 []kv.MirrorDivergence
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MirrorDB) View(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.viewMutex.Lock()
+	ret, specificReturn := fake.viewReturnsOnCall[len(fake.viewArgsForCall)]
+	fake.viewArgsForCall = append(fake.viewArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.ViewStub
+	fakeReturns := fake.viewReturns
+	fake.recordInvocation("View", []interface{}{arg1, arg2})
+	fake.viewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *MirrorDB) ViewCallCount() int {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	return len(fake.viewArgsForCall)
+}
+
+func (fake *MirrorDB) ViewCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = stub
+}
+
+func (fake *MirrorDB) ViewArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	argsForCall := fake.viewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *MirrorDB) ViewReturns(result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	fake.viewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) ViewReturnsOnCall(i int, result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	if fake.viewReturnsOnCall == nil {
+		fake.viewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.viewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *MirrorDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *MirrorDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.MirrorDB = new(MirrorDB)
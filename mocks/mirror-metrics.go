@@ -0,0 +1,213 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type MirrorMetrics struct {
+	MirrorAppliedAddStub        func(int)
+	mirrorAppliedAddMutex       sync.RWMutex
+	mirrorAppliedAddArgsForCall []struct {
+		arg1 int
+	}
+	MirrorDivergenceIncStub        func(kv.BucketName)
+	mirrorDivergenceIncMutex       sync.RWMutex
+	mirrorDivergenceIncArgsForCall []struct {
+		arg1 kv.BucketName
+	}
+	MirrorFailureIncStub        func()
+	mirrorFailureIncMutex       sync.RWMutex
+	mirrorFailureIncArgsForCall []struct {
+	}
+	MirrorPositionSetStub        func(uint64)
+	mirrorPositionSetMutex       sync.RWMutex
+	mirrorPositionSetArgsForCall []struct {
+		arg1 uint64
+	}
+	MirrorReadMismatchIncStub        func(kv.BucketName)
+	mirrorReadMismatchIncMutex       sync.RWMutex
+	mirrorReadMismatchIncArgsForCall []struct {
+		arg1 kv.BucketName
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *MirrorMetrics) MirrorAppliedAdd(arg1 int) {
+	fake.mirrorAppliedAddMutex.Lock()
+	fake.mirrorAppliedAddArgsForCall = append(fake.mirrorAppliedAddArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.MirrorAppliedAddStub
+	fake.recordInvocation("MirrorAppliedAdd", []interface{}{arg1})
+	fake.mirrorAppliedAddMutex.Unlock()
+	if stub != nil {
+		fake.MirrorAppliedAddStub(arg1)
+	}
+}
+
+func (fake *MirrorMetrics) MirrorAppliedAddCallCount() int {
+	fake.mirrorAppliedAddMutex.RLock()
+	defer fake.mirrorAppliedAddMutex.RUnlock()
+	return len(fake.mirrorAppliedAddArgsForCall)
+}
+
+func (fake *MirrorMetrics) MirrorAppliedAddCalls(stub func(int)) {
+	fake.mirrorAppliedAddMutex.Lock()
+	defer fake.mirrorAppliedAddMutex.Unlock()
+	fake.MirrorAppliedAddStub = stub
+}
+
+func (fake *MirrorMetrics) MirrorAppliedAddArgsForCall(i int) int {
+	fake.mirrorAppliedAddMutex.RLock()
+	defer fake.mirrorAppliedAddMutex.RUnlock()
+	argsForCall := fake.mirrorAppliedAddArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorMetrics) MirrorDivergenceInc(arg1 kv.BucketName) {
+	fake.mirrorDivergenceIncMutex.Lock()
+	fake.mirrorDivergenceIncArgsForCall = append(fake.mirrorDivergenceIncArgsForCall, struct {
+		arg1 kv.BucketName
+	}{arg1})
+	stub := fake.MirrorDivergenceIncStub
+	fake.recordInvocation("MirrorDivergenceInc", []interface{}{arg1})
+	fake.mirrorDivergenceIncMutex.Unlock()
+	if stub != nil {
+		fake.MirrorDivergenceIncStub(arg1)
+	}
+}
+
+func (fake *MirrorMetrics) MirrorDivergenceIncCallCount() int {
+	fake.mirrorDivergenceIncMutex.RLock()
+	defer fake.mirrorDivergenceIncMutex.RUnlock()
+	return len(fake.mirrorDivergenceIncArgsForCall)
+}
+
+func (fake *MirrorMetrics) MirrorDivergenceIncCalls(stub func(kv.BucketName)) {
+	fake.mirrorDivergenceIncMutex.Lock()
+	defer fake.mirrorDivergenceIncMutex.Unlock()
+	fake.MirrorDivergenceIncStub = stub
+}
+
+func (fake *MirrorMetrics) MirrorDivergenceIncArgsForCall(i int) kv.BucketName {
+	fake.mirrorDivergenceIncMutex.RLock()
+	defer fake.mirrorDivergenceIncMutex.RUnlock()
+	argsForCall := fake.mirrorDivergenceIncArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorMetrics) MirrorFailureInc() {
+	fake.mirrorFailureIncMutex.Lock()
+	fake.mirrorFailureIncArgsForCall = append(fake.mirrorFailureIncArgsForCall, struct {
+	}{})
+	stub := fake.MirrorFailureIncStub
+	fake.recordInvocation("MirrorFailureInc", []interface{}{})
+	fake.mirrorFailureIncMutex.Unlock()
+	if stub != nil {
+		fake.MirrorFailureIncStub()
+	}
+}
+
+func (fake *MirrorMetrics) MirrorFailureIncCallCount() int {
+	fake.mirrorFailureIncMutex.RLock()
+	defer fake.mirrorFailureIncMutex.RUnlock()
+	return len(fake.mirrorFailureIncArgsForCall)
+}
+
+func (fake *MirrorMetrics) MirrorFailureIncCalls(stub func()) {
+	fake.mirrorFailureIncMutex.Lock()
+	defer fake.mirrorFailureIncMutex.Unlock()
+	fake.MirrorFailureIncStub = stub
+}
+
+func (fake *MirrorMetrics) MirrorPositionSet(arg1 uint64) {
+	fake.mirrorPositionSetMutex.Lock()
+	fake.mirrorPositionSetArgsForCall = append(fake.mirrorPositionSetArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.MirrorPositionSetStub
+	fake.recordInvocation("MirrorPositionSet", []interface{}{arg1})
+	fake.mirrorPositionSetMutex.Unlock()
+	if stub != nil {
+		fake.MirrorPositionSetStub(arg1)
+	}
+}
+
+func (fake *MirrorMetrics) MirrorPositionSetCallCount() int {
+	fake.mirrorPositionSetMutex.RLock()
+	defer fake.mirrorPositionSetMutex.RUnlock()
+	return len(fake.mirrorPositionSetArgsForCall)
+}
+
+func (fake *MirrorMetrics) MirrorPositionSetCalls(stub func(uint64)) {
+	fake.mirrorPositionSetMutex.Lock()
+	defer fake.mirrorPositionSetMutex.Unlock()
+	fake.MirrorPositionSetStub = stub
+}
+
+func (fake *MirrorMetrics) MirrorPositionSetArgsForCall(i int) uint64 {
+	fake.mirrorPositionSetMutex.RLock()
+	defer fake.mirrorPositionSetMutex.RUnlock()
+	argsForCall := fake.mirrorPositionSetArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorMetrics) MirrorReadMismatchInc(arg1 kv.BucketName) {
+	fake.mirrorReadMismatchIncMutex.Lock()
+	fake.mirrorReadMismatchIncArgsForCall = append(fake.mirrorReadMismatchIncArgsForCall, struct {
+		arg1 kv.BucketName
+	}{arg1})
+	stub := fake.MirrorReadMismatchIncStub
+	fake.recordInvocation("MirrorReadMismatchInc", []interface{}{arg1})
+	fake.mirrorReadMismatchIncMutex.Unlock()
+	if stub != nil {
+		fake.MirrorReadMismatchIncStub(arg1)
+	}
+}
+
+func (fake *MirrorMetrics) MirrorReadMismatchIncCallCount() int {
+	fake.mirrorReadMismatchIncMutex.RLock()
+	defer fake.mirrorReadMismatchIncMutex.RUnlock()
+	return len(fake.mirrorReadMismatchIncArgsForCall)
+}
+
+func (fake *MirrorMetrics) MirrorReadMismatchIncCalls(stub func(kv.BucketName)) {
+	fake.mirrorReadMismatchIncMutex.Lock()
+	defer fake.mirrorReadMismatchIncMutex.Unlock()
+	fake.MirrorReadMismatchIncStub = stub
+}
+
+func (fake *MirrorMetrics) MirrorReadMismatchIncArgsForCall(i int) kv.BucketName {
+	fake.mirrorReadMismatchIncMutex.RLock()
+	defer fake.mirrorReadMismatchIncMutex.RUnlock()
+	argsForCall := fake.mirrorReadMismatchIncArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MirrorMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *MirrorMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.MirrorMetrics = new(MirrorMetrics)
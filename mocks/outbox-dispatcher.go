@@ -0,0 +1,182 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type OutboxDispatcher struct {
+	DispatchStub        func(context.Context) error
+	dispatchMutex       sync.RWMutex
+	dispatchArgsForCall []struct {
+		arg1 context.Context
+	}
+	dispatchReturns struct {
+		result1 error
+	}
+	dispatchReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RunStub        func(context.Context) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 context.Context
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *OutboxDispatcher) Dispatch(arg1 context.Context) error {
+	fake.dispatchMutex.Lock()
+	ret, specificReturn := fake.dispatchReturnsOnCall[len(fake.dispatchArgsForCall)]
+	fake.dispatchArgsForCall = append(fake.dispatchArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.DispatchStub
+	fakeReturns := fake.dispatchReturns
+	fake.recordInvocation("Dispatch", []interface{}{arg1})
+	fake.dispatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OutboxDispatcher) DispatchCallCount() int {
+	fake.dispatchMutex.RLock()
+	defer fake.dispatchMutex.RUnlock()
+	return len(fake.dispatchArgsForCall)
+}
+
+func (fake *OutboxDispatcher) DispatchCalls(stub func(context.Context) error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = stub
+}
+
+func (fake *OutboxDispatcher) DispatchArgsForCall(i int) context.Context {
+	fake.dispatchMutex.RLock()
+	defer fake.dispatchMutex.RUnlock()
+	argsForCall := fake.dispatchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OutboxDispatcher) DispatchReturns(result1 error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = nil
+	fake.dispatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxDispatcher) DispatchReturnsOnCall(i int, result1 error) {
+	fake.dispatchMutex.Lock()
+	defer fake.dispatchMutex.Unlock()
+	fake.DispatchStub = nil
+	if fake.dispatchReturnsOnCall == nil {
+		fake.dispatchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.dispatchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxDispatcher) Run(arg1 context.Context) error {
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OutboxDispatcher) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *OutboxDispatcher) RunCalls(stub func(context.Context) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *OutboxDispatcher) RunArgsForCall(i int) context.Context {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OutboxDispatcher) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxDispatcher) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxDispatcher) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *OutboxDispatcher) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.OutboxDispatcher = new(OutboxDispatcher)
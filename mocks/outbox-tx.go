@@ -0,0 +1,114 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type OutboxTx struct {
+	EnqueueStub        func(context.Context, kv.Tx, kv.OutboxMessage) error
+	enqueueMutex       sync.RWMutex
+	enqueueArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.Tx
+		arg3 kv.OutboxMessage
+	}
+	enqueueReturns struct {
+		result1 error
+	}
+	enqueueReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *OutboxTx) Enqueue(arg1 context.Context, arg2 kv.Tx, arg3 kv.OutboxMessage) error {
+	fake.enqueueMutex.Lock()
+	ret, specificReturn := fake.enqueueReturnsOnCall[len(fake.enqueueArgsForCall)]
+	fake.enqueueArgsForCall = append(fake.enqueueArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.Tx
+		arg3 kv.OutboxMessage
+	}{arg1, arg2, arg3})
+	stub := fake.EnqueueStub
+	fakeReturns := fake.enqueueReturns
+	fake.recordInvocation("Enqueue", []interface{}{arg1, arg2, arg3})
+	fake.enqueueMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OutboxTx) EnqueueCallCount() int {
+	fake.enqueueMutex.RLock()
+	defer fake.enqueueMutex.RUnlock()
+	return len(fake.enqueueArgsForCall)
+}
+
+func (fake *OutboxTx) EnqueueCalls(stub func(context.Context, kv.Tx, kv.OutboxMessage) error) {
+	fake.enqueueMutex.Lock()
+	defer fake.enqueueMutex.Unlock()
+	fake.EnqueueStub = stub
+}
+
+func (fake *OutboxTx) EnqueueArgsForCall(i int) (context.Context, kv.Tx, kv.OutboxMessage) {
+	fake.enqueueMutex.RLock()
+	defer fake.enqueueMutex.RUnlock()
+	argsForCall := fake.enqueueArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *OutboxTx) EnqueueReturns(result1 error) {
+	fake.enqueueMutex.Lock()
+	defer fake.enqueueMutex.Unlock()
+	fake.EnqueueStub = nil
+	fake.enqueueReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxTx) EnqueueReturnsOnCall(i int, result1 error) {
+	fake.enqueueMutex.Lock()
+	defer fake.enqueueMutex.Unlock()
+	fake.EnqueueStub = nil
+	if fake.enqueueReturnsOnCall == nil {
+		fake.enqueueReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.enqueueReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OutboxTx) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *OutboxTx) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.OutboxTx = new(OutboxTx)
@@ -0,0 +1,750 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type OverlayDB struct {
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CommitStub        func(context.Context) error
+	commitMutex       sync.RWMutex
+	commitArgsForCall []struct {
+		arg1 context.Context
+	}
+	commitReturns struct {
+		result1 error
+	}
+	commitReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DiffStub        func(context.Context) ([]kv.OverlayChange, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 context.Context
+	}
+	diffReturns struct {
+		result1 []kv.OverlayChange
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 []kv.OverlayChange
+		result2 error
+	}
+	DiscardStub        func(context.Context) error
+	discardMutex       sync.RWMutex
+	discardArgsForCall []struct {
+		arg1 context.Context
+	}
+	discardReturns struct {
+		result1 error
+	}
+	discardReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveStub        func() error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+	}
+	removeReturns struct {
+		result1 error
+	}
+	removeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StatsStub        func(context.Context) (*kv.Stats, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	StatsDetailedStub        func(context.Context) (*kv.Stats, error)
+	statsDetailedMutex       sync.RWMutex
+	statsDetailedArgsForCall []struct {
+		arg1 context.Context
+	}
+	statsDetailedReturns struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	statsDetailedReturnsOnCall map[int]struct {
+		result1 *kv.Stats
+		result2 error
+	}
+	SyncStub        func() error
+	syncMutex       sync.RWMutex
+	syncArgsForCall []struct {
+	}
+	syncReturns struct {
+		result1 error
+	}
+	syncReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ViewStub        func(context.Context, func(ctx context.Context, tx kv.Tx) error) error
+	viewMutex       sync.RWMutex
+	viewArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}
+	viewReturns struct {
+		result1 error
+	}
+	viewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *OverlayDB) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *OverlayDB) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *OverlayDB) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Commit(arg1 context.Context) error {
+	fake.commitMutex.Lock()
+	ret, specificReturn := fake.commitReturnsOnCall[len(fake.commitArgsForCall)]
+	fake.commitArgsForCall = append(fake.commitArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CommitStub
+	fakeReturns := fake.commitReturns
+	fake.recordInvocation("Commit", []interface{}{arg1})
+	fake.commitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) CommitCallCount() int {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return len(fake.commitArgsForCall)
+}
+
+func (fake *OverlayDB) CommitCalls(stub func(context.Context) error) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = stub
+}
+
+func (fake *OverlayDB) CommitArgsForCall(i int) context.Context {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	argsForCall := fake.commitArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OverlayDB) CommitReturns(result1 error) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = nil
+	fake.commitReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) CommitReturnsOnCall(i int, result1 error) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = nil
+	if fake.commitReturnsOnCall == nil {
+		fake.commitReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.commitReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Diff(arg1 context.Context) ([]kv.OverlayChange, error) {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.DiffStub
+	fakeReturns := fake.diffReturns
+	fake.recordInvocation("Diff", []interface{}{arg1})
+	fake.diffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *OverlayDB) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *OverlayDB) DiffCalls(stub func(context.Context) ([]kv.OverlayChange, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *OverlayDB) DiffArgsForCall(i int) context.Context {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OverlayDB) DiffReturns(result1 []kv.OverlayChange, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 []kv.OverlayChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) DiffReturnsOnCall(i int, result1 []kv.OverlayChange, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 []kv.OverlayChange
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 []kv.OverlayChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) Discard(arg1 context.Context) error {
+	fake.discardMutex.Lock()
+	ret, specificReturn := fake.discardReturnsOnCall[len(fake.discardArgsForCall)]
+	fake.discardArgsForCall = append(fake.discardArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.DiscardStub
+	fakeReturns := fake.discardReturns
+	fake.recordInvocation("Discard", []interface{}{arg1})
+	fake.discardMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) DiscardCallCount() int {
+	fake.discardMutex.RLock()
+	defer fake.discardMutex.RUnlock()
+	return len(fake.discardArgsForCall)
+}
+
+func (fake *OverlayDB) DiscardCalls(stub func(context.Context) error) {
+	fake.discardMutex.Lock()
+	defer fake.discardMutex.Unlock()
+	fake.DiscardStub = stub
+}
+
+func (fake *OverlayDB) DiscardArgsForCall(i int) context.Context {
+	fake.discardMutex.RLock()
+	defer fake.discardMutex.RUnlock()
+	argsForCall := fake.discardArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OverlayDB) DiscardReturns(result1 error) {
+	fake.discardMutex.Lock()
+	defer fake.discardMutex.Unlock()
+	fake.DiscardStub = nil
+	fake.discardReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) DiscardReturnsOnCall(i int, result1 error) {
+	fake.discardMutex.Lock()
+	defer fake.discardMutex.Unlock()
+	fake.DiscardStub = nil
+	if fake.discardReturnsOnCall == nil {
+		fake.discardReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.discardReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Remove() error {
+	fake.removeMutex.Lock()
+	ret, specificReturn := fake.removeReturnsOnCall[len(fake.removeArgsForCall)]
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+	}{})
+	stub := fake.RemoveStub
+	fakeReturns := fake.removeReturns
+	fake.recordInvocation("Remove", []interface{}{})
+	fake.removeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *OverlayDB) RemoveCalls(stub func() error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = stub
+}
+
+func (fake *OverlayDB) RemoveReturns(result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) RemoveReturnsOnCall(i int, result1 error) {
+	fake.removeMutex.Lock()
+	defer fake.removeMutex.Unlock()
+	fake.RemoveStub = nil
+	if fake.removeReturnsOnCall == nil {
+		fake.removeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Stats(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsStub
+	fakeReturns := fake.statsReturns
+	fake.recordInvocation("Stats", []interface{}{arg1})
+	fake.statsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *OverlayDB) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *OverlayDB) StatsCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = stub
+}
+
+func (fake *OverlayDB) StatsArgsForCall(i int) context.Context {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	argsForCall := fake.statsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OverlayDB) StatsReturns(result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) StatsReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) StatsDetailed(arg1 context.Context) (*kv.Stats, error) {
+	fake.statsDetailedMutex.Lock()
+	ret, specificReturn := fake.statsDetailedReturnsOnCall[len(fake.statsDetailedArgsForCall)]
+	fake.statsDetailedArgsForCall = append(fake.statsDetailedArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.StatsDetailedStub
+	fakeReturns := fake.statsDetailedReturns
+	fake.recordInvocation("StatsDetailed", []interface{}{arg1})
+	fake.statsDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *OverlayDB) StatsDetailedCallCount() int {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	return len(fake.statsDetailedArgsForCall)
+}
+
+func (fake *OverlayDB) StatsDetailedCalls(stub func(context.Context) (*kv.Stats, error)) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = stub
+}
+
+func (fake *OverlayDB) StatsDetailedArgsForCall(i int) context.Context {
+	fake.statsDetailedMutex.RLock()
+	defer fake.statsDetailedMutex.RUnlock()
+	argsForCall := fake.statsDetailedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OverlayDB) StatsDetailedReturns(result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	fake.statsDetailedReturns = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) StatsDetailedReturnsOnCall(i int, result1 *kv.Stats, result2 error) {
+	fake.statsDetailedMutex.Lock()
+	defer fake.statsDetailedMutex.Unlock()
+	fake.StatsDetailedStub = nil
+	if fake.statsDetailedReturnsOnCall == nil {
+		fake.statsDetailedReturnsOnCall = make(map[int]struct {
+			result1 *kv.Stats
+			result2 error
+		})
+	}
+	fake.statsDetailedReturnsOnCall[i] = struct {
+		result1 *kv.Stats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OverlayDB) Sync() error {
+	fake.syncMutex.Lock()
+	ret, specificReturn := fake.syncReturnsOnCall[len(fake.syncArgsForCall)]
+	fake.syncArgsForCall = append(fake.syncArgsForCall, struct {
+	}{})
+	stub := fake.SyncStub
+	fakeReturns := fake.syncReturns
+	fake.recordInvocation("Sync", []interface{}{})
+	fake.syncMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) SyncCallCount() int {
+	fake.syncMutex.RLock()
+	defer fake.syncMutex.RUnlock()
+	return len(fake.syncArgsForCall)
+}
+
+func (fake *OverlayDB) SyncCalls(stub func() error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = stub
+}
+
+func (fake *OverlayDB) SyncReturns(result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	fake.syncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) SyncReturnsOnCall(i int, result1 error) {
+	fake.syncMutex.Lock()
+	defer fake.syncMutex.Unlock()
+	fake.SyncStub = nil
+	if fake.syncReturnsOnCall == nil {
+		fake.syncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.syncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Update(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *OverlayDB) UpdateCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *OverlayDB) UpdateArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *OverlayDB) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) View(arg1 context.Context, arg2 func(ctx context.Context, tx kv.Tx) error) error {
+	fake.viewMutex.Lock()
+	ret, specificReturn := fake.viewReturnsOnCall[len(fake.viewArgsForCall)]
+	fake.viewArgsForCall = append(fake.viewArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context, tx kv.Tx) error
+	}{arg1, arg2})
+	stub := fake.ViewStub
+	fakeReturns := fake.viewReturns
+	fake.recordInvocation("View", []interface{}{arg1, arg2})
+	fake.viewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverlayDB) ViewCallCount() int {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	return len(fake.viewArgsForCall)
+}
+
+func (fake *OverlayDB) ViewCalls(stub func(context.Context, func(ctx context.Context, tx kv.Tx) error) error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = stub
+}
+
+func (fake *OverlayDB) ViewArgsForCall(i int) (context.Context, func(ctx context.Context, tx kv.Tx) error) {
+	fake.viewMutex.RLock()
+	defer fake.viewMutex.RUnlock()
+	argsForCall := fake.viewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *OverlayDB) ViewReturns(result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	fake.viewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) ViewReturnsOnCall(i int, result1 error) {
+	fake.viewMutex.Lock()
+	defer fake.viewMutex.Unlock()
+	fake.ViewStub = nil
+	if fake.viewReturnsOnCall == nil {
+		fake.viewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.viewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverlayDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *OverlayDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.OverlayDB = new(OverlayDB)
@@ -0,0 +1,187 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type RetentionManager struct {
+	RunStub        func(context.Context) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 context.Context
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	TrimStub        func(context.Context) ([]kv.RetentionResult, error)
+	trimMutex       sync.RWMutex
+	trimArgsForCall []struct {
+		arg1 context.Context
+	}
+	trimReturns struct {
+		result1 []kv.RetentionResult
+		result2 error
+	}
+	trimReturnsOnCall map[int]struct {
+		result1 []kv.RetentionResult
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RetentionManager) Run(arg1 context.Context) error {
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *RetentionManager) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *RetentionManager) RunCalls(stub func(context.Context) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *RetentionManager) RunArgsForCall(i int) context.Context {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RetentionManager) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RetentionManager) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RetentionManager) Trim(arg1 context.Context) ([]kv.RetentionResult, error) {
+	fake.trimMutex.Lock()
+	ret, specificReturn := fake.trimReturnsOnCall[len(fake.trimArgsForCall)]
+	fake.trimArgsForCall = append(fake.trimArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.TrimStub
+	fakeReturns := fake.trimReturns
+	fake.recordInvocation("Trim", []interface{}{arg1})
+	fake.trimMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RetentionManager) TrimCallCount() int {
+	fake.trimMutex.RLock()
+	defer fake.trimMutex.RUnlock()
+	return len(fake.trimArgsForCall)
+}
+
+func (fake *RetentionManager) TrimCalls(stub func(context.Context) ([]kv.RetentionResult, error)) {
+	fake.trimMutex.Lock()
+	defer fake.trimMutex.Unlock()
+	fake.TrimStub = stub
+}
+
+func (fake *RetentionManager) TrimArgsForCall(i int) context.Context {
+	fake.trimMutex.RLock()
+	defer fake.trimMutex.RUnlock()
+	argsForCall := fake.trimArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RetentionManager) TrimReturns(result1 []kv.RetentionResult, result2 error) {
+	fake.trimMutex.Lock()
+	defer fake.trimMutex.Unlock()
+	fake.TrimStub = nil
+	fake.trimReturns = struct {
+		result1 []kv.RetentionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RetentionManager) TrimReturnsOnCall(i int, result1 []kv.RetentionResult, result2 error) {
+	fake.trimMutex.Lock()
+	defer fake.trimMutex.Unlock()
+	fake.TrimStub = nil
+	if fake.trimReturnsOnCall == nil {
+		fake.trimReturnsOnCall = make(map[int]struct {
+			result1 []kv.RetentionResult
+			result2 error
+		})
+	}
+	fake.trimReturnsOnCall[i] = struct {
+		result1 []kv.RetentionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RetentionManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RetentionManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.RetentionManager = new(RetentionManager)
@@ -0,0 +1,115 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type RetentionMetrics struct {
+	RetentionPurgedAddStub        func(kv.BucketName, int64)
+	retentionPurgedAddMutex       sync.RWMutex
+	retentionPurgedAddArgsForCall []struct {
+		arg1 kv.BucketName
+		arg2 int64
+	}
+	RetentionRetainedSetStub        func(kv.BucketName, int64)
+	retentionRetainedSetMutex       sync.RWMutex
+	retentionRetainedSetArgsForCall []struct {
+		arg1 kv.BucketName
+		arg2 int64
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RetentionMetrics) RetentionPurgedAdd(arg1 kv.BucketName, arg2 int64) {
+	fake.retentionPurgedAddMutex.Lock()
+	fake.retentionPurgedAddArgsForCall = append(fake.retentionPurgedAddArgsForCall, struct {
+		arg1 kv.BucketName
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.RetentionPurgedAddStub
+	fake.recordInvocation("RetentionPurgedAdd", []interface{}{arg1, arg2})
+	fake.retentionPurgedAddMutex.Unlock()
+	if stub != nil {
+		fake.RetentionPurgedAddStub(arg1, arg2)
+	}
+}
+
+func (fake *RetentionMetrics) RetentionPurgedAddCallCount() int {
+	fake.retentionPurgedAddMutex.RLock()
+	defer fake.retentionPurgedAddMutex.RUnlock()
+	return len(fake.retentionPurgedAddArgsForCall)
+}
+
+func (fake *RetentionMetrics) RetentionPurgedAddCalls(stub func(kv.BucketName, int64)) {
+	fake.retentionPurgedAddMutex.Lock()
+	defer fake.retentionPurgedAddMutex.Unlock()
+	fake.RetentionPurgedAddStub = stub
+}
+
+func (fake *RetentionMetrics) RetentionPurgedAddArgsForCall(i int) (kv.BucketName, int64) {
+	fake.retentionPurgedAddMutex.RLock()
+	defer fake.retentionPurgedAddMutex.RUnlock()
+	argsForCall := fake.retentionPurgedAddArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *RetentionMetrics) RetentionRetainedSet(arg1 kv.BucketName, arg2 int64) {
+	fake.retentionRetainedSetMutex.Lock()
+	fake.retentionRetainedSetArgsForCall = append(fake.retentionRetainedSetArgsForCall, struct {
+		arg1 kv.BucketName
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.RetentionRetainedSetStub
+	fake.recordInvocation("RetentionRetainedSet", []interface{}{arg1, arg2})
+	fake.retentionRetainedSetMutex.Unlock()
+	if stub != nil {
+		fake.RetentionRetainedSetStub(arg1, arg2)
+	}
+}
+
+func (fake *RetentionMetrics) RetentionRetainedSetCallCount() int {
+	fake.retentionRetainedSetMutex.RLock()
+	defer fake.retentionRetainedSetMutex.RUnlock()
+	return len(fake.retentionRetainedSetArgsForCall)
+}
+
+func (fake *RetentionMetrics) RetentionRetainedSetCalls(stub func(kv.BucketName, int64)) {
+	fake.retentionRetainedSetMutex.Lock()
+	defer fake.retentionRetainedSetMutex.Unlock()
+	fake.RetentionRetainedSetStub = stub
+}
+
+func (fake *RetentionMetrics) RetentionRetainedSetArgsForCall(i int) (kv.BucketName, int64) {
+	fake.retentionRetainedSetMutex.RLock()
+	defer fake.retentionRetainedSetMutex.RUnlock()
+	argsForCall := fake.retentionRetainedSetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *RetentionMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RetentionMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.RetentionMetrics = new(RetentionMetrics)
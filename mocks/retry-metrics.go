@@ -0,0 +1,65 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type RetryMetrics struct {
+	DbUpdateRetryIncStub        func()
+	dbUpdateRetryIncMutex       sync.RWMutex
+	dbUpdateRetryIncArgsForCall []struct {
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RetryMetrics) DbUpdateRetryInc() {
+	fake.dbUpdateRetryIncMutex.Lock()
+	fake.dbUpdateRetryIncArgsForCall = append(fake.dbUpdateRetryIncArgsForCall, struct {
+	}{})
+	stub := fake.DbUpdateRetryIncStub
+	fake.recordInvocation("DbUpdateRetryInc", []interface{}{})
+	fake.dbUpdateRetryIncMutex.Unlock()
+	if stub != nil {
+		fake.DbUpdateRetryIncStub()
+	}
+}
+
+func (fake *RetryMetrics) DbUpdateRetryIncCallCount() int {
+	fake.dbUpdateRetryIncMutex.RLock()
+	defer fake.dbUpdateRetryIncMutex.RUnlock()
+	return len(fake.dbUpdateRetryIncArgsForCall)
+}
+
+func (fake *RetryMetrics) DbUpdateRetryIncCalls(stub func()) {
+	fake.dbUpdateRetryIncMutex.Lock()
+	defer fake.dbUpdateRetryIncMutex.Unlock()
+	fake.DbUpdateRetryIncStub = stub
+}
+
+func (fake *RetryMetrics) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RetryMetrics) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.RetryMetrics = new(RetryMetrics)
@@ -0,0 +1,651 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type SavepointTx struct {
+	BucketStub        func(context.Context, kv.BucketName) (kv.Bucket, error)
+	bucketMutex       sync.RWMutex
+	bucketArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}
+	bucketReturns struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	bucketReturnsOnCall map[int]struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	CreateBucketStub        func(context.Context, kv.BucketName) (kv.Bucket, error)
+	createBucketMutex       sync.RWMutex
+	createBucketArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}
+	createBucketReturns struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	createBucketReturnsOnCall map[int]struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	CreateBucketIfNotExistsStub        func(context.Context, kv.BucketName) (kv.Bucket, error)
+	createBucketIfNotExistsMutex       sync.RWMutex
+	createBucketIfNotExistsArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}
+	createBucketIfNotExistsReturns struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	createBucketIfNotExistsReturnsOnCall map[int]struct {
+		result1 kv.Bucket
+		result2 error
+	}
+	DeleteBucketStub        func(context.Context, kv.BucketName) error
+	deleteBucketMutex       sync.RWMutex
+	deleteBucketArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}
+	deleteBucketReturns struct {
+		result1 error
+	}
+	deleteBucketReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListBucketNamesStub        func(context.Context) (kv.BucketNames, error)
+	listBucketNamesMutex       sync.RWMutex
+	listBucketNamesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listBucketNamesReturns struct {
+		result1 kv.BucketNames
+		result2 error
+	}
+	listBucketNamesReturnsOnCall map[int]struct {
+		result1 kv.BucketNames
+		result2 error
+	}
+	ReleaseStub        func(context.Context, kv.Savepoint) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.Savepoint
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RollbackToStub        func(context.Context, kv.Savepoint) error
+	rollbackToMutex       sync.RWMutex
+	rollbackToArgsForCall []struct {
+		arg1 context.Context
+		arg2 kv.Savepoint
+	}
+	rollbackToReturns struct {
+		result1 error
+	}
+	rollbackToReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SavepointStub        func(context.Context) (kv.Savepoint, error)
+	savepointMutex       sync.RWMutex
+	savepointArgsForCall []struct {
+		arg1 context.Context
+	}
+	savepointReturns struct {
+		result1 kv.Savepoint
+		result2 error
+	}
+	savepointReturnsOnCall map[int]struct {
+		result1 kv.Savepoint
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SavepointTx) Bucket(arg1 context.Context, arg2 kv.BucketName) (kv.Bucket, error) {
+	fake.bucketMutex.Lock()
+	ret, specificReturn := fake.bucketReturnsOnCall[len(fake.bucketArgsForCall)]
+	fake.bucketArgsForCall = append(fake.bucketArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}{arg1, arg2})
+	stub := fake.BucketStub
+	fakeReturns := fake.bucketReturns
+	fake.recordInvocation("Bucket", []interface{}{arg1, arg2})
+	fake.bucketMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SavepointTx) BucketCallCount() int {
+	fake.bucketMutex.RLock()
+	defer fake.bucketMutex.RUnlock()
+	return len(fake.bucketArgsForCall)
+}
+
+func (fake *SavepointTx) BucketCalls(stub func(context.Context, kv.BucketName) (kv.Bucket, error)) {
+	fake.bucketMutex.Lock()
+	defer fake.bucketMutex.Unlock()
+	fake.BucketStub = stub
+}
+
+func (fake *SavepointTx) BucketArgsForCall(i int) (context.Context, kv.BucketName) {
+	fake.bucketMutex.RLock()
+	defer fake.bucketMutex.RUnlock()
+	argsForCall := fake.bucketArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) BucketReturns(result1 kv.Bucket, result2 error) {
+	fake.bucketMutex.Lock()
+	defer fake.bucketMutex.Unlock()
+	fake.BucketStub = nil
+	fake.bucketReturns = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) BucketReturnsOnCall(i int, result1 kv.Bucket, result2 error) {
+	fake.bucketMutex.Lock()
+	defer fake.bucketMutex.Unlock()
+	fake.BucketStub = nil
+	if fake.bucketReturnsOnCall == nil {
+		fake.bucketReturnsOnCall = make(map[int]struct {
+			result1 kv.Bucket
+			result2 error
+		})
+	}
+	fake.bucketReturnsOnCall[i] = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) CreateBucket(arg1 context.Context, arg2 kv.BucketName) (kv.Bucket, error) {
+	fake.createBucketMutex.Lock()
+	ret, specificReturn := fake.createBucketReturnsOnCall[len(fake.createBucketArgsForCall)]
+	fake.createBucketArgsForCall = append(fake.createBucketArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}{arg1, arg2})
+	stub := fake.CreateBucketStub
+	fakeReturns := fake.createBucketReturns
+	fake.recordInvocation("CreateBucket", []interface{}{arg1, arg2})
+	fake.createBucketMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SavepointTx) CreateBucketCallCount() int {
+	fake.createBucketMutex.RLock()
+	defer fake.createBucketMutex.RUnlock()
+	return len(fake.createBucketArgsForCall)
+}
+
+func (fake *SavepointTx) CreateBucketCalls(stub func(context.Context, kv.BucketName) (kv.Bucket, error)) {
+	fake.createBucketMutex.Lock()
+	defer fake.createBucketMutex.Unlock()
+	fake.CreateBucketStub = stub
+}
+
+func (fake *SavepointTx) CreateBucketArgsForCall(i int) (context.Context, kv.BucketName) {
+	fake.createBucketMutex.RLock()
+	defer fake.createBucketMutex.RUnlock()
+	argsForCall := fake.createBucketArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) CreateBucketReturns(result1 kv.Bucket, result2 error) {
+	fake.createBucketMutex.Lock()
+	defer fake.createBucketMutex.Unlock()
+	fake.CreateBucketStub = nil
+	fake.createBucketReturns = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) CreateBucketReturnsOnCall(i int, result1 kv.Bucket, result2 error) {
+	fake.createBucketMutex.Lock()
+	defer fake.createBucketMutex.Unlock()
+	fake.CreateBucketStub = nil
+	if fake.createBucketReturnsOnCall == nil {
+		fake.createBucketReturnsOnCall = make(map[int]struct {
+			result1 kv.Bucket
+			result2 error
+		})
+	}
+	fake.createBucketReturnsOnCall[i] = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExists(arg1 context.Context, arg2 kv.BucketName) (kv.Bucket, error) {
+	fake.createBucketIfNotExistsMutex.Lock()
+	ret, specificReturn := fake.createBucketIfNotExistsReturnsOnCall[len(fake.createBucketIfNotExistsArgsForCall)]
+	fake.createBucketIfNotExistsArgsForCall = append(fake.createBucketIfNotExistsArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}{arg1, arg2})
+	stub := fake.CreateBucketIfNotExistsStub
+	fakeReturns := fake.createBucketIfNotExistsReturns
+	fake.recordInvocation("CreateBucketIfNotExists", []interface{}{arg1, arg2})
+	fake.createBucketIfNotExistsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExistsCallCount() int {
+	fake.createBucketIfNotExistsMutex.RLock()
+	defer fake.createBucketIfNotExistsMutex.RUnlock()
+	return len(fake.createBucketIfNotExistsArgsForCall)
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExistsCalls(stub func(context.Context, kv.BucketName) (kv.Bucket, error)) {
+	fake.createBucketIfNotExistsMutex.Lock()
+	defer fake.createBucketIfNotExistsMutex.Unlock()
+	fake.CreateBucketIfNotExistsStub = stub
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExistsArgsForCall(i int) (context.Context, kv.BucketName) {
+	fake.createBucketIfNotExistsMutex.RLock()
+	defer fake.createBucketIfNotExistsMutex.RUnlock()
+	argsForCall := fake.createBucketIfNotExistsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExistsReturns(result1 kv.Bucket, result2 error) {
+	fake.createBucketIfNotExistsMutex.Lock()
+	defer fake.createBucketIfNotExistsMutex.Unlock()
+	fake.CreateBucketIfNotExistsStub = nil
+	fake.createBucketIfNotExistsReturns = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) CreateBucketIfNotExistsReturnsOnCall(i int, result1 kv.Bucket, result2 error) {
+	fake.createBucketIfNotExistsMutex.Lock()
+	defer fake.createBucketIfNotExistsMutex.Unlock()
+	fake.CreateBucketIfNotExistsStub = nil
+	if fake.createBucketIfNotExistsReturnsOnCall == nil {
+		fake.createBucketIfNotExistsReturnsOnCall = make(map[int]struct {
+			result1 kv.Bucket
+			result2 error
+		})
+	}
+	fake.createBucketIfNotExistsReturnsOnCall[i] = struct {
+		result1 kv.Bucket
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) DeleteBucket(arg1 context.Context, arg2 kv.BucketName) error {
+	fake.deleteBucketMutex.Lock()
+	ret, specificReturn := fake.deleteBucketReturnsOnCall[len(fake.deleteBucketArgsForCall)]
+	fake.deleteBucketArgsForCall = append(fake.deleteBucketArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.BucketName
+	}{arg1, arg2})
+	stub := fake.DeleteBucketStub
+	fakeReturns := fake.deleteBucketReturns
+	fake.recordInvocation("DeleteBucket", []interface{}{arg1, arg2})
+	fake.deleteBucketMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SavepointTx) DeleteBucketCallCount() int {
+	fake.deleteBucketMutex.RLock()
+	defer fake.deleteBucketMutex.RUnlock()
+	return len(fake.deleteBucketArgsForCall)
+}
+
+func (fake *SavepointTx) DeleteBucketCalls(stub func(context.Context, kv.BucketName) error) {
+	fake.deleteBucketMutex.Lock()
+	defer fake.deleteBucketMutex.Unlock()
+	fake.DeleteBucketStub = stub
+}
+
+func (fake *SavepointTx) DeleteBucketArgsForCall(i int) (context.Context, kv.BucketName) {
+	fake.deleteBucketMutex.RLock()
+	defer fake.deleteBucketMutex.RUnlock()
+	argsForCall := fake.deleteBucketArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) DeleteBucketReturns(result1 error) {
+	fake.deleteBucketMutex.Lock()
+	defer fake.deleteBucketMutex.Unlock()
+	fake.DeleteBucketStub = nil
+	fake.deleteBucketReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) DeleteBucketReturnsOnCall(i int, result1 error) {
+	fake.deleteBucketMutex.Lock()
+	defer fake.deleteBucketMutex.Unlock()
+	fake.DeleteBucketStub = nil
+	if fake.deleteBucketReturnsOnCall == nil {
+		fake.deleteBucketReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteBucketReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) ListBucketNames(arg1 context.Context) (kv.BucketNames, error) {
+	fake.listBucketNamesMutex.Lock()
+	ret, specificReturn := fake.listBucketNamesReturnsOnCall[len(fake.listBucketNamesArgsForCall)]
+	fake.listBucketNamesArgsForCall = append(fake.listBucketNamesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListBucketNamesStub
+	fakeReturns := fake.listBucketNamesReturns
+	fake.recordInvocation("ListBucketNames", []interface{}{arg1})
+	fake.listBucketNamesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SavepointTx) ListBucketNamesCallCount() int {
+	fake.listBucketNamesMutex.RLock()
+	defer fake.listBucketNamesMutex.RUnlock()
+	return len(fake.listBucketNamesArgsForCall)
+}
+
+func (fake *SavepointTx) ListBucketNamesCalls(stub func(context.Context) (kv.BucketNames, error)) {
+	fake.listBucketNamesMutex.Lock()
+	defer fake.listBucketNamesMutex.Unlock()
+	fake.ListBucketNamesStub = stub
+}
+
+func (fake *SavepointTx) ListBucketNamesArgsForCall(i int) context.Context {
+	fake.listBucketNamesMutex.RLock()
+	defer fake.listBucketNamesMutex.RUnlock()
+	argsForCall := fake.listBucketNamesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SavepointTx) ListBucketNamesReturns(result1 kv.BucketNames, result2 error) {
+	fake.listBucketNamesMutex.Lock()
+	defer fake.listBucketNamesMutex.Unlock()
+	fake.ListBucketNamesStub = nil
+	fake.listBucketNamesReturns = struct {
+		result1 kv.BucketNames
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) ListBucketNamesReturnsOnCall(i int, result1 kv.BucketNames, result2 error) {
+	fake.listBucketNamesMutex.Lock()
+	defer fake.listBucketNamesMutex.Unlock()
+	fake.ListBucketNamesStub = nil
+	if fake.listBucketNamesReturnsOnCall == nil {
+		fake.listBucketNamesReturnsOnCall = make(map[int]struct {
+			result1 kv.BucketNames
+			result2 error
+		})
+	}
+	fake.listBucketNamesReturnsOnCall[i] = struct {
+		result1 kv.BucketNames
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) Release(arg1 context.Context, arg2 kv.Savepoint) error {
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.Savepoint
+	}{arg1, arg2})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SavepointTx) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *SavepointTx) ReleaseCalls(stub func(context.Context, kv.Savepoint) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *SavepointTx) ReleaseArgsForCall(i int) (context.Context, kv.Savepoint) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) RollbackTo(arg1 context.Context, arg2 kv.Savepoint) error {
+	fake.rollbackToMutex.Lock()
+	ret, specificReturn := fake.rollbackToReturnsOnCall[len(fake.rollbackToArgsForCall)]
+	fake.rollbackToArgsForCall = append(fake.rollbackToArgsForCall, struct {
+		arg1 context.Context
+		arg2 kv.Savepoint
+	}{arg1, arg2})
+	stub := fake.RollbackToStub
+	fakeReturns := fake.rollbackToReturns
+	fake.recordInvocation("RollbackTo", []interface{}{arg1, arg2})
+	fake.rollbackToMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SavepointTx) RollbackToCallCount() int {
+	fake.rollbackToMutex.RLock()
+	defer fake.rollbackToMutex.RUnlock()
+	return len(fake.rollbackToArgsForCall)
+}
+
+func (fake *SavepointTx) RollbackToCalls(stub func(context.Context, kv.Savepoint) error) {
+	fake.rollbackToMutex.Lock()
+	defer fake.rollbackToMutex.Unlock()
+	fake.RollbackToStub = stub
+}
+
+func (fake *SavepointTx) RollbackToArgsForCall(i int) (context.Context, kv.Savepoint) {
+	fake.rollbackToMutex.RLock()
+	defer fake.rollbackToMutex.RUnlock()
+	argsForCall := fake.rollbackToArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SavepointTx) RollbackToReturns(result1 error) {
+	fake.rollbackToMutex.Lock()
+	defer fake.rollbackToMutex.Unlock()
+	fake.RollbackToStub = nil
+	fake.rollbackToReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) RollbackToReturnsOnCall(i int, result1 error) {
+	fake.rollbackToMutex.Lock()
+	defer fake.rollbackToMutex.Unlock()
+	fake.RollbackToStub = nil
+	if fake.rollbackToReturnsOnCall == nil {
+		fake.rollbackToReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rollbackToReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SavepointTx) Savepoint(arg1 context.Context) (kv.Savepoint, error) {
+	fake.savepointMutex.Lock()
+	ret, specificReturn := fake.savepointReturnsOnCall[len(fake.savepointArgsForCall)]
+	fake.savepointArgsForCall = append(fake.savepointArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SavepointStub
+	fakeReturns := fake.savepointReturns
+	fake.recordInvocation("Savepoint", []interface{}{arg1})
+	fake.savepointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SavepointTx) SavepointCallCount() int {
+	fake.savepointMutex.RLock()
+	defer fake.savepointMutex.RUnlock()
+	return len(fake.savepointArgsForCall)
+}
+
+func (fake *SavepointTx) SavepointCalls(stub func(context.Context) (kv.Savepoint, error)) {
+	fake.savepointMutex.Lock()
+	defer fake.savepointMutex.Unlock()
+	fake.SavepointStub = stub
+}
+
+func (fake *SavepointTx) SavepointArgsForCall(i int) context.Context {
+	fake.savepointMutex.RLock()
+	defer fake.savepointMutex.RUnlock()
+	argsForCall := fake.savepointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SavepointTx) SavepointReturns(result1 kv.Savepoint, result2 error) {
+	fake.savepointMutex.Lock()
+	defer fake.savepointMutex.Unlock()
+	fake.SavepointStub = nil
+	fake.savepointReturns = struct {
+		result1 kv.Savepoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) SavepointReturnsOnCall(i int, result1 kv.Savepoint, result2 error) {
+	fake.savepointMutex.Lock()
+	defer fake.savepointMutex.Unlock()
+	fake.SavepointStub = nil
+	if fake.savepointReturnsOnCall == nil {
+		fake.savepointReturnsOnCall = make(map[int]struct {
+			result1 kv.Savepoint
+			result2 error
+		})
+	}
+	fake.savepointReturnsOnCall[i] = struct {
+		result1 kv.Savepoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SavepointTx) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SavepointTx) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.SavepointTx = new(SavepointTx)
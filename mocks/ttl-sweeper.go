@@ -0,0 +1,187 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/kv"
+)
+
+type TTLSweeper struct {
+	RunStub        func(context.Context) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 context.Context
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SweepStub        func(context.Context) (int, error)
+	sweepMutex       sync.RWMutex
+	sweepArgsForCall []struct {
+		arg1 context.Context
+	}
+	sweepReturns struct {
+		result1 int
+		result2 error
+	}
+	sweepReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *TTLSweeper) Run(arg1 context.Context) error {
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *TTLSweeper) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *TTLSweeper) RunCalls(stub func(context.Context) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *TTLSweeper) RunArgsForCall(i int) context.Context {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *TTLSweeper) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TTLSweeper) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TTLSweeper) Sweep(arg1 context.Context) (int, error) {
+	fake.sweepMutex.Lock()
+	ret, specificReturn := fake.sweepReturnsOnCall[len(fake.sweepArgsForCall)]
+	fake.sweepArgsForCall = append(fake.sweepArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SweepStub
+	fakeReturns := fake.sweepReturns
+	fake.recordInvocation("Sweep", []interface{}{arg1})
+	fake.sweepMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TTLSweeper) SweepCallCount() int {
+	fake.sweepMutex.RLock()
+	defer fake.sweepMutex.RUnlock()
+	return len(fake.sweepArgsForCall)
+}
+
+func (fake *TTLSweeper) SweepCalls(stub func(context.Context) (int, error)) {
+	fake.sweepMutex.Lock()
+	defer fake.sweepMutex.Unlock()
+	fake.SweepStub = stub
+}
+
+func (fake *TTLSweeper) SweepArgsForCall(i int) context.Context {
+	fake.sweepMutex.RLock()
+	defer fake.sweepMutex.RUnlock()
+	argsForCall := fake.sweepArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *TTLSweeper) SweepReturns(result1 int, result2 error) {
+	fake.sweepMutex.Lock()
+	defer fake.sweepMutex.Unlock()
+	fake.SweepStub = nil
+	fake.sweepReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TTLSweeper) SweepReturnsOnCall(i int, result1 int, result2 error) {
+	fake.sweepMutex.Lock()
+	defer fake.sweepMutex.Unlock()
+	fake.SweepStub = nil
+	if fake.sweepReturnsOnCall == nil {
+		fake.sweepReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.sweepReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TTLSweeper) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *TTLSweeper) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kv.TTLSweeper = new(TTLSweeper)